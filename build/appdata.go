@@ -84,6 +84,94 @@ func ExchangeRate() string {
 	return os.Getenv(siaExchangeRate)
 }
 
+// ContractMaintenanceMode returns the skydContractMaintenanceMode environment
+// variable if set.
+func ContractMaintenanceMode() (string, bool) {
+	return os.LookupEnv(skydContractMaintenanceMode)
+}
+
+// CooldownRecoveryFactor returns the skydCooldownRecoveryFactor environment
+// variable if set.
+func CooldownRecoveryFactor() (float64, bool) {
+	factorStr, ok := os.LookupEnv(skydCooldownRecoveryFactor)
+	if !ok {
+		return 0, false
+	}
+	var factor float64
+	_, err := fmt.Sscan(factorStr, &factor)
+	if err != nil {
+		Critical("failed to marshal SKYD_COOLDOWN_RECOVERY_FACTOR environment variable")
+		return 0, false
+	}
+	return factor, true
+}
+
+// DownloadMemory returns the skydDownloadMemory environment variable if set.
+func DownloadMemory() (uint64, bool) {
+	memStr, ok := os.LookupEnv(skydDownloadMemory)
+	if !ok {
+		return 0, false
+	}
+	var mem uint64
+	_, err := fmt.Sscan(memStr, &mem)
+	if err != nil {
+		Critical("failed to marshal SKYD_DOWNLOAD_MEMORY environment variable")
+		return 0, false
+	}
+	if mem == 0 {
+		Critical("SKYD_DOWNLOAD_MEMORY environment variable needs to be greater than 0")
+		return 0, false
+	}
+	return mem, true
+}
+
+// DownloadVariancePenalty returns the skydDownloadVariancePenalty environment
+// variable if set.
+func DownloadVariancePenalty() (float64, bool) {
+	penaltyStr, ok := os.LookupEnv(skydDownloadVariancePenalty)
+	if !ok {
+		return 0, false
+	}
+	var penalty float64
+	_, err := fmt.Sscan(penaltyStr, &penalty)
+	if err != nil {
+		Critical("failed to marshal SKYD_DOWNLOAD_VARIANCE_PENALTY environment variable")
+		return 0, false
+	}
+	return penalty, true
+}
+
+// MaxOldContracts returns the skydMaxOldContracts environment variable if set.
+func MaxOldContracts() (int, bool) {
+	maxStr, ok := os.LookupEnv(skydMaxOldContracts)
+	if !ok {
+		return 0, false
+	}
+	var max int
+	_, err := fmt.Sscan(maxStr, &max)
+	if err != nil {
+		Critical("failed to marshal SKYD_MAX_OLD_CONTRACTS environment variable")
+		return 0, false
+	}
+	return max, true
+}
+
+// PortalFormationThreads returns the skydPortalFormationThreads environment
+// variable if set.
+func PortalFormationThreads() (int, bool) {
+	threadsStr, ok := os.LookupEnv(skydPortalFormationThreads)
+	if !ok {
+		return 0, false
+	}
+	var threads int
+	_, err := fmt.Sscan(threadsStr, &threads)
+	if err != nil {
+		Critical("failed to marshal SKYD_PORTAL_FORMATION_THREADS environment variable")
+		return 0, false
+	}
+	return threads, true
+}
+
 // TUSMaxSize returns the tusMaxSize environment variable if set.
 func TUSMaxSize() (int64, bool) {
 	maxSizeStr, ok := os.LookupEnv(tusMaxSize)
@@ -24,6 +24,33 @@ var (
 	// show amounts (additionally) in a different currency
 	siaExchangeRate = "SIA_EXCHANGE_RATE"
 
+	// skydContractMaintenanceMode is the environment variable that sets how
+	// the contractor handles a contract maintenance trigger while another
+	// maintenance run is active.
+	skydContractMaintenanceMode = "SKYD_CONTRACT_MAINTENANCE_MODE"
+
+	// skydCooldownRecoveryFactor is the environment variable that sets the
+	// fraction of a worker's remaining maintenance cooldown that is dropped
+	// when one of its maintenance tasks succeeds.
+	skydCooldownRecoveryFactor = "SKYD_COOLDOWN_RECOVERY_FACTOR"
+
+	// skydDownloadMemory is the environment variable that sets the memory
+	// budget of user-initiated downloads in bytes.
+	skydDownloadMemory = "SKYD_DOWNLOAD_MEMORY"
+
+	// skydDownloadVariancePenalty is the environment variable that sets the
+	// penalty for the variance of a worker's read durations when picking the
+	// workers of a download.
+	skydDownloadVariancePenalty = "SKYD_DOWNLOAD_VARIANCE_PENALTY"
+
+	// skydMaxOldContracts is the environment variable that sets the max number
+	// of expired contracts the contractor keeps in memory.
+	skydMaxOldContracts = "SKYD_MAX_OLD_CONTRACTS"
+
+	// skydPortalFormationThreads is the environment variable that sets the
+	// number of threads a portal uses to form contracts in parallel.
+	skydPortalFormationThreads = "SKYD_PORTAL_FORMATION_THREADS"
+
 	// tusMaxSize determines the max size of an upload via the /tus endpoint.
 	tusMaxSize = "TUS_MAXSIZE"
 )
@@ -2130,6 +2130,7 @@ func renterworkerscmd() {
 	fmt.Fprintf(w, "  Workers On Download Cooldown:\t%v\n", rw.TotalDownloadCoolDown)
 	fmt.Fprintf(w, "  Workers On Upload Cooldown:\t%v\n", rw.TotalUploadCoolDown)
 	fmt.Fprintf(w, "  Workers On Maintenance Cooldown:\t%v\n", rw.TotalMaintenanceCoolDown)
+	fmt.Fprintf(w, "  Workers With Stale Price Tables:\t%v\n", rw.TotalStalePriceTables)
 	if err := w.Flush(); err != nil {
 		die("failed to flush writer:", err)
 	}
@@ -2266,10 +2267,11 @@ func renterworkersptcmd() {
 	// print summary
 	fmt.Fprintf(w, "Total Workers: \t%v\n", rw.NumWorkers)
 	fmt.Fprintf(w, "Workers Without Price Table: \t%v\n", workersWithoutPTs)
+	fmt.Fprintf(w, "Workers With Stale Price Table: \t%v\n", rw.TotalStalePriceTables)
 
 	// print header
 	hostInfo := "Host PubKey"
-	priceTableInfo := "\tActive\tExpiry\tUpdate"
+	priceTableInfo := "\tActive\tStale\tAge\tExpiry\tUpdate"
 	queueInfo := "\tErrorAt\tError"
 	header := hostInfo + priceTableInfo + queueInfo
 	fmt.Fprintln(w, "\nWorker Price Tables Detail  \n\n"+header)
@@ -2282,8 +2284,10 @@ func renterworkersptcmd() {
 		fmt.Fprintf(w, "%v", worker.HostPubKey.String())
 
 		// Price Table Info
-		fmt.Fprintf(w, "\t%t\t%s\t%s",
+		fmt.Fprintf(w, "\t%t\t%t\t%v\t%s\t%s",
 			pts.Active,
+			pts.Stale,
+			pts.Age.Round(time.Second),
 			sanitizeTime(pts.ExpiryTime, pts.Active),
 			sanitizeTime(pts.UpdateTime, pts.Active))
 
@@ -2312,7 +2316,7 @@ func renterworkersrjcmd() {
 
 	// print header
 	hostInfo := "Host PubKey"
-	queueInfo := "\tJobs\tAvgJobTime64k (ms)\tAvgJobTime1m (ms)\tAvgJobTime4m (ms)\tConsecFail\tErrorAt\tError"
+	queueInfo := "\tJobs\tAvgJobTime64k (ms)\tAvgJobTime1m (ms)\tAvgJobTime4m (ms)\tSuccessRate\tConsecFail\tErrorAt\tError"
 	header := hostInfo + queueInfo
 	fmt.Fprintln(w, "\nWorker Read Jobs  \n\n"+header)
 
@@ -2324,11 +2328,12 @@ func renterworkersrjcmd() {
 		fmt.Fprintf(w, "%v", worker.HostPubKey.String())
 
 		// ReadJobs Info
-		fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+		fmt.Fprintf(w, "\t%v\t%v\t%v\t%v\t%.2f\t%v\t%v\t%v\n",
 			rjs.JobQueueSize,
 			rjs.AvgJobTime64k,
 			rjs.AvgJobTime1m,
 			rjs.AvgJobTime4m,
+			rjs.SuccessRate,
 			rjs.ConsecutiveFailures,
 			sanitizeTime(rjs.RecentErrTime, rjs.RecentErr != ""),
 			sanitizeErr(rjs.RecentErr))
@@ -95,6 +95,100 @@ func (a *AllowanceRequestPost) WithMaxPeriodChurn(maxPeriodChurn uint64) *Allowa
 	return a
 }
 
+// WithMinContractFunding adds the mincontractfunding field to the request.
+func (a *AllowanceRequestPost) WithMinContractFunding(funding types.Currency) *AllowanceRequestPost {
+	a.values.Set("mincontractfunding", funding.String())
+	return a
+}
+
+// WithBackupHosts adds the backuphosts field to the request.
+func (a *AllowanceRequestPost) WithBackupHosts(hosts []types.SiaPublicKey) *AllowanceRequestPost {
+	pks := make([]string, 0, len(hosts))
+	for _, pk := range hosts {
+		pks = append(pks, pk.String())
+	}
+	a.values.Set("backuphosts", strings.Join(pks, ","))
+	return a
+}
+
+// WithBackupHostsThreshold adds the backuphoststhreshold field to the request.
+func (a *AllowanceRequestPost) WithBackupHostsThreshold(threshold float64) *AllowanceRequestPost {
+	a.values.Set("backuphoststhreshold", fmt.Sprint(threshold))
+	return a
+}
+
+// WithCancelSharedNetAddressContracts adds the
+// cancelsharednetaddresscontracts field to the request.
+func (a *AllowanceRequestPost) WithCancelSharedNetAddressContracts(cancel bool) *AllowanceRequestPost {
+	a.values.Set("cancelsharednetaddresscontracts", fmt.Sprint(cancel))
+	return a
+}
+
+// WithDuplicateContractStrategy adds the duplicatecontractstrategy field to
+// the request.
+func (a *AllowanceRequestPost) WithDuplicateContractStrategy(strategy skymodules.DuplicateContractStrategy) *AllowanceRequestPost {
+	a.values.Set("duplicatecontractstrategy", string(strategy))
+	return a
+}
+
+// WithAccountRefillLowWaterMark adds the accountrefilllowwatermark field to
+// the request.
+func (a *AllowanceRequestPost) WithAccountRefillLowWaterMark(low types.Currency) *AllowanceRequestPost {
+	a.values.Set("accountrefilllowwatermark", low.String())
+	return a
+}
+
+// WithAccountRefillHighWaterMark adds the accountrefillhighwatermark field to
+// the request.
+func (a *AllowanceRequestPost) WithAccountRefillHighWaterMark(high types.Currency) *AllowanceRequestPost {
+	a.values.Set("accountrefillhighwatermark", high.String())
+	return a
+}
+
+// WithRenewGraceBlocks adds the renewgraceblocks field to the request.
+func (a *AllowanceRequestPost) WithRenewGraceBlocks(grace types.BlockHeight) *AllowanceRequestPost {
+	a.values.Set("renewgraceblocks", fmt.Sprint(grace))
+	return a
+}
+
+// WithRenewGraceDataThreshold adds the renewgracedatathreshold field to the
+// request.
+func (a *AllowanceRequestPost) WithRenewGraceDataThreshold(threshold uint64) *AllowanceRequestPost {
+	a.values.Set("renewgracedatathreshold", fmt.Sprint(threshold))
+	return a
+}
+
+// WithDisableRenewFundingFloor adds the disablerenewfundingfloor field to the
+// request.
+func (a *AllowanceRequestPost) WithDisableRenewFundingFloor(disable bool) *AllowanceRequestPost {
+	a.values.Set("disablerenewfundingfloor", fmt.Sprint(disable))
+	return a
+}
+
+// WithPreferDiverseHosts adds the preferdiversehosts field to the request.
+func (a *AllowanceRequestPost) WithPreferDiverseHosts(prefer bool) *AllowanceRequestPost {
+	a.values.Set("preferdiversehosts", fmt.Sprint(prefer))
+	return a
+}
+
+// WithContractArchiveGrace adds the contractarchivegrace field to the request.
+func (a *AllowanceRequestPost) WithContractArchiveGrace(grace types.BlockHeight) *AllowanceRequestPost {
+	a.values.Set("contractarchivegrace", fmt.Sprint(grace))
+	return a
+}
+
+// WithScoreLeewayGFR adds the scoreleewaygfr field to the request.
+func (a *AllowanceRequestPost) WithScoreLeewayGFR(leeway uint64) *AllowanceRequestPost {
+	a.values.Set("scoreleewaygfr", fmt.Sprint(leeway))
+	return a
+}
+
+// WithScoreLeewayGFU adds the scoreleewaygfu field to the request.
+func (a *AllowanceRequestPost) WithScoreLeewayGFU(leeway uint64) *AllowanceRequestPost {
+	a.values.Set("scoreleewaygfu", fmt.Sprint(leeway))
+	return a
+}
+
 // WithMaxRPCPrice adds the maxrpcprice field to the request.
 func (a *AllowanceRequestPost) WithMaxRPCPrice(price types.Currency) *AllowanceRequestPost {
 	a.values.Set("maxrpcprice", price.String())
@@ -246,6 +340,15 @@ func (c *Client) RenterExpiredContractsGet() (rc api.RenterContracts, err error)
 	return
 }
 
+// RenterOrphanedContractsGet requests the /renter/contracts resource with the
+// orphaned flag set to true
+func (c *Client) RenterOrphanedContractsGet() (rc api.RenterContracts, err error) {
+	values := url.Values{}
+	values.Set("orphaned", fmt.Sprint(true))
+	err = c.get("/renter/contracts?"+values.Encode(), &rc)
+	return
+}
+
 // RenterRecoverableContractsGet requests the /renter/contracts resource with the
 // recoverable flag set to true
 func (c *Client) RenterRecoverableContractsGet() (rc api.RenterContracts, err error) {
@@ -477,6 +580,22 @@ func (c *Client) RenterPostAllowance(allowance skymodules.Allowance) error {
 	a = a.WithExpectedDownload(allowance.ExpectedDownload)
 	a = a.WithExpectedRedundancy(allowance.ExpectedRedundancy)
 	a = a.WithMaxPeriodChurn(allowance.MaxPeriodChurn)
+	a = a.WithMinContractFunding(allowance.MinContractFunding)
+	a = a.WithContractArchiveGrace(allowance.ContractArchiveGrace)
+	a = a.WithScoreLeewayGFR(allowance.ScoreLeewayGFR)
+	a = a.WithScoreLeewayGFU(allowance.ScoreLeewayGFU)
+	a = a.WithRenewGraceBlocks(allowance.RenewGraceBlocks)
+	a = a.WithRenewGraceDataThreshold(allowance.RenewGraceDataThreshold)
+	a = a.WithDisableRenewFundingFloor(allowance.DisableRenewFundingFloor)
+	a = a.WithPreferDiverseHosts(allowance.PreferDiverseHosts)
+	a = a.WithBackupHosts(allowance.BackupHosts)
+	a = a.WithBackupHostsThreshold(allowance.BackupHostsThreshold)
+	a = a.WithCancelSharedNetAddressContracts(allowance.CancelSharedNetAddressContracts)
+	if allowance.DuplicateContractStrategy != "" {
+		a = a.WithDuplicateContractStrategy(allowance.DuplicateContractStrategy)
+	}
+	a = a.WithAccountRefillLowWaterMark(allowance.AccountRefillLowWaterMark)
+	a = a.WithAccountRefillHighWaterMark(allowance.AccountRefillHighWaterMark)
 	a = a.WithPaymentContractInitialFunding(allowance.PaymentContractInitialFunding)
 	return a.Send()
 }
@@ -765,6 +884,29 @@ func (c *Client) RenterPost(values url.Values) (err error) {
 	return
 }
 
+// RenterWorkerCooldownGet uses the /renter/workers/cooldown/:pubkey endpoint
+// to get the maintenance cooldown status of the worker for the given host.
+func (c *Client) RenterWorkerCooldownGet(pk types.SiaPublicKey) (wmcs skymodules.WorkerMaintenanceCooldownStatus, err error) {
+	err = c.get("/renter/workers/cooldown/"+pk.String(), &wmcs)
+	return
+}
+
+// RenterWorkerCooldownPost uses the /renter/workers/cooldown/:pubkey endpoint
+// to clear the maintenance cooldown of the worker for the given host.
+func (c *Client) RenterWorkerCooldownPost(pk types.SiaPublicKey) (err error) {
+	err = c.post("/renter/workers/cooldown/"+pk.String(), "", nil)
+	return
+}
+
+// RenterWorkerBalanceTargetPost uses the /renter/workers/balancetarget/:pubkey
+// endpoint to set the balance target of the worker for the given host.
+func (c *Client) RenterWorkerBalanceTargetPost(pk types.SiaPublicKey, target types.Currency) (err error) {
+	values := url.Values{}
+	values.Set("target", target.String())
+	err = c.post("/renter/workers/balancetarget/"+pk.String(), values.Encode(), nil)
+	return
+}
+
 // RenterWorkersGet uses the /renter/workers endpoint to get the current status
 // of the renter's workers.
 func (c *Client) RenterWorkersGet() (wps skymodules.WorkerPoolStatus, err error) {
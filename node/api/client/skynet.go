@@ -1166,6 +1166,10 @@ func urlValuesFromSkyfileUploadParameters(sup skymodules.SkyfileUploadParameters
 	values.Set("mode", fmt.Sprintf("%o", sup.Mode))
 	values.Set("defaultpath", sup.DefaultPath)
 	values.Set("disabledefaultpath", strconv.FormatBool(sup.DisableDefaultPath))
+	values.Set("immutable", strconv.FormatBool(sup.Immutable))
+	if sup.ContentType != "" {
+		values.Set("contenttype", sup.ContentType)
+	}
 
 	b, err := json.Marshal(sup.TryFiles)
 	if err != nil {
@@ -87,7 +87,8 @@ type (
 		CurrentPeriod    types.BlockHeight             `json:"currentperiod"`
 		NextPeriod       types.BlockHeight             `json:"nextperiod"`
 
-		MemoryStatus skymodules.MemoryStatus `json:"memorystatus"`
+		MemoryStatus         skymodules.MemoryStatus         `json:"memorystatus"`
+		DownloadMemoryStatus skymodules.DownloadMemoryStatus `json:"downloadmemorystatus"`
 	}
 
 	// RenterContract represents a contract formed by the renter.
@@ -141,6 +142,9 @@ type (
 		GoodForRenew bool `json:"goodforrenew"`
 		// Signals if a contract has been marked as bad
 		BadContract bool `json:"badcontract"`
+		// Signals if a contract has ended but is kept in the active set until
+		// the allowance's contract archive grace period has passed
+		PendingArchive bool `json:"pendingarchive"`
 	}
 
 	// RenterContracts contains the renter's contracts.
@@ -156,6 +160,7 @@ type (
 		DisabledContracts         []RenterContract                 `json:"disabledcontracts"`
 		ExpiredContracts          []RenterContract                 `json:"expiredcontracts"`
 		ExpiredRefreshedContracts []RenterContract                 `json:"expiredrefreshedcontracts"`
+		OrphanedContracts         []RenterContract                 `json:"orphanedcontracts"`
 		RecoverableContracts      []skymodules.RecoverableContract `json:"recoverablecontracts"`
 	}
 
@@ -666,13 +671,19 @@ func (api *API) renterHandlerGET(w http.ResponseWriter, _ *http.Request, _ httpr
 		WriteError(w, Error{"unable to get renter memory information: " + err.Error()}, http.StatusBadRequest)
 		return
 	}
+	downloadMemoryStatus, err := api.renter.DownloadMemoryStatus()
+	if err != nil {
+		WriteError(w, Error{"unable to get renter download memory information: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
 	WriteJSON(w, RenterGET{
 		Settings:         settings,
 		FinancialMetrics: spending,
 		CurrentPeriod:    currentPeriod,
 		NextPeriod:       nextPeriod,
 
-		MemoryStatus: memoryStatus,
+		MemoryStatus:         memoryStatus,
+		DownloadMemoryStatus: downloadMemoryStatus,
 	})
 }
 
@@ -784,6 +795,122 @@ func (api *API) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ ht
 		settings.Allowance.MaxPeriodChurn = maxPeriodChurn
 		maxPeriodChurnSet = true
 	}
+	if str := req.FormValue("mincontractfunding"); str != "" {
+		funding, ok := scanAmount(str)
+		if !ok {
+			WriteError(w, Error{"unable to parse mincontractfunding"}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.MinContractFunding = funding
+	}
+	if str := req.FormValue("backuphosts"); str != "" {
+		var backupHosts []types.SiaPublicKey
+		for _, pkStr := range strings.Split(str, ",") {
+			var pk types.SiaPublicKey
+			if err := pk.LoadString(pkStr); err != nil {
+				WriteError(w, Error{"unable to parse backuphosts: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			backupHosts = append(backupHosts, pk)
+		}
+		settings.Allowance.BackupHosts = backupHosts
+	}
+	if str := req.FormValue("backuphoststhreshold"); str != "" {
+		var threshold float64
+		if _, err := fmt.Sscan(str, &threshold); err != nil {
+			WriteError(w, Error{"unable to parse backuphoststhreshold: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.BackupHostsThreshold = threshold
+	}
+	if str := req.FormValue("cancelsharednetaddresscontracts"); str != "" {
+		cancel, err := strconv.ParseBool(str)
+		if err != nil {
+			WriteError(w, Error{"unable to parse cancelsharednetaddresscontracts: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.CancelSharedNetAddressContracts = cancel
+	}
+	if str := req.FormValue("duplicatecontractstrategy"); str != "" {
+		strategy := skymodules.DuplicateContractStrategy(str)
+		if !strategy.IsValid() {
+			WriteError(w, Error{"unable to parse duplicatecontractstrategy: unknown strategy " + str}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.DuplicateContractStrategy = strategy
+	}
+	if str := req.FormValue("accountrefilllowwatermark"); str != "" {
+		low, ok := scanAmount(str)
+		if !ok {
+			WriteError(w, Error{"unable to parse accountrefilllowwatermark"}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.AccountRefillLowWaterMark = low
+	}
+	if str := req.FormValue("accountrefillhighwatermark"); str != "" {
+		high, ok := scanAmount(str)
+		if !ok {
+			WriteError(w, Error{"unable to parse accountrefillhighwatermark"}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.AccountRefillHighWaterMark = high
+	}
+	if str := req.FormValue("renewgraceblocks"); str != "" {
+		var grace types.BlockHeight
+		if _, err := fmt.Sscan(str, &grace); err != nil {
+			WriteError(w, Error{"unable to parse renewgraceblocks: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.RenewGraceBlocks = grace
+	}
+	if str := req.FormValue("renewgracedatathreshold"); str != "" {
+		var threshold uint64
+		if _, err := fmt.Sscan(str, &threshold); err != nil {
+			WriteError(w, Error{"unable to parse renewgracedatathreshold: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.RenewGraceDataThreshold = threshold
+	}
+	if str := req.FormValue("disablerenewfundingfloor"); str != "" {
+		disable, err := strconv.ParseBool(str)
+		if err != nil {
+			WriteError(w, Error{"unable to parse disablerenewfundingfloor: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.DisableRenewFundingFloor = disable
+	}
+	if str := req.FormValue("preferdiversehosts"); str != "" {
+		prefer, err := strconv.ParseBool(str)
+		if err != nil {
+			WriteError(w, Error{"unable to parse preferdiversehosts: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.PreferDiverseHosts = prefer
+	}
+	if str := req.FormValue("contractarchivegrace"); str != "" {
+		var grace types.BlockHeight
+		if _, err := fmt.Sscan(str, &grace); err != nil {
+			WriteError(w, Error{"unable to parse contractarchivegrace: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.ContractArchiveGrace = grace
+	}
+	if str := req.FormValue("scoreleewaygfr"); str != "" {
+		var leeway uint64
+		if _, err := fmt.Sscan(str, &leeway); err != nil {
+			WriteError(w, Error{"unable to parse scoreleewaygfr: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.ScoreLeewayGFR = leeway
+	}
+	if str := req.FormValue("scoreleewaygfu"); str != "" {
+		var leeway uint64
+		if _, err := fmt.Sscan(str, &leeway); err != nil {
+			WriteError(w, Error{"unable to parse scoreleewaygfu: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Allowance.ScoreLeewayGFU = leeway
+	}
 	if str := req.FormValue("maxrpcprice"); str != "" {
 		price, ok := scanAmount(str)
 		if !ok {
@@ -1055,11 +1182,13 @@ func (api *API) renterContractCancelHandler(w http.ResponseWriter, req *http.Req
 // ExpiredRefreshed contracts are refreshed contracts who's endheights are in
 // the past.
 //
+// Orphaned contracts are contracts whose hosts are no longer in the hostdb.
+//
 // Recoverable contracts are contracts of the renter that are recovered from the
 // blockchain by using the renter's seed.
 func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Parse flags
-	var disabled, inactive, expired, recoverable bool
+	var disabled, inactive, expired, orphaned, recoverable bool
 	var err error
 	if s := req.FormValue("disabled"); s != "" {
 		disabled, err = scanBool(s)
@@ -1082,6 +1211,13 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 			return
 		}
 	}
+	if s := req.FormValue("orphaned"); s != "" {
+		orphaned, err = scanBool(s)
+		if err != nil {
+			WriteError(w, Error{"unable to parse orphaned: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
 	if s := req.FormValue("recoverable"); s != "" {
 		recoverable, err = scanBool(s)
 		if err != nil {
@@ -1093,6 +1229,24 @@ func (api *API) renterContractsHandler(w http.ResponseWriter, req *http.Request,
 	// Parse the renter's contracts into their appropriate categories
 	contracts := api.parseRenterContracts(disabled, inactive, expired)
 
+	// Get orphaned contracts
+	if orphaned {
+		orphanedContracts, err := api.renter.OrphanedContracts()
+		if err != nil {
+			WriteError(w, Error{"unable to get orphaned contracts: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		orphanedIDs := make(map[types.FileContractID]struct{})
+		for _, c := range orphanedContracts {
+			orphanedIDs[c.ID] = struct{}{}
+		}
+		for _, c := range contracts.Contracts {
+			if _, exists := orphanedIDs[c.ID]; exists {
+				contracts.OrphanedContracts = append(contracts.OrphanedContracts, c)
+			}
+		}
+	}
+
 	// Get recoverable contracts
 	var recoverableContracts []skymodules.RecoverableContract
 	if recoverable {
@@ -1131,6 +1285,7 @@ func (api *API) parseRenterContracts(disabled, inactive, expired bool) RenterCon
 			LastTransaction:           c.Transaction,
 			NetAddress:                netAddress,
 			MaintenanceSpending:       c.MaintenanceSpending,
+			PendingArchive:            c.EndHeight < currentBlockHeight,
 			RenterFunds:               c.RenterFunds,
 			Size:                      c.Size(),
 			StartHeight:               c.StartHeight,
@@ -1873,6 +2028,39 @@ func parseDownloadParameters(w http.ResponseWriter, req *http.Request, ps httpro
 		}
 	}
 
+	// stickyworkers determines whether the download prefers the hosts that
+	// served the previous download of the same chunks.
+	var stickyWorkers bool
+	if str := req.FormValue("stickyworkers"); str != "" {
+		stickyWorkers, err = scanBool(str)
+		if err != nil {
+			return skymodules.RenterDownloadParameters{}, errors.AddContext(err, "error parsing the stickyworkers flag")
+		}
+	}
+
+	// minoverdrive and maxoverdrive bound the number of extra pieces that are
+	// downloaded for each chunk. If maxoverdrive isn't set, the renter's
+	// defaults are used.
+	var minOverdrive, maxOverdrive uint64
+	if str := req.FormValue("minoverdrive"); str != "" {
+		_, err = fmt.Sscan(str, &minOverdrive)
+		if err != nil {
+			return skymodules.RenterDownloadParameters{}, errors.AddContext(err, "error parsing the minoverdrive parameter")
+		}
+	}
+	if str := req.FormValue("maxoverdrive"); str != "" {
+		_, err = fmt.Sscan(str, &maxOverdrive)
+		if err != nil {
+			return skymodules.RenterDownloadParameters{}, errors.AddContext(err, "error parsing the maxoverdrive parameter")
+		}
+	}
+	if maxOverdrive == 0 && minOverdrive != 0 {
+		return skymodules.RenterDownloadParameters{}, errors.New("minoverdrive can't be set without maxoverdrive")
+	}
+	if minOverdrive > maxOverdrive {
+		return skymodules.RenterDownloadParameters{}, errors.New("minoverdrive can't be larger than maxoverdrive")
+	}
+
 	dp := skymodules.RenterDownloadParameters{
 		Destination:      destination,
 		DisableDiskFetch: disableLocalFetch,
@@ -1880,6 +2068,9 @@ func parseDownloadParameters(w http.ResponseWriter, req *http.Request, ps httpro
 		Length:           length,
 		Offset:           offset,
 		SiaPath:          siaPath,
+		StickyWorkers:    stickyWorkers,
+		MinOverdrive:     minOverdrive,
+		MaxOverdrive:     maxOverdrive,
 	}
 	if httpresp {
 		dp.Httpwriter = w
@@ -2301,6 +2492,62 @@ func (api *API) renterContractStatusHandler(w http.ResponseWriter, req *http.Req
 	WriteJSON(w, contractStatus)
 }
 
+// renterWorkerCooldownHandlerGET handles the API call to get the maintenance
+// cooldown status of a single worker.
+func (api *API) renterWorkerCooldownHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	var pk types.SiaPublicKey
+	err := pk.LoadString(ps.ByName("pubkey"))
+	if err != nil {
+		WriteError(w, Error{"unable to parse host public key: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	status, err := api.renter.WorkerMaintenanceCooldownStatus(pk)
+	if err != nil {
+		WriteError(w, Error{"unable to get worker cooldown status: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteJSON(w, status)
+}
+
+// renterWorkerCooldownHandlerPOST handles the API call to clear the
+// maintenance cooldown of a single worker.
+func (api *API) renterWorkerCooldownHandlerPOST(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	var pk types.SiaPublicKey
+	err := pk.LoadString(ps.ByName("pubkey"))
+	if err != nil {
+		WriteError(w, Error{"unable to parse host public key: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = api.renter.ClearWorkerCooldown(pk)
+	if err != nil {
+		WriteError(w, Error{"unable to clear worker cooldown: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
+// renterWorkerBalanceTargetHandlerPOST handles the API call to set the balance
+// target of a single worker's ephemeral account.
+func (api *API) renterWorkerBalanceTargetHandlerPOST(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var pk types.SiaPublicKey
+	err := pk.LoadString(ps.ByName("pubkey"))
+	if err != nil {
+		WriteError(w, Error{"unable to parse host public key: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	target, ok := scanAmount(req.FormValue("target"))
+	if !ok {
+		WriteError(w, Error{"unable to parse target"}, http.StatusBadRequest)
+		return
+	}
+	err = api.renter.SetWorkerBalanceTarget(pk, target)
+	if err != nil {
+		WriteError(w, Error{"unable to set worker balance target: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	WriteSuccess(w)
+}
+
 // renterWorkersHandler handles the API call to check the status of the renter's
 // workers
 func (api *API) renterWorkersHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
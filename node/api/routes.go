@@ -123,6 +123,9 @@ func (api *API) buildHTTPRoutes() {
 		router.POST("/renter/uploadstream/*siapath", RequirePassword(api.renterUploadStreamHandler, requiredPassword))
 		router.POST("/renter/validatesiapath/*siapath", RequirePassword(api.renterValidateSiaPathHandler, requiredPassword))
 		router.GET("/renter/workers", api.renterWorkersHandler)
+		router.GET("/renter/workers/cooldown/:pubkey", api.renterWorkerCooldownHandlerGET)
+		router.POST("/renter/workers/cooldown/:pubkey", RequirePassword(api.renterWorkerCooldownHandlerPOST, requiredPassword))
+		router.POST("/renter/workers/balancetarget/:pubkey", RequirePassword(api.renterWorkerBalanceTargetHandlerPOST, requiredPassword))
 
 		// Skynet endpoints
 		router.GET("/skynet/basesector/*skylink", api.skynetBaseSectorHandlerGET)
@@ -41,6 +41,11 @@ const (
 	// high timeouts.
 	MaxSkynetRequestTimeout = 15 * time.Minute
 
+	// immutableCacheMaxAge is the max-age in seconds of the Cache-Control
+	// header that is set when serving immutable skyfiles. It equals one year,
+	// which is the maximum recommended by RFC 7234.
+	immutableCacheMaxAge = 31536000
+
 	// SkynetDisableForceHeader allows disabling the force-update feature.
 	SkynetDisableForceHeader = "Skynet-Disable-Force"
 
@@ -65,13 +70,6 @@ const (
 	SkynetRequestedSkylinkHeader = "Skynet-Requested-Skylink"
 )
 
-var (
-	// DefaultSkynetPricePerMS is the default price per millisecond the renter
-	// is able to spend on faster workers when downloading a Skyfile. By default
-	// this is a sane default of 100 nS.
-	DefaultSkynetPricePerMS = types.SiacoinPrecision.MulFloat(1e-7) // 100 nS
-)
-
 type (
 	// HostsForRegistryUpdateGET is the response that the api returns after
 	// a request to /skynet/registry/hosts.
@@ -274,7 +272,7 @@ func (api *API) skynetBaseSectorHandlerGET(w http.ResponseWriter, req *http.Requ
 	}
 
 	// Parse pricePerMS.
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := queryForm.Get("priceperms")
 	if pricePerMSStr != "" {
 		_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
@@ -463,7 +461,7 @@ func (api *API) skynetRootHandlerGET(w http.ResponseWriter, req *http.Request, _
 	}
 
 	// Parse pricePerMS.
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := queryForm.Get("priceperms")
 	if pricePerMSStr != "" {
 		_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
@@ -603,6 +601,15 @@ func (api *API) skynetSkylinkHandlerGET(w http.ResponseWriter, req *http.Request
 	eTag := buildETag(streamer.Skylink(), path, format)
 	w.Header().Set("ETag", fmt.Sprintf("\"%v\"", eTag))
 
+	// Set the Cache-Control response header for immutable skyfiles once the
+	// content is served successfully. Errors must not be cached forever.
+	if cc := immutableCacheControl(params.skylink, metadata); cc != "" {
+		w = &cacheControlResponseWriter{
+			staticInner:        w,
+			staticCacheControl: cc,
+		}
+	}
+
 	// Set the Layout
 	if params.includeLayout {
 		w.Header().Set(SkynetFileLayoutHeader, hex.EncodeToString(encLayout))
@@ -689,7 +696,7 @@ func (api *API) skynetSkylinkPinHandlerPOST(w http.ResponseWriter, req *http.Req
 	}
 
 	// Parse pricePerMS.
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := queryForm.Get("priceperms")
 	if pricePerMSStr != "" {
 		_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
@@ -816,8 +823,10 @@ func (api *API) skynetSkyfileHandlerPOST(w http.ResponseWriter, req *http.Reques
 		SkykeyName: params.skyKeyName,
 		SkykeyID:   params.skyKeyID,
 
-		TryFiles:   params.tryFiles,
-		ErrorPages: params.errorPages,
+		TryFiles:    params.tryFiles,
+		ErrorPages:  params.errorPages,
+		Immutable:   params.immutable,
+		ContentType: params.contentType,
 	}
 
 	// set the reader
@@ -1561,7 +1570,7 @@ func (api *API) skynetMetadataHandlerGET(w http.ResponseWriter, req *http.Reques
 	}
 
 	// Parse pricePerMS.
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := queryForm.Get("priceperms")
 	if pricePerMSStr != "" {
 		_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
@@ -1652,7 +1661,7 @@ func (api *API) skynetSkylinkHealthGET(w http.ResponseWriter, req *http.Request,
 	defer cancel()
 
 	// Get health.
-	sh, err := api.renter.SkylinkHealth(ctx, skylink, DefaultSkynetPricePerMS)
+	sh, err := api.renter.SkylinkHealth(ctx, skylink, skymodules.DefaultSkynetPricePerMS)
 	if err != nil {
 		handleSkynetError(w, "failed to get skylink health", err)
 		return
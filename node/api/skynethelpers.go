@@ -65,6 +65,7 @@ type (
 	// string parameters on upload
 	skyfileUploadParams struct {
 		baseChunkRedundancy uint8
+		contentType         string
 		defaultPath         string
 		convertPath         string
 		disableDefaultPath  bool
@@ -73,6 +74,7 @@ type (
 		dryRun              bool
 		filename            string
 		force               bool
+		immutable           bool
 		mode                os.FileMode
 		root                bool
 		siaPath             skymodules.SiaPath
@@ -121,6 +123,42 @@ func (rw *monetizedResponseWriter) Write(b []byte) (int, error) {
 	return rw.staticW.Write(b)
 }
 
+// cacheControlResponseWriter is a wrapper for a response writer. It sets the
+// Cache-Control header only for successful responses.
+type cacheControlResponseWriter struct {
+	staticInner        http.ResponseWriter
+	staticCacheControl string
+
+	wroteHeader bool
+}
+
+// Header calls the inner writers Header method.
+func (rw *cacheControlResponseWriter) Header() http.Header {
+	return rw.staticInner.Header()
+}
+
+// WriteHeader sets the Cache-Control header if the status code indicates a
+// successful response and calls the inner writers WriteHeader method.
+func (rw *cacheControlResponseWriter) WriteHeader(statusCode int) {
+	if !rw.wroteHeader {
+		rw.wroteHeader = true
+		switch statusCode {
+		case http.StatusOK, http.StatusPartialContent, http.StatusNotModified:
+			rw.staticInner.Header().Set("Cache-Control", rw.staticCacheControl)
+		}
+	}
+	rw.staticInner.WriteHeader(statusCode)
+}
+
+// Write writes to the inner writer. Just like a regular response writer, it
+// implicitly writes a http.StatusOK header if none was written yet.
+func (rw *cacheControlResponseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.staticInner.Write(b)
+}
+
 // newCustomErrorWriter creates a new customErrorWriter.
 func newCustomErrorWriter(meta skymodules.SkyfileMetadata, streamer io.ReadSeeker) *customErrorWriter {
 	if meta.ErrorPages == nil {
@@ -325,7 +363,7 @@ func parseDownloadRequestParameters(req *http.Request) (*skyfileDownloadParams,
 	}
 
 	// Parse pricePerMS.
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := queryForm.Get("priceperms")
 	if pricePerMSStr != "" {
 		_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
@@ -416,6 +454,9 @@ func parseUploadHeadersAndRequestParameters(req *http.Request, ps httprouter.Par
 		}
 	}
 
+	// parse 'contenttype' query parameter
+	contentType := queryForm.Get("contenttype")
+
 	// parse 'convertpath' query parameter
 	convertPath := queryForm.Get("convertpath")
 
@@ -485,6 +526,16 @@ func parseUploadHeadersAndRequestParameters(req *http.Request, ps httprouter.Par
 		}
 	}
 
+	// parse 'immutable' query parameter
+	var immutable bool
+	immutableStr := queryForm.Get("immutable")
+	if immutableStr != "" {
+		immutable, err = strconv.ParseBool(immutableStr)
+		if err != nil {
+			return nil, nil, errors.AddContext(err, "unable to parse 'immutable' parameter")
+		}
+	}
+
 	// parse 'mode' query parameter
 	modeStr := queryForm.Get("mode")
 	var mode os.FileMode
@@ -569,6 +620,7 @@ func parseUploadHeadersAndRequestParameters(req *http.Request, ps httprouter.Par
 	}
 	params := &skyfileUploadParams{
 		baseChunkRedundancy: baseChunkRedundancy,
+		contentType:         contentType,
 		convertPath:         convertPath,
 		defaultPath:         defaultPath,
 		disableDefaultPath:  disableDefaultPath,
@@ -576,6 +628,7 @@ func parseUploadHeadersAndRequestParameters(req *http.Request, ps httprouter.Par
 		errorPages:          errPages,
 		filename:            filename,
 		force:               force,
+		immutable:           immutable,
 		mode:                mode,
 		root:                root,
 		siaPath:             siaPath,
@@ -586,6 +639,17 @@ func parseUploadHeadersAndRequestParameters(req *http.Request, ps httprouter.Par
 	return headers, params, nil
 }
 
+// immutableCacheControl returns the Cache-Control header value for a skyfile
+// that is declared immutable in its metadata. It returns an empty string if
+// the skyfile isn't immutable or if it was requested through a V2 skylink,
+// since a V2 skylink can be updated to point to different content.
+func immutableCacheControl(requested skymodules.Skylink, md skymodules.SkyfileMetadata) string {
+	if !md.Immutable || !requested.IsSkylinkV1() {
+		return ""
+	}
+	return fmt.Sprintf("public, max-age=%d, immutable", immutableCacheMaxAge)
+}
+
 // serveArchive serves skyfiles as an archive by reading them from r and writing
 // the archive to dst using the given archiveFunc.
 func serveArchive(w http.ResponseWriter, src io.ReadSeeker, format skymodules.SkyfileFormat, md skymodules.SkyfileMetadata) (err error) {
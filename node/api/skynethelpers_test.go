@@ -21,6 +21,7 @@ import (
 	"gitlab.com/SkynetLabs/skyd/skykey"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
 
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"gitlab.com/SkynetLabs/skyd/skymodules/renter"
@@ -132,6 +133,8 @@ func TestHandleSkynetError(t *testing.T) {
 // TestSkynet` from the command line.
 func TestSkynetHelpers(t *testing.T) {
 	t.Run("BuildETag", testBuildETag)
+	t.Run("CacheControlResponseWriter", testCacheControlResponseWriter)
+	t.Run("ImmutableCacheControl", testImmutableCacheControl)
 	t.Run("ParseSkylinkURL", testParseSkylinkURL)
 	t.Run("ParseUploadRequestParameters", testParseUploadRequestParameters)
 	t.Run("ParseDownloadRequestParameters", testParseDownloadRequestParameters)
@@ -180,6 +183,80 @@ func testBuildETag(t *testing.T) {
 	}
 }
 
+// testCacheControlResponseWriter verifies that the cacheControlResponseWriter
+// only sets the Cache-Control header for successful responses.
+func testCacheControlResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	cc := "public, max-age=31536000, immutable"
+	tests := []struct {
+		statusCode int
+		cached     bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusPartialContent, true},
+		{http.StatusNotModified, true},
+		{http.StatusNotFound, false},
+		{http.StatusRequestedRangeNotSatisfiable, false},
+		{http.StatusInternalServerError, false},
+	}
+	for _, test := range tests {
+		tw := newTestHTTPWriter()
+		rw := &cacheControlResponseWriter{staticInner: tw, staticCacheControl: cc}
+		rw.WriteHeader(test.statusCode)
+		if tw.statusCode != test.statusCode {
+			t.Fatal("unexpected status code", tw.statusCode, test.statusCode)
+		}
+		var expected string
+		if test.cached {
+			expected = cc
+		}
+		if got := tw.header.Get("Cache-Control"); got != expected {
+			t.Fatalf("unexpected Cache-Control header for status %v: '%v'", test.statusCode, got)
+		}
+	}
+
+	// writing without writing a header first is a successful response.
+	tw := newTestHTTPWriter()
+	rw := &cacheControlResponseWriter{staticInner: tw, staticCacheControl: cc}
+	if _, err := rw.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if tw.statusCode != http.StatusOK || tw.header.Get("Cache-Control") != cc {
+		t.Fatal("unexpected response", tw.statusCode, tw.header.Get("Cache-Control"))
+	}
+}
+
+// testImmutableCacheControl verifies the functionality of the
+// immutableCacheControl helper function
+func testImmutableCacheControl(t *testing.T) {
+	t.Parallel()
+
+	var skylink skymodules.Skylink
+	err := skylink.LoadString("AACogzrAimYPG42tDOKhS3lXZD8YvlF8Q8R17afe95iV2Q")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mutable skyfile
+	md := skymodules.SkyfileMetadata{Filename: "foo"}
+	if cc := immutableCacheControl(skylink, md); cc != "" {
+		t.Fatal("unexpected output", cc)
+	}
+
+	// immutable skyfile
+	md.Immutable = true
+	if cc := immutableCacheControl(skylink, md); cc != "public, max-age=31536000, immutable" {
+		t.Fatal("unexpected output", cc)
+	}
+
+	// immutable skyfile requested through a V2 skylink
+	skylinkV2 := skymodules.NewSkylinkV2(types.SiaPublicKey{}, crypto.Hash{})
+	if cc := immutableCacheControl(skylinkV2, md); cc != "" {
+		t.Fatal("unexpected output", cc)
+	}
+}
+
 // testParseSkylinkURL is a table test for the parseSkylinkUrl function.
 func testParseSkylinkURL(t *testing.T) {
 	tests := []struct {
@@ -549,6 +626,26 @@ func testParseUploadRequestParameters(t *testing.T) {
 		t.Fatal("Unexpected")
 	}
 
+	// verify 'contenttype'
+	req = buildRequest(url.Values{"contenttype": []string{"image/png"}}, http.Header{"Content-type": []string{"text/html"}})
+	_, params, err = parseRequest(req, defaultParams)
+	if err != nil {
+		t.Fatal("Unexpected error", err)
+	}
+	if params.contentType != "image/png" {
+		t.Fatal("Unexpected")
+	}
+
+	// verify 'immutable'
+	req = buildRequest(url.Values{"immutable": trueStr}, http.Header{"Content-type": []string{"text/html"}})
+	_, params, err = parseRequest(req, defaultParams)
+	if err != nil {
+		t.Fatal("Unexpected error", err)
+	}
+	if !params.immutable {
+		t.Fatal("Unexpected")
+	}
+
 	// verify 'mode'
 	req = buildRequest(url.Values{"mode": []string{fmt.Sprintf("%o", os.FileMode(0644))}}, http.Header{"Content-type": []string{"text/html"}})
 	_, params, err = parseRequest(req, defaultParams)
@@ -660,7 +757,7 @@ func testParseDownloadRequestParameters(t *testing.T) {
 	baseParams := func() *skyfileDownloadParams {
 		return &skyfileDownloadParams{
 			path:                 "/",
-			pricePerMS:           DefaultSkynetPricePerMS,
+			pricePerMS:           skymodules.DefaultSkynetPricePerMS,
 			skylink:              skylink,
 			skylinkStringNoQuery: skylinkStr,
 			timeout:              DefaultSkynetRequestTimeout,
@@ -766,7 +863,7 @@ func testParseDownloadRequestParameters(t *testing.T) {
 	}
 
 	// Test pricePerMS
-	pricePerMS := DefaultSkynetPricePerMS
+	pricePerMS := skymodules.DefaultSkynetPricePerMS
 	pricePerMSStr := "1000"
 	_, err = fmt.Sscan(pricePerMSStr, &pricePerMS)
 	if err != nil {
@@ -296,6 +296,13 @@ func NewDependencyDisableCommitPaymentIntent() *DependencyWithDisableAndEnable {
 	return newDependencywithDisableAndEnable("DisableCommitPaymentIntent")
 }
 
+// NewDependencyInterruptAccountsUpgradeCopy creates a new dependency that
+// interrupts the accounts upgrade in the middle of copying the upgraded
+// accounts from the tmp accounts file to the accounts file.
+func NewDependencyInterruptAccountsUpgradeCopy() *DependencyInterruptOnceOnKeyword {
+	return newDependencyInterruptOnceOnKeyword("InterruptAccountsUpgradeCopy")
+}
+
 // NewDependencyInterruptContractSaveToDiskAfterDeletion creates a new
 // dependency that interrupts the contract being saved to disk after being
 // removed from static contracts
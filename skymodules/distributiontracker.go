@@ -22,6 +22,7 @@ package skymodules
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -416,6 +417,26 @@ func (d *Distribution) Shift(dur time.Duration) {
 	}
 }
 
+// StandardDeviation returns the standard deviation of the durations in the
+// distribution. If no data was collected, 0 is returned.
+func (d *Distribution) StandardDeviation() time.Duration {
+	total := d.DataPoints()
+	if total == 0 {
+		return 0
+	}
+	expected := float64(d.ExpectedDuration())
+
+	// Across all buckets, multiply the pct chance times the squared
+	// difference between the bucket's duration and the expected duration.
+	var variance float64
+	for i := 0; i < len(d.timings); i++ {
+		pct := d.timings[i] / total
+		diff := float64(DistributionDurationForBucketIndex(i)) - expected
+		variance += pct * diff * diff
+	}
+	return time.Duration(math.Sqrt(variance))
+}
+
 // HalfLife returns this distribution's half file.
 func (d *Distribution) HalfLife() time.Duration {
 	return d.staticHalfLife
@@ -26,6 +26,7 @@ func TestDistributionTracker(t *testing.T) {
 	t.Run("Helpers", testDistributionHelpers)
 	t.Run("MergeWith", testDistributionMergeWith)
 	t.Run("Shift", testDistributionShift)
+	t.Run("StandardDeviation", testDistributionStandardDeviation)
 }
 
 // testDistributionBucketing will check that the distribution is placing timings
@@ -975,3 +976,27 @@ func testDistributionHelpers(t *testing.T) {
 		t.Error("bad", index, fraction)
 	}
 }
+
+// testDistributionStandardDeviation verifies the standard deviation of a
+// distribution is computed correctly.
+func testDistributionStandardDeviation(t *testing.T) {
+	t.Parallel()
+
+	// no data points
+	d := NewDistribution(time.Minute * 100)
+	if d.StandardDeviation() != 0 {
+		t.Fatal("unexpected", d.StandardDeviation())
+	}
+
+	// a single data point
+	d.AddDataPoint(8 * time.Millisecond)
+	if d.StandardDeviation() != 0 {
+		t.Fatal("unexpected", d.StandardDeviation())
+	}
+
+	// two data points 8ms apart
+	d.AddDataPoint(16 * time.Millisecond)
+	if d.StandardDeviation() != 4*time.Millisecond {
+		t.Fatal("unexpected", d.StandardDeviation())
+	}
+}
@@ -92,6 +92,10 @@ var (
 // build stream buffers.
 type DataSourceID crypto.Hash
 
+// DuplicateContractStrategy is the strategy the contractor uses to decide which
+// of two contracts with the same host to keep.
+type DuplicateContractStrategy string
+
 // FilterMode is the helper type for the enum constants for the HostDB filter
 // mode
 type FilterMode int
@@ -231,6 +235,23 @@ const (
 	HostDBActiveWhitelist
 )
 
+// DuplicateContractStrategyStartHeight, DuplicateContractStrategyDataStored
+// and DuplicateContractStrategyRenterFunds are the strategies the contractor
+// can use to decide which of two contracts with the same host to keep.
+const (
+	// DuplicateContractStrategyStartHeight keeps the contract with the higher
+	// start height.
+	DuplicateContractStrategyStartHeight DuplicateContractStrategy = "startheight"
+
+	// DuplicateContractStrategyDataStored keeps the contract that stores more
+	// data.
+	DuplicateContractStrategyDataStored DuplicateContractStrategy = "datastored"
+
+	// DuplicateContractStrategyRenterFunds keeps the contract with more
+	// remaining renter funds.
+	DuplicateContractStrategyRenterFunds DuplicateContractStrategy = "renterfunds"
+)
+
 // Filesystem related consts.
 const (
 	// DefaultDirPerm defines the default permissions used for a new dir if no
@@ -276,6 +297,17 @@ func (fm *FilterMode) FromString(s string) error {
 	return nil
 }
 
+// IsValid returns true if the strategy is either empty or one of the known
+// strategies.
+func (s DuplicateContractStrategy) IsValid() bool {
+	switch s {
+	case "", DuplicateContractStrategyStartHeight, DuplicateContractStrategyDataStored, DuplicateContractStrategyRenterFunds:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsHostsFault indicates if a returned error is the host's fault.
 func IsHostsFault(err error) bool {
 	return errors.Contains(err, ErrHostFault)
@@ -368,6 +400,84 @@ type Allowance struct {
 	// period.
 	MaxPeriodChurn uint64 `json:"maxperiodchurn"`
 
+	// MinContractFunding is an absolute floor for the amount of money put into
+	// a newly formed contract. It takes precedence over the minimum and
+	// maximum funding derived from dividing the allowance funds by the number
+	// of hosts. If this value is zero, no floor is applied.
+	MinContractFunding types.Currency `json:"mincontractfunding"`
+
+	// ContractArchiveGrace is the number of blocks past a contract's
+	// EndHeight for which the ended contract remains in the active contract
+	// set before it is archived. This keeps ended contracts queryable for
+	// dispute resolution. If this value is zero, contracts are archived as
+	// soon as they end.
+	ContractArchiveGrace types.BlockHeight `json:"contractarchivegrace"`
+
+	// ScoreLeewayGFR and ScoreLeewayGFU are the factors by which the score of
+	// a host can be lower than the lowest score of a freshly sampled set of
+	// hosts before the host's contract is marked !GoodForRenew or
+	// !GoodForUpload respectively. Larger values tolerate worse hosts and
+	// cause less churn. If a value is zero, the contractor's default is used.
+	ScoreLeewayGFR uint64 `json:"scoreleewaygfr"`
+	ScoreLeewayGFU uint64 `json:"scoreleewaygfu"`
+
+	// RenewGraceBlocks delays the point at which the contractor gives up on
+	// renewing a contract after too many failed attempts. By default it gives
+	// up once the contract reaches the second half of its renew window. For
+	// contracts that store at least RenewGraceDataThreshold bytes, it keeps
+	// trying for RenewGraceBlocks more blocks, so valuable data isn't
+	// abandoned due to a transient host issue. RenewGraceBlocks must be less
+	// than half the renew window. If it is zero, no grace is applied.
+	RenewGraceBlocks        types.BlockHeight `json:"renewgraceblocks"`
+	RenewGraceDataThreshold uint64            `json:"renewgracedatathreshold"`
+
+	// DisableRenewFundingFloor disables the floor of the renew funding
+	// estimate for contracts that store data. By default, the funding of a
+	// renewed contract is at least the initial funding of a new contract.
+	// Contracts that store data have a usage history the estimate is based
+	// on, so with this field set, their funding tracks the actual usage more
+	// closely. Empty contracts still use the floor.
+	DisableRenewFundingFloor bool `json:"disablerenewfundingfloor"`
+
+	// PreferDiverseHosts makes the contractor prefer forming contracts with
+	// hosts whose networks aren't used by the hosts it already has contracts
+	// with, instead of forming contracts in the random order returned by the
+	// hostdb. This spreads the contracts across more networks, which tend to
+	// be in different locations.
+	PreferDiverseHosts bool `json:"preferdiversehosts"`
+
+	// BackupHosts is a set of hosts the contractor only forms contracts with
+	// when the number of good for upload contracts with other hosts drops
+	// below BackupHostsThreshold * Hosts. Once the other hosts recover, the
+	// contracts with the backup hosts are dropped again.
+	BackupHosts []types.SiaPublicKey `json:"backuphosts"`
+
+	// BackupHostsThreshold is the fraction of Hosts below which the
+	// contractor starts using the BackupHosts.
+	BackupHostsThreshold float64 `json:"backuphoststhreshold"`
+
+	// CancelSharedNetAddressContracts controls how the contractor handles
+	// contracts with different hosts that share the same net address. These
+	// hosts are most likely the same machine with a rotated public key, so
+	// storing data on more than one of them is redundant. The redundant
+	// contracts are always logged. If this field is set, they are canceled
+	// as well.
+	CancelSharedNetAddressContracts bool `json:"cancelsharednetaddresscontracts"`
+
+	// DuplicateContractStrategy decides which contract survives if the
+	// contractor finds more than one contract with the same host. If it is
+	// empty, the contract with the higher start height is kept.
+	DuplicateContractStrategy DuplicateContractStrategy `json:"duplicatecontractstrategy"`
+
+	// AccountRefillLowWaterMark and AccountRefillHighWaterMark control the
+	// refills of the workers' ephemeral accounts. Once an account's balance
+	// drops below the low water mark, the worker refills it up to the high
+	// water mark. If the high water mark is zero, the worker's default
+	// balance target is used. If the low water mark is zero, half of the
+	// high water mark is used.
+	AccountRefillLowWaterMark  types.Currency `json:"accountrefilllowwatermark"`
+	AccountRefillHighWaterMark types.Currency `json:"accountrefillhighwatermark"`
+
 	// The following fields provide price gouging protection for the user. By
 	// setting a particular maximum price for each mechanism that a host can use
 	// to charge users, the workers know to avoid hosts that go outside of the
@@ -398,6 +508,17 @@ func (a Allowance) Active() bool {
 	return a.Period != 0
 }
 
+// IsBackupHost returns true if the host with the given public key is one of
+// the allowance's backup hosts.
+func (a Allowance) IsBackupHost(pk types.SiaPublicKey) bool {
+	for _, bh := range a.BackupHosts {
+		if bh.Equals(pk) {
+			return true
+		}
+	}
+	return false
+}
+
 // PortalMode returns true if the renter is supposed to act as a portal.
 func (a Allowance) PortalMode() bool {
 	return !a.PaymentContractInitialFunding.IsZero()
@@ -449,6 +570,7 @@ type ContractWatchStatus struct {
 	DoubleSpendHeight         types.BlockHeight `json:"doublespendheight"`
 	WindowStart               types.BlockHeight `json:"windowstart"`
 	WindowEnd                 types.BlockHeight `json:"windowend"`
+	Note                      string            `json:"note"`
 }
 
 // DirectoryInfo provides information about a siadir
@@ -674,6 +796,37 @@ type HostScoreBreakdown struct {
 	VersionAdjustment          float64 `json:"versionadjustment"`
 }
 
+// DownloadForecast contains the expected completion times of a download at
+// different percentiles.
+type DownloadForecast struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+}
+
+// DownloadMemoryStatus contains information about the memory budget that is
+// shared by all user-initiated downloads and streamer caches.
+type DownloadMemoryStatus struct {
+	// Budget is the total amount of memory downloads, streamer caches and
+	// stream buffers may use. It can be set with the SKYD_DOWNLOAD_MEMORY
+	// environment variable.
+	Budget uint64 `json:"budget"`
+
+	// Used is the amount of memory that is currently in use, including the
+	// memory held by streamer caches and stream buffers.
+	Used uint64 `json:"used"`
+
+	// Requested is the amount of memory downloads are currently waiting for.
+	Requested uint64 `json:"requested"`
+
+	// StreamerCaches is the amount of memory held by streamer caches.
+	StreamerCaches uint64 `json:"streamercaches"`
+
+	// StreamBuffers is the amount of memory held by the streams of skynet
+	// downloads.
+	StreamBuffers uint64 `json:"streambuffers"`
+}
+
 // MemoryStatus contains information about the status of the memory managers in
 // the renter.
 type MemoryStatus struct {
@@ -838,6 +991,13 @@ type RecoverableContract struct {
 	TxnFee types.Currency `json:"txnfee"`
 }
 
+// ContractCapacity pairs a contract with the remaining storage of its host, as
+// last reported to the hostdb.
+type ContractCapacity struct {
+	Contract         RenterContract `json:"contract"`
+	RemainingStorage uint64         `json:"remainingstorage"`
+}
+
 // A RenterContract contains metadata about a file contract. It is read-only;
 // modifying a RenterContract does not modify the actual file contract.
 type RenterContract struct {
@@ -1012,6 +1172,29 @@ type ContractorChurnStatus struct {
 	MaxPeriodChurn uint64 `json:"maxperiodchurn"`
 }
 
+// AllowanceUtilization describes how much of the allowance's funds have been
+// allocated to and spent from contracts in the current period.
+type AllowanceUtilization struct {
+	Funds           types.Currency `json:"funds"`
+	TotalAllocated  types.Currency `json:"totalallocated"`
+	TotalSpent      types.Currency `json:"totalspent"`
+	FundsRemaining  types.Currency `json:"fundsremaining"`
+	PercentUtilized float64        `json:"percentutilized"`
+}
+
+// FormationMetrics contains metrics about the time it takes the Contractor to
+// form contracts. The formation times are computed over the most recent
+// successful formations, the counts are accumulated since startup.
+type FormationMetrics struct {
+	AverageFormationTime time.Duration `json:"averageformationtime"`
+	P90FormationTime     time.Duration `json:"p90formationtime"`
+	P99FormationTime     time.Duration `json:"p99formationtime"`
+
+	DuplicateFormations uint64 `json:"duplicateformations"`
+	Failures            uint64 `json:"failures"`
+	Successes           uint64 `json:"successes"`
+}
+
 // UploadedBackup contains metadata about an uploaded backup.
 type UploadedBackup struct {
 	Name           string
@@ -1028,6 +1211,7 @@ type (
 		NumWorkers               int            `json:"numworkers"`
 		TotalDownloadCoolDown    int            `json:"totaldownloadcooldown"`
 		TotalMaintenanceCoolDown int            `json:"totalmaintenancecooldown"`
+		TotalStalePriceTables    int            `json:"totalstalepricetables"`
 		TotalUploadCoolDown      int            `json:"totaluploadcooldown"`
 		Workers                  []WorkerStatus `json:"workers"`
 	}
@@ -1092,6 +1276,17 @@ type (
 		RecentErrTime       time.Time `json:"recenterrtime"`
 	}
 
+	// WorkerMaintenanceCooldownStatus contains detailed information about a
+	// worker's maintenance cooldown.
+	WorkerMaintenanceCooldownStatus struct {
+		HostPubKey          types.SiaPublicKey `json:"hostpubkey"`
+		ConsecutiveFailures uint64             `json:"consecutivefailures"`
+		OnCooldown          bool               `json:"oncooldown"`
+		OnCooldownUntil     time.Time          `json:"oncooldownuntil"`
+		RecentErr           string             `json:"recenterr"`
+		RecentErrTime       time.Time          `json:"recenterrtime"`
+	}
+
 	// WorkerAccountStatus contains detailed information about the account
 	WorkerAccountStatus struct {
 		AvailableBalance types.Currency `json:"availablebalance"`
@@ -1105,10 +1300,13 @@ type (
 	// WorkerPriceTableStatus contains detailed information about the price
 	// table
 	WorkerPriceTableStatus struct {
-		ExpiryTime time.Time `json:"expirytime"`
-		UpdateTime time.Time `json:"updatetime"`
+		ExpiryTime    time.Time `json:"expirytime"`
+		LastFetchTime time.Time `json:"lastfetchtime"`
+		UpdateTime    time.Time `json:"updatetime"`
 
-		Active bool `json:"active"`
+		Active bool          `json:"active"`
+		Age    time.Duration `json:"age"`
+		Stale  bool          `json:"stale"`
 
 		RecentErr     string    `json:"recenterr"`
 		RecentErrTime time.Time `json:"recenterrtime"`
@@ -1126,6 +1324,9 @@ type (
 
 		RecentErr     string    `json:"recenterr"`
 		RecentErrTime time.Time `json:"recenterrtime"`
+
+		// SuccessRate is the fraction of recent read jobs that succeeded.
+		SuccessRate float64 `json:"successrate"`
 	}
 
 	// WorkerHasSectorJobsStatus contains detailed information about the has
@@ -1239,6 +1440,10 @@ type Renter interface {
 	// began.
 	CurrentPeriod() types.BlockHeight
 
+	// DownloadMemoryStatus returns the current usage of the memory budget
+	// that is shared by all user-initiated downloads and streamer caches.
+	DownloadMemoryStatus() (DownloadMemoryStatus, error)
+
 	// MemoryStatus returns the current status of the memory manager
 	MemoryStatus() (MemoryStatus, error)
 
@@ -1259,6 +1464,15 @@ type Renter interface {
 	// billing period.
 	PeriodSpending() (ContractorSpending, error)
 
+	// OrphanedContracts returns the active contracts whose hosts are no
+	// longer in the hostdb.
+	OrphanedContracts() ([]RenterContract, error)
+
+	// ContractsByHostRemainingStorage returns the GoodForUpload contracts
+	// paired with the remaining storage of their hosts, sorted by the
+	// remaining storage in descending order.
+	ContractsByHostRemainingStorage() ([]ContractCapacity, error)
+
 	// RecoverableContracts returns the contracts that the contractor deems
 	// recoverable. That means they are not expired yet and also not part of the
 	// active contracts. Usually this should return an empty slice unless the host
@@ -1487,6 +1701,20 @@ type Renter interface {
 	// potentially more expensive, hosts.
 	DownloadSkylinkBaseSector(link Skylink, timeout time.Duration, pricePerMS types.Currency) (Streamer, []RegistryEntry, Skylink, error)
 
+	// DownloadCompletionForecast returns the expected completion times of
+	// fetching the base sector of a V1 skylink without launching the
+	// download. The hosts aren't asked whether they have the sector, they are
+	// weighted by how often they had sectors in the past instead.
+	DownloadCompletionForecast(link Skylink, timeout time.Duration, pricePerMS types.Currency) (DownloadForecast, error)
+
+	// WhyNotSelected returns a human-readable reason why the given host isn't
+	// selected for downloading the base sector of the given skylink.
+	WhyNotSelected(skylink string, pk types.SiaPublicKey) (string, error)
+
+	// VerifyDownload verifies that the downloaded base sector of a skyfile
+	// hashes to the Merkle root of the V1 skylink it was downloaded from.
+	VerifyDownload(link Skylink, data []byte) error
+
 	// SkylinkHealth returns the health of a skylink on the network.
 	SkylinkHealth(ctx context.Context, link Skylink, ppms types.Currency) (SkylinkHealth, error)
 
@@ -1530,6 +1758,18 @@ type Renter interface {
 	// WorkerPoolStatus returns the current status of the Renter's worker pool
 	WorkerPoolStatus() (WorkerPoolStatus, error)
 
+	// ClearWorkerCooldown takes the worker for the given host off of its
+	// maintenance cooldown.
+	ClearWorkerCooldown(hostPubKey types.SiaPublicKey) error
+
+	// SetWorkerBalanceTarget sets the balance the worker for the given host
+	// refills its ephemeral account to. A zero target removes the override.
+	SetWorkerBalanceTarget(hostPubKey types.SiaPublicKey, target types.Currency) error
+
+	// WorkerMaintenanceCooldownStatus returns the maintenance cooldown status
+	// of the worker for the given host.
+	WorkerMaintenanceCooldownStatus(hostPubKey types.SiaPublicKey) (WorkerMaintenanceCooldownStatus, error)
+
 	// UpdateMetadata will ensure that the metadata of the provided directory is
 	// updated and that the updated stats are represented in the aggregate
 	// statistics of the root folder.
@@ -1578,6 +1818,61 @@ type SkylinkHealth struct {
 	FanoutRedundancy []float64 `json:"fanoutredundancy,omitempty"`
 }
 
+// PinnedSkyfileHealth describes the health of a skyfile pinned by the renter
+// based on the cached health and redundancy of its siafile.
+type PinnedSkyfileHealth struct {
+	SiaPath  SiaPath  `json:"siapath"`
+	Skylinks []string `json:"skylinks"`
+
+	Health     float64 `json:"health"`
+	Redundancy float64 `json:"redundancy"`
+
+	// MinPieces is the number of pieces needed to recover a chunk of the
+	// skyfile and ReachablePieces is the number of pieces of the skyfile's
+	// worst chunk which are stored on online hosts that the renter has a
+	// contract with.
+	MinPieces       uint64 `json:"minpieces"`
+	ReachablePieces uint64 `json:"reachablepieces"`
+
+	// MeetsMinRedundancy indicates whether the skyfile's health is good
+	// enough to not require a repair.
+	MeetsMinRedundancy bool `json:"meetsminredundancy"`
+}
+
+// UploadedPiece describes a piece of a chunk that was uploaded to a host.
+type UploadedPiece struct {
+	HostPublicKey types.SiaPublicKey `json:"hostpublickey"`
+	MerkleRoot    crypto.Hash        `json:"merkleroot"`
+	PieceIndex    uint64             `json:"pieceindex"`
+}
+
+// AvailabilityReport describes how many of the pieces needed to recover a
+// skyfile are currently reachable on the network.
+type AvailabilityReport struct {
+	// BaseSectorHosts is the number of hosts which have the base sector.
+	BaseSectorHosts uint64 `json:"basesectorhosts"`
+
+	// NumHosts is the number of distinct hosts which have at least one piece
+	// of the skyfile.
+	NumHosts uint64 `json:"numhosts"`
+
+	// PiecesNeeded is the number of pieces needed to recover the skyfile,
+	// which is the base sector plus the data pieces of every fanout chunk.
+	PiecesNeeded uint64 `json:"piecesneeded"`
+
+	// PiecesReachable is the number of needed pieces that are currently
+	// reachable.
+	PiecesReachable uint64 `json:"piecesreachable"`
+
+	// AtRisk indicates whether losing a single host might render the skyfile
+	// unrecoverable.
+	AtRisk bool `json:"atrisk"`
+
+	// Unrecoverable indicates whether the skyfile can't currently be
+	// recovered from the reachable pieces.
+	Unrecoverable bool `json:"unrecoverable"`
+}
+
 // RenterDownloadParameters defines the parameters passed to the Renter's
 // Download method.
 type RenterDownloadParameters struct {
@@ -1588,6 +1883,20 @@ type RenterDownloadParameters struct {
 	SiaPath          SiaPath
 	Destination      string
 	DisableDiskFetch bool
+	StickyWorkers    bool
+
+	// PriorityPieces are the indices of the pieces that are fetched first in
+	// every chunk. The workers of the other pieces only fill the remaining
+	// slots, e.g. to fetch the data pieces first, which don't need to be
+	// decoded. Enough pieces are still fetched to recover each chunk.
+	PriorityPieces []uint64
+
+	// MinOverdrive and MaxOverdrive bound the number of extra pieces that
+	// are downloaded for each chunk. The overdrive scales with the number of
+	// chunks in the download, starting at MinOverdrive for a single chunk. If
+	// MaxOverdrive is 0, the renter's defaults are used.
+	MinOverdrive uint64
+	MaxOverdrive uint64
 }
 
 // HealthPercentage returns the health in a more human understandable format out
@@ -6,6 +6,7 @@ import (
 
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/modules"
 )
 
 // Version and system parameters.
@@ -21,6 +22,13 @@ const (
 	// AlertSiafileLowRedundancyThreshold is the health threshold at which we start
 	// registering the LowRedundancy alert for a Siafile.
 	AlertSiafileLowRedundancyThreshold = 0.75
+
+	// AlertIDStalePriceTables is the id of the alert that is registered when
+	// a significant fraction of the workers have a stale price table.
+	AlertIDStalePriceTables = modules.AlertID("stale-price-tables")
+	// AlertMSGStalePriceTables indicates that the price tables of many
+	// workers couldn't be updated.
+	AlertMSGStalePriceTables = "A significant fraction of the workers failed to update their price tables"
 )
 
 // AlertCauseSiafileLowRedundancy creates a customized "cause" for a siafile
@@ -29,6 +37,12 @@ func AlertCauseSiafileLowRedundancy(siaPath skymodules.SiaPath, health, redundan
 	return fmt.Sprintf("Siafile '%v' has a health of %v and redundancy of %v", siaPath.String(), health, redundancy)
 }
 
+// AlertCauseStalePriceTables creates a customized "cause" for the stale price
+// tables alert.
+func AlertCauseStalePriceTables(numStale, numWorkers int) string {
+	return fmt.Sprintf("%v out of %v workers have a stale price table", numStale, numWorkers)
+}
+
 // Default redundancy parameters.
 var (
 	// syncCheckInterval is how often the repair heap checks the consensus code
@@ -75,7 +89,8 @@ var (
 	// userDownloadMemoryDefault establishes the default amount of memory that
 	// the renter will use when performing user-initiated downloads. The mapping
 	// is currently not perfect due to GC overhead and other places where we
-	// don't count all of the memory usage accurately.
+	// don't count all of the memory usage accurately. It can be overwritten
+	// with the SKYD_DOWNLOAD_MEMORY environment variable.
 	userDownloadMemoryDefault = build.Select(build.Var{
 		Dev:      uint64(1 << 28), // 256 MiB
 		Standard: uint64(1 << 29), // 0.5 GiB
@@ -138,6 +153,20 @@ var (
 		Standard: int64(1 << 25), // 32 MiB
 		Testing:  int64(1 << 13), // 8 KiB
 	}).(int64)
+
+	// defaultStreamerFillCacheRetries is the number of times a streamer will
+	// retry a failed download while filling its cache before it gives up and
+	// returns the error to the reader. Retries are disabled by default.
+	defaultStreamerFillCacheRetries = 0
+
+	// defaultStreamerFillCacheRetryBackoff is the initial amount of time a
+	// streamer waits before retrying a failed download. The backoff doubles
+	// with every retry.
+	defaultStreamerFillCacheRetryBackoff = build.Select(build.Var{
+		Dev:      100 * time.Millisecond,
+		Standard: 500 * time.Millisecond,
+		Testing:  10 * time.Millisecond,
+	}).(time.Duration)
 )
 
 // Default bandwidth usage parameters.
@@ -151,6 +180,18 @@ const (
 	DefaultMaxUploadSpeed = 0
 )
 
+// Default download overdrive parameters.
+const (
+	// DefaultDownloadOverdrive is the default number of extra pieces that are
+	// downloaded for each chunk of a download. The overdrive only scales with
+	// the size of a download if the download sets its own bounds.
+	DefaultDownloadOverdrive = 3 // TODO: moderate default until full overdrive support is added.
+
+	// downloadChunksPerOverdrive is the number of chunks a download needs to
+	// grow by for the overdrive of its chunks to increase by one.
+	downloadChunksPerOverdrive = 8
+)
+
 // Naming conventions for code readability.
 const (
 	// destinationTypeSeekStream is the destination type used for downloads
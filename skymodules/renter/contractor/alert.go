@@ -1,9 +1,36 @@
 package contractor
 
-import "go.sia.tech/siad/modules"
+import (
+	"fmt"
+
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
 
 // Alerts implements the modules.Alerter interface for the contractor. It returns
 // all alerts of the contractor.
 func (c *Contractor) Alerts() (crit, err, warn []modules.Alert) {
 	return c.staticAlerter.Alerts()
 }
+
+// alertIDDuplicateFormation is the id of the alert that is registered when a
+// contract was formed with a host that the contractor already had a contract
+// with.
+var alertIDDuplicateFormation = modules.AlertID("contractor-duplicate-formation")
+
+// alertIDRenewedContractUtility returns the id of the alert that is registered
+// when the utility of a renewed contract couldn't be updated.
+func alertIDRenewedContractUtility(fcID types.FileContractID) modules.AlertID {
+	return modules.AlertID("renewed-contract-utility-" + fcID.String())
+}
+
+// managedRegisterDuplicateFormation tracks that a contract was formed with a
+// host that the contractor already had a contract with and registers an alert.
+// Since formation checks for an existing contract before negotiating, this
+// only happens if two maintenance paths race to form a contract with the same
+// host.
+func (c *Contractor) managedRegisterDuplicateFormation(hpk types.SiaPublicKey) {
+	n := c.staticFormationMetrics.callAddDuplicateFormation()
+	cause := fmt.Sprintf("%v duplicate formations since startup, most recently with host %v", n, hpk)
+	c.staticAlerter.RegisterAlert(alertIDDuplicateFormation, AlertMSGDuplicateFormation, cause, modules.SeverityWarning)
+}
@@ -36,6 +36,21 @@ var (
 	// ErrAllowanceZeroMaxPeriodChurn is returned if the allowance max period
 	// churn is being set to zero when not cancelling the allowance
 	ErrAllowanceZeroMaxPeriodChurn = errors.New("max period churn must be non-zero")
+	// ErrAllowanceMinContractFundingTooHigh is returned if the allowance
+	// minimum contract funding exceeds the allowance funds
+	ErrAllowanceMinContractFundingTooHigh = errors.New("min contract funding must not exceed funds")
+	// ErrAllowanceInvalidBackupHostsThreshold is returned if the allowance
+	// backup hosts threshold is not within [0, 1]
+	ErrAllowanceInvalidBackupHostsThreshold = errors.New("backup hosts threshold must be between 0 and 1")
+	// ErrAllowanceInvalidDuplicateContractStrategy is returned if the
+	// allowance duplicate contract strategy is unknown
+	ErrAllowanceInvalidDuplicateContractStrategy = errors.New("unknown duplicate contract strategy")
+	// ErrAllowanceInvalidAccountRefillWaterMarks is returned if the allowance
+	// account refill low water mark is not below the high water mark
+	ErrAllowanceInvalidAccountRefillWaterMarks = errors.New("account refill low water mark must be below the high water mark")
+	// ErrAllowanceRenewGraceTooLarge is returned if the allowance renew grace
+	// blocks are not less than half the renew window
+	ErrAllowanceRenewGraceTooLarge = errors.New("renew grace blocks must be less than half the renew window")
 )
 
 // SetAllowance sets the amount of money the Contractor is allowed to spend on
@@ -53,9 +68,29 @@ var (
 // set. The contracts cannot be used to create Editors or Downloads, and will
 // not be renewed.
 //
+// If a temporary allowance is active, its pending revert is dropped.
+//
 // NOTE: At this time, transaction fees are not counted towards the allowance.
 // This means the contractor may spend more than allowance.Funds.
 func (c *Contractor) SetAllowance(a skymodules.Allowance) error {
+	c.allowanceLock.Lock()
+	defer c.allowanceLock.Unlock()
+
+	c.mu.Lock()
+	if c.temporaryAllowance != nil {
+		c.staticLog.Println("INFO: dropping temporary allowance revert due to manual allowance change")
+		c.temporaryAllowance = nil
+		if err := c.save(); err != nil {
+			c.staticLog.Println("Unable to save contractor after dropping temporary allowance:", err)
+		}
+	}
+	c.mu.Unlock()
+	return c.managedSetAllowance(a)
+}
+
+// managedSetAllowance sets the allowance without touching the temporary
+// allowance.
+func (c *Contractor) managedSetAllowance(a skymodules.Allowance) error {
 	if reflect.DeepEqual(a, skymodules.Allowance{}) {
 		return c.managedCancelAllowance()
 	}
@@ -86,6 +121,16 @@ func (c *Contractor) SetAllowance(a skymodules.Allowance) error {
 		return ErrAllowanceZeroExpectedRedundancy
 	} else if a.MaxPeriodChurn == 0 {
 		return ErrAllowanceZeroMaxPeriodChurn
+	} else if a.MinContractFunding.Cmp(a.Funds) > 0 {
+		return ErrAllowanceMinContractFundingTooHigh
+	} else if a.BackupHostsThreshold < 0 || a.BackupHostsThreshold > 1 {
+		return ErrAllowanceInvalidBackupHostsThreshold
+	} else if !a.DuplicateContractStrategy.IsValid() {
+		return ErrAllowanceInvalidDuplicateContractStrategy
+	} else if !a.AccountRefillHighWaterMark.IsZero() && a.AccountRefillLowWaterMark.Cmp(a.AccountRefillHighWaterMark) >= 0 {
+		return ErrAllowanceInvalidAccountRefillWaterMarks
+	} else if a.RenewGraceBlocks > 0 && a.RenewGraceBlocks >= a.RenewWindow/2 {
+		return ErrAllowanceRenewGraceTooLarge
 	}
 	c.staticLog.Println("INFO: setting allowance to", a)
 
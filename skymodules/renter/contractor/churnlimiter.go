@@ -105,15 +105,18 @@ func (cl *churnLimiter) callNotifyChurnedContract(contract skymodules.RenterCont
 }
 
 // callBumpChurnBudget increases the churn budget by a fraction of the max churn
-// budget per period. Used when new blocks are processed.
-func (cl *churnLimiter) callBumpChurnBudget(numBlocksAdded int, period types.BlockHeight) {
+// budget per period. Used when new blocks are processed. The allowance is
+// passed in rather than fetched from the contractor, so that the budget can be
+// bumped while the contractor's lock is held.
+func (cl *churnLimiter) callBumpChurnBudget(numBlocksAdded int, a skymodules.Allowance) {
 	// Don't add to churn budget when there is no period, since no allowance is
 	// set yet.
+	period := a.Period
 	if period == types.BlockHeight(0) {
 		return
 	}
-	maxPeriodChurn := cl.managedMaxPeriodChurn()
-	maxChurnBudget := cl.managedMaxChurnBudget()
+	maxPeriodChurn := a.MaxPeriodChurn
+	maxChurnBudget := maxChurnBudget(maxPeriodChurn)
 	cl.mu.Lock()
 	defer cl.mu.Unlock()
 
@@ -129,9 +132,15 @@ func (cl *churnLimiter) callBumpChurnBudget(numBlocksAdded int, period types.Blo
 
 // managedMaxChurnBudget returns the max allowed value for remainingChurnBudget.
 func (cl *churnLimiter) managedMaxChurnBudget() int {
+	return maxChurnBudget(cl.managedMaxPeriodChurn())
+}
+
+// maxChurnBudget returns the max allowed value for remainingChurnBudget given
+// the max churn per period.
+func maxChurnBudget(maxPeriodChurn uint64) int {
 	// Do not let churn budget to build up to maxPeriodChurn to avoid using entire
 	// period budget at once (except in special circumstances).
-	return int(cl.managedMaxPeriodChurn() / 2)
+	return int(maxPeriodChurn / 2)
 }
 
 // managedProcessSuggestedUpdates processes suggested utility updates. It prevents
@@ -318,5 +327,15 @@ func (c *Contractor) managedMarkContractsUtility() error {
 		return errors.AddContext(err, "churnLimiter processSuggestedUpdates err")
 	}
 
+	// Save the contractor to persist any churn, otherwise a restart would
+	// reset the churn budget that was used by the updates.
+	if len(suggestedUpdateQueue) > 0 {
+		c.mu.Lock()
+		err = c.save()
+		c.mu.Unlock()
+		if err != nil {
+			c.staticLog.Println("Unable to save the churn limiter after processing utility updates:", err)
+		}
+	}
 	return nil
 }
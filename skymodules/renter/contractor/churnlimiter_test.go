@@ -1,9 +1,11 @@
 package contractor
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/persist"
 	"go.sia.tech/siad/types"
 )
 
@@ -81,3 +83,36 @@ func TestCanChurnContract(t *testing.T) {
 		t.Fatal("Expected not to be able to churn contract")
 	}
 }
+
+// TestBumpChurnBudget is a unit test for callBumpChurnBudget.
+func TestBumpChurnBudget(t *testing.T) {
+	t.Parallel()
+
+	l, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := newChurnLimiter(&Contractor{staticLog: l})
+	cl.remainingChurnBudget = -100
+
+	// Without a period, the budget isn't bumped.
+	var a skymodules.Allowance
+	a.MaxPeriodChurn = 1000
+	cl.callBumpChurnBudget(10, a)
+	if cl.remainingChurnBudget != -100 {
+		t.Fatal("unexpected budget", cl.remainingChurnBudget)
+	}
+
+	// With a period, every block adds its share of the period's churn.
+	a.Period = 100
+	cl.callBumpChurnBudget(10, a)
+	if cl.remainingChurnBudget != 0 {
+		t.Fatal("unexpected budget", cl.remainingChurnBudget)
+	}
+
+	// The budget is capped at half the period's churn.
+	cl.callBumpChurnBudget(1000, a)
+	if cl.remainingChurnBudget != 500 {
+		t.Fatal("unexpected budget", cl.remainingChurnBudget)
+	}
+}
@@ -1,6 +1,8 @@
 package contractor
 
 import (
+	"time"
+
 	"gitlab.com/SkynetLabs/skyd/build"
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
@@ -17,9 +19,18 @@ var (
 	// funds.
 	AlertMSGAllowanceLowFunds = "At least one contract formation/renewal failed due to the allowance being low on funds"
 
+	// AlertMSGDuplicateFormation indicates that a contract was formed with a
+	// host that the contractor already had a contract with. The funding of
+	// such a contract is lost.
+	AlertMSGDuplicateFormation = "Contractor formed a contract with a host it already had a contract with"
+
 	// AlertMSGFailedContractRenewal indicates that the contract renewal failed
 	AlertMSGFailedContractRenewal = "Contractor is attempting to renew/refresh contracts but failed"
 
+	// AlertMSGRenewedContractUtility indicates that the utilities of a
+	// contract couldn't be updated after it was renewed successfully.
+	AlertMSGRenewedContractUtility = "Contract was renewed but its utility couldn't be updated and needs to be repaired manually"
+
 	// AlertMSGWalletLockedDuringMaintenance indicates that forming/renewing a
 	// contract during contract maintenance isn't possible due to a locked wallet.
 	AlertMSGWalletLockedDuringMaintenance = "At least one contract failed to form/renew due to the wallet being locked"
@@ -70,6 +81,27 @@ var (
 	// failure mode of 'can't retrieve stuff already uploaded'.
 	MinContractFundUploadThreshold = float64(0.05) // 5%
 
+	// renewUtilityUpdateRetries is the number of times the utility updates
+	// after a successful renewal are retried before giving up.
+	renewUtilityUpdateRetries = 3
+
+	// renewUtilityUpdateRetryInterval is the time the contractor waits between
+	// retrying the utility updates after a successful renewal.
+	renewUtilityUpdateRetryInterval = build.Select(build.Var{
+		Dev:      time.Second,
+		Standard: 5 * time.Second,
+		Testing:  10 * time.Millisecond,
+	}).(time.Duration)
+
+	// renewInvalidationTimeout is the max time a renewal waits for the
+	// active editor and downloader of a contract to be invalidated before the
+	// renewal is skipped.
+	renewInvalidationTimeout = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 5 * time.Minute,
+		Testing:  5 * time.Second,
+	}).(time.Duration)
+
 	// randomHostsBufferForScore defines how many extra hosts are queried when trying
 	// to figure out an appropriate minimum score for the hosts that we have.
 	randomHostsBufferForScore = build.Select(build.Var{
@@ -78,6 +110,28 @@ var (
 		Testing:  1,
 	}).(int)
 
+	// defaultPortalFormationThreads is the default number of threads a portal
+	// uses to form contracts in parallel. By default, contracts are formed one
+	// at a time. It can be overwritten with the SKYD_PORTAL_FORMATION_THREADS
+	// environment variable.
+	defaultPortalFormationThreads = 1
+
+	// maintenanceModeQueue and maintenanceModeSkip are the values of the
+	// SKYD_CONTRACT_MAINTENANCE_MODE environment variable. With
+	// maintenanceModeSkip, a contract maintenance trigger is dropped if
+	// another maintenance run is active. With maintenanceModeQueue, the
+	// maintenance runs once more after the active run finishes.
+	maintenanceModeQueue = "queue"
+	maintenanceModeSkip  = "skip"
+
+	// networkDiversityIPv4PrefixLen and networkDiversityIPv6PrefixLen are the
+	// prefix lengths of the networks that hosts are grouped by when the
+	// allowance prefers diverse hosts. They are shorter than the subnets used
+	// by the hostdb's IP filter, since hosts within those subnets are already
+	// filtered.
+	networkDiversityIPv4PrefixLen = 16
+	networkDiversityIPv6PrefixLen = 32
+
 	// oosRetryInterval is the time we wait for a host that ran out of storage to
 	// add more storage before trying to upload to it again.
 	oosRetryInterval = build.Select(build.Var{
@@ -103,7 +157,8 @@ var (
 		Testing:  maxStoragePrice.Mul64(300 * uint64(types.BlocksPerMonth)), // 2 orders of magnitude greater
 	}).(types.Currency)
 
-	// scoreLeewayGoodForRenew defines the factor by which a host can miss the
+	// scoreLeewayGoodForRenew is the default factor used if the allowance's
+	// ScoreLeewayGFR is not set. It defines the factor by which a host can miss the
 	// goal score for a set of hosts and still be GoodForRenew. To determine the
 	// goal score, a new set of hosts is queried from the hostdb and the lowest
 	// scoring among them is selected.  That score is then divided by
@@ -114,7 +169,8 @@ var (
 	// be getting set in a lot more scientific way.
 	scoreLeewayGoodForRenew = types.NewCurrency64(500)
 
-	// scoreLeewayGoodForUpload defines the factor by which a host can miss the
+	// scoreLeewayGoodForUpload is the default factor used if the allowance's
+	// ScoreLeewayGFU is not set. It defines the factor by which a host can miss the
 	// goal score for a set of hosts and still be GoodForUpload. To determine the
 	// goal score, a new set of hosts is queried from the hostdb and the lowest
 	// scoring among them is selected.  That score is then divided by
@@ -5,14 +5,19 @@ package contractor
 // contracts need to be renewed, and if contracts need to be blacklisted.
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"math/big"
+	"net"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/NebulousLabs/threadgroup"
 
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
@@ -42,6 +47,14 @@ var (
 
 	// errHostBlocked is the error returned when the host is blocked
 	errHostBlocked = errors.New("host is blocked")
+
+	// errContractWithHostExists is returned when trying to form a contract
+	// with a host that the contractor already has a contract with.
+	errContractWithHostExists = errors.New("contract with host already exists")
+
+	// errInvalidationTimeout is returned when a renewal is skipped because an
+	// editor or downloader of the contract couldn't be invalidated in time.
+	errInvalidationTimeout = errors.New("timed out waiting for the invalidation of an active editor or downloader")
 )
 
 type (
@@ -118,10 +131,11 @@ func hostsForRegularFormation(allowance skymodules.Allowance, allContracts []sky
 		return 0, nil
 	}
 	// Count the number of contracts which are good for uploading, and then make
-	// more as needed to fill the gap.
+	// more as needed to fill the gap. Contracts with backup hosts are not
+	// counted since they are only used temporarily.
 	uploadContracts := 0
 	for _, c := range allContracts {
-		if c.Utility.GoodForUpload {
+		if c.Utility.GoodForUpload && !allowance.IsBackupHost(c.HostPublicKey) {
 			uploadContracts++
 		}
 	}
@@ -151,6 +165,8 @@ func hostsForRegularFormation(allowance skymodules.Allowance, allContracts []sky
 	for _, contract := range recoverableContracts {
 		blacklist = append(blacklist, contract.HostPublicKey)
 	}
+	// Backup hosts are only used by the backup host formation.
+	blacklist = append(blacklist, allowance.BackupHosts...)
 
 	hosts, err := randomHosts(neededContracts*4+randomHostsBufferForScore, blacklist, addressBlacklist)
 	if err != nil {
@@ -161,6 +177,136 @@ func hostsForRegularFormation(allowance skymodules.Allowance, allContracts []sky
 	return neededContracts, hosts
 }
 
+// hostNetworks returns the networks of the host's IP subnets. The networks
+// are coarser than the subnets to group hosts that are close to each other.
+// Hosts without any known subnets are grouped together.
+func hostNetworks(host skymodules.HostDBEntry) []string {
+	var networks []string
+	for _, ipNet := range host.IPNets {
+		_, subnet, err := net.ParseCIDR(ipNet)
+		if err != nil {
+			continue
+		}
+		prefixLen := networkDiversityIPv6PrefixLen
+		if subnet.IP.To4() != nil {
+			prefixLen = networkDiversityIPv4PrefixLen
+		}
+		network := &net.IPNet{
+			IP:   subnet.IP,
+			Mask: net.CIDRMask(prefixLen, len(subnet.IP)*8),
+		}
+		network.IP = network.IP.Mask(network.Mask)
+		networks = append(networks, network.String())
+	}
+	if len(networks) == 0 {
+		return []string{""}
+	}
+	return networks
+}
+
+// orderHostsByDiversity reorders the given hosts to prefer hosts that
+// increase the network diversity of the hosts we have contracts with. It
+// repeatedly picks the host whose networks are shared with the fewest
+// existing and already picked hosts. Ties are broken by the original order,
+// so the random order of the hosts is preserved among equally diverse hosts.
+func orderHostsByDiversity(hosts, existing []skymodules.HostDBEntry) []skymodules.HostDBEntry {
+	counts := make(map[string]int)
+	for _, host := range existing {
+		for _, network := range hostNetworks(host) {
+			counts[network]++
+		}
+	}
+
+	remaining := make([][]string, len(hosts))
+	for i, host := range hosts {
+		remaining[i] = hostNetworks(host)
+	}
+	picked := make([]bool, len(hosts))
+	ordered := make([]skymodules.HostDBEntry, 0, len(hosts))
+	for len(ordered) < len(hosts) {
+		best, bestScore := -1, 0
+		for i, networks := range remaining {
+			if picked[i] {
+				continue
+			}
+			score := 0
+			for _, network := range networks {
+				score += counts[network]
+			}
+			if best == -1 || score < bestScore {
+				best, bestScore = i, score
+			}
+		}
+		picked[best] = true
+		ordered = append(ordered, hosts[best])
+		for _, network := range remaining[best] {
+			counts[network]++
+		}
+	}
+	return ordered
+}
+
+// backupHostsNeeded returns whether the number of good for upload contracts
+// with hosts that are not backup hosts dropped below the allowance's backup
+// hosts threshold.
+func backupHostsNeeded(allowance skymodules.Allowance, allContracts []skymodules.RenterContract) bool {
+	if len(allowance.BackupHosts) == 0 || allowance.PortalMode() {
+		return false
+	}
+	primaryContracts := 0
+	for _, c := range allContracts {
+		if c.Utility.GoodForUpload && !allowance.IsBackupHost(c.HostPublicKey) {
+			primaryContracts++
+		}
+	}
+	return float64(primaryContracts) < allowance.BackupHostsThreshold*float64(allowance.Hosts)
+}
+
+// hostsForBackupFormation returns the number of contracts that should be
+// formed with backup hosts plus the set of backup hosts to use. Contracts are
+// only formed with backup hosts if they are needed and only until the total
+// number of good for upload contracts matches the allowance.
+func hostsForBackupFormation(allowance skymodules.Allowance, allContracts []skymodules.RenterContract, recoverableContracts []skymodules.RecoverableContract, hostFn func(types.SiaPublicKey) (skymodules.HostDBEntry, bool, error), l *persist.Logger) (int, []skymodules.HostDBEntry) {
+	if !backupHostsNeeded(allowance, allContracts) {
+		return 0, nil
+	}
+	uploadContracts := 0
+	existing := make(map[string]struct{})
+	for _, c := range allContracts {
+		if c.Utility.GoodForUpload {
+			uploadContracts++
+		}
+		existing[c.HostPublicKey.String()] = struct{}{}
+	}
+	for _, c := range recoverableContracts {
+		existing[c.HostPublicKey.String()] = struct{}{}
+	}
+	neededContracts := int(allowance.Hosts) - uploadContracts
+	if neededContracts <= 0 {
+		return 0, nil
+	}
+
+	var hosts []skymodules.HostDBEntry
+	for _, pk := range allowance.BackupHosts {
+		if _, exists := existing[pk.String()]; exists {
+			continue
+		}
+		host, ok, err := hostFn(pk)
+		if err != nil || !ok || host.Filtered {
+			l.Debugf("skipping backup host %v, found: %v, filtered: %v, err: %v", pk, ok, host.Filtered, err)
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	if neededContracts > len(hosts) {
+		neededContracts = len(hosts)
+	}
+	if neededContracts > 0 {
+		l.Println("need more contracts with backup hosts:", neededContracts)
+	}
+	return neededContracts, hosts
+}
+
 // initialContractFunding computes the amount of money to put into the first
 // contract formed with a host.
 func initialContractFunding(a skymodules.Allowance, host skymodules.HostDBEntry, txnFee, min, max types.Currency) types.Currency {
@@ -171,6 +317,16 @@ func initialContractFunding(a skymodules.Allowance, host skymodules.HostDBEntry,
 	// Calculate the contract funding with host
 	contractFunds := host.ContractPrice.Add(txnFee).Mul64(ContractFeeFundingMulFactor)
 
+	// Apply the absolute floor from the allowance. The floor takes precedence
+	// over the min and max derived from the per-host division of the
+	// allowance, so that no contract is too small to be useful.
+	if min.Cmp(a.MinContractFunding) < 0 {
+		min = a.MinContractFunding
+	}
+	if !max.IsZero() && max.Cmp(min) < 0 {
+		max = min
+	}
+
 	// Check that the contract funding is reasonable compared to the max and
 	// min initial funding. This is to protect against increases to
 	// allowances being used up to fast and not being able to spread the
@@ -210,6 +366,10 @@ func (c *Contractor) callNotifyDoubleSpend(fcID types.FileContractID, blockHeigh
 // managedCheckForDuplicates checks for static contracts that have the same host
 // key and moves the older one to old contracts.
 func (c *Contractor) managedCheckForDuplicates() {
+	c.mu.RLock()
+	strategy := c.allowance.DuplicateContractStrategy
+	c.mu.RUnlock()
+
 	// Build map for comparison.
 	pubkeys := make(map[string]types.FileContractID)
 	var newContract, oldContract skymodules.RenterContract
@@ -220,13 +380,9 @@ func (c *Contractor) managedCheckForDuplicates() {
 			continue
 		}
 
-		// Duplicate contract found, determine older contract to delete.
+		// Duplicate contract found, determine the contract to delete.
 		if rc, ok := c.staticContracts.View(id); ok {
-			if rc.StartHeight >= contract.StartHeight {
-				newContract, oldContract = rc, contract
-			} else {
-				newContract, oldContract = contract, rc
-			}
+			newContract, oldContract = resolveDuplicateContracts(strategy, rc, contract)
 			c.staticLog.Printf("Duplicate contract found. New contract is %x and old contract is %v", newContract.ID, oldContract.ID)
 
 			// Get SafeContract
@@ -238,7 +394,10 @@ func (c *Contractor) managedCheckForDuplicates() {
 			}
 
 			// Link the contracts to each other and then store the old contract
-			// in the record of historic contracts.
+			// in the record of historic contracts. The duplicate might be the
+			// result of an interrupted renewal which didn't get to link the
+			// contracts, so the links are reconciled to point to the surviving
+			// contract.
 			//
 			// Note: This means that if there are multiple duplicates, say 3
 			// contracts that all share the same host, then the ordering may not
@@ -246,9 +405,8 @@ func (c *Contractor) managedCheckForDuplicates() {
 			// possible for the contractor to end up with A->C and B<->C in the
 			// mapping.
 			c.mu.Lock()
-			c.renewedFrom[newContract.ID] = oldContract.ID
-			c.renewedTo[oldContract.ID] = newContract.ID
-			c.oldContracts[oldContract.ID] = oldSC.Metadata()
+			c.linkRenewedContracts(oldContract.ID, newContract.ID)
+			c.oldContracts.set(oldSC.Metadata())
 
 			// Save the contractor and delete the contract.
 			//
@@ -271,6 +429,69 @@ func (c *Contractor) managedCheckForDuplicates() {
 	}
 }
 
+// resolveDuplicateContracts decides which of two contracts with the same host
+// survives according to the given strategy. The surviving contract is returned
+// as the new contract and the other one as the old contract, which is linked
+// to the new one as if it was renewed. If the strategy doesn't prefer either
+// contract, the one with the higher start height survives.
+func resolveDuplicateContracts(strategy skymodules.DuplicateContractStrategy, a, b skymodules.RenterContract) (newContract, oldContract skymodules.RenterContract) {
+	var cmp int
+	switch strategy {
+	case skymodules.DuplicateContractStrategyDataStored:
+		switch {
+		case a.Size() > b.Size():
+			cmp = 1
+		case a.Size() < b.Size():
+			cmp = -1
+		}
+	case skymodules.DuplicateContractStrategyRenterFunds:
+		cmp = a.RenterFunds.Cmp(b.RenterFunds)
+	}
+	if cmp > 0 || (cmp == 0 && a.StartHeight >= b.StartHeight) {
+		return a, b
+	}
+	return b, a
+}
+
+// linkRenewedContracts links the old contract to the contract that replaces it
+// and carries over the old contract's user settings. Links to other contracts
+// that would no longer be mirrored by the opposite map are removed, so that
+// renewedFrom and renewedTo stay consistent even if the same renewal is linked
+// more than once or a previous attempt left a partial link behind.
+func (c *Contractor) linkRenewedContracts(oldID, newID types.FileContractID) {
+	if to, exists := c.renewedTo[oldID]; exists && to != newID && c.renewedFrom[to] == oldID {
+		delete(c.renewedFrom, to)
+	}
+	if from, exists := c.renewedFrom[newID]; exists && from != oldID && c.renewedTo[from] == newID {
+		delete(c.renewedTo, from)
+	}
+	c.renewedFrom[newID] = oldID
+	c.renewedTo[oldID] = newID
+
+	// Carry over a manually disabled upload utility to the new contract.
+	if _, disabled := c.uploadDisabledContracts[oldID]; disabled {
+		delete(c.uploadDisabledContracts, oldID)
+		c.uploadDisabledContracts[newID] = struct{}{}
+	}
+	// Carry over the contract's note to the new contract.
+	if note, exists := c.contractNotes[oldID]; exists {
+		delete(c.contractNotes, oldID)
+		c.contractNotes[newID] = note
+	}
+}
+
+// percentFundsRemaining returns the fraction of a contract's total cost that
+// is still available to the renter. A zero total cost, which can only happen
+// for corrupt or legacy contracts, is treated as having no funds remaining so
+// that the contract is deterministically considered for a refresh.
+func percentFundsRemaining(renterFunds, totalCost types.Currency) float64 {
+	if totalCost.IsZero() {
+		return 0
+	}
+	percentRemaining, _ := big.NewRat(0, 1).SetFrac(renterFunds.Big(), totalCost.Big()).Float64()
+	return percentRemaining
+}
+
 // managedEstimateRenewFundingRequirements estimates the amount of money that a
 // contract is going to need in the next billing cycle by looking at how much
 // storage is in the contract and what the historic usage pattern of the
@@ -314,7 +535,7 @@ func (c *Contractor) managedEstimateRenewFundingRequirements(contract skymodules
 
 		// If the contract is not in oldContracts, that's probably a bug, but
 		// nothing to do otherwise.
-		currentContract, exists := c.oldContracts[currentID]
+		currentContract, exists := c.oldContracts.get(currentID)
 		if !exists {
 			c.staticLog.Println("WARN: A known previous contract is not found in c.oldContracts")
 			break
@@ -390,11 +611,27 @@ func (c *Contractor) managedEstimateRenewFundingRequirements(contract skymodules
 	// but without an upper cap.
 	minInitialContractFunds := allowance.Funds.Div64(allowance.Hosts).Div64(MinInitialContractFundingDivFactor)
 	minimum := initialContractFunding(allowance, host, txnFees, minInitialContractFunds, types.ZeroCurrency)
-	if estimatedCost.Cmp(minimum) < 0 {
-		c.staticLog.Printf("Contract renew amount %v below minimum amount %v", estimatedCost, minimum)
-		estimatedCost = minimum
+	funding, floorApplied := applyRenewFundingFloor(estimatedCost, minimum, dataStored, allowance.DisableRenewFundingFloor)
+	if floorApplied {
+		c.staticLog.Printf("INFO: renew funding floor is the binding constraint for contract %v, raising the estimate of %v to the minimum of %v", contract.ID, estimatedCost, minimum)
+	} else if estimatedCost.Cmp(minimum) < 0 {
+		c.staticLog.Printf("INFO: renew funding floor of %v disabled for contract %v, using the estimate of %v", minimum, contract.ID, estimatedCost)
+	}
+	return funding, nil
+}
+
+// applyRenewFundingFloor raises the estimated renew funding of a contract to
+// the given minimum. If disableFloor is set, the floor is skipped for contracts
+// that store data, since their estimate is based on their actual usage. It
+// returns the funding and whether the floor was the binding constraint.
+func applyRenewFundingFloor(estimate, minimum types.Currency, dataStored uint64, disableFloor bool) (types.Currency, bool) {
+	if estimate.Cmp(minimum) >= 0 {
+		return estimate, false
+	}
+	if disableFloor && dataStored > 0 {
+		return estimate, false
 	}
-	return estimatedCost, nil
+	return minimum, true
 }
 
 // callInterruptContractMaintenance will issue an interrupt signal to any
@@ -460,6 +697,19 @@ func (c *Contractor) managedAddPreferredHosts(toAdd int, preferredHosts, potenti
 	}
 }
 
+// scoreLeeways returns the score leeways for GoodForRenew and GoodForUpload
+// from the allowance, falling back to the defaults for unset values.
+func scoreLeeways(a skymodules.Allowance) (gfr, gfu types.Currency) {
+	gfr, gfu = scoreLeewayGoodForRenew, scoreLeewayGoodForUpload
+	if a.ScoreLeewayGFR > 0 {
+		gfr = types.NewCurrency64(a.ScoreLeewayGFR)
+	}
+	if a.ScoreLeewayGFU > 0 {
+		gfu = types.NewCurrency64(a.ScoreLeewayGFU)
+	}
+	return
+}
+
 // managedFindMinAllowedHostScores uses a set of random hosts from the hostdb to
 // calculate minimum acceptable score for a host to be marked GFR and GFU.
 func (c *Contractor) managedFindMinAllowedHostScores() (types.Currency, types.Currency, error) {
@@ -469,6 +719,7 @@ func (c *Contractor) managedFindMinAllowedHostScores() (types.Currency, types.Cu
 	// worthwhile.
 	c.mu.RLock()
 	hostCount := int(c.allowance.Hosts)
+	leewayGFR, leewayGFU := scoreLeeways(c.allowance)
 	c.mu.RUnlock()
 	hosts, err := c.staticHDB.RandomHosts(hostCount+randomHostsBufferForScore, nil, nil)
 	if err != nil {
@@ -498,8 +749,8 @@ func (c *Contractor) managedFindMinAllowedHostScores() (types.Currency, types.Cu
 		}
 	}
 	// Set the minimum acceptable score to a factor of the lowest score.
-	minScoreGFR = lowestScore.Div(scoreLeewayGoodForRenew)
-	minScoreGFU = lowestScore.Div(scoreLeewayGoodForUpload)
+	minScoreGFR = lowestScore.Div(leewayGFR)
+	minScoreGFU = lowestScore.Div(leewayGFU)
 
 	// Set min score to the max score seen times 2.
 	if c.staticDeps.Disrupt("HighMinHostScore") {
@@ -532,6 +783,11 @@ func (c *Contractor) managedNewContract(host skymodules.HostDBEntry, contractFun
 		c.mu.Unlock()
 		return types.ZeroCurrency, skymodules.RenterContract{}, errors.New("called managedNewContract but allowance wasn't set")
 	}
+	// Don't negotiate with a host we already have a contract with.
+	if _, exists := c.pubKeysToContractID[host.PublicKey.String()]; exists {
+		c.mu.Unlock()
+		return types.ZeroCurrency, skymodules.RenterContract{}, errContractWithHostExists
+	}
 	allowance := c.allowance
 	hostSettings := host.HostExternalSettings
 	period := c.allowance.Period
@@ -623,6 +879,7 @@ func (c *Contractor) managedNewContract(host skymodules.HostDBEntry, contractFun
 		// We need to return a funding value because money was spent on this
 		// host, even though the full process could not be completed.
 		c.staticLog.Println("WARN: Attempted to form a new contract with a host that we already have a contrat with.")
+		c.managedRegisterDuplicateFormation(contract.HostPublicKey)
 		return contractFunding, skymodules.RenterContract{}, fmt.Errorf("We already have a contract with host %v", contract.HostPublicKey)
 	}
 	c.pubKeysToContractID[contract.HostPublicKey.String()] = contract.ID
@@ -675,6 +932,82 @@ func (c *Contractor) managedPrunedRedundantAddressRange() {
 	}
 }
 
+// managedPruneSharedNetAddresses finds contracts with different hosts that
+// share the same net address. Out of these contracts, the one that stores the
+// most data is kept and the others are redundant. If the allowance says so,
+// the redundant contracts are canceled, otherwise they are only logged at
+// debug level.
+func (c *Contractor) managedPruneSharedNetAddresses() {
+	c.mu.RLock()
+	cancel := c.allowance.CancelSharedNetAddressContracts
+	c.mu.RUnlock()
+
+	// Get all contracts which are not canceled.
+	var contracts []skymodules.RenterContract
+	for _, contract := range c.staticContracts.ViewAll() {
+		if contract.Utility.Locked && !contract.Utility.GoodForRenew && !contract.Utility.GoodForUpload {
+			// contract is canceled
+			continue
+		}
+		contracts = append(contracts, contract)
+	}
+
+	netAddress := func(pk types.SiaPublicKey) (modules.NetAddress, bool) {
+		host, exists, err := c.staticHDB.Host(pk)
+		if err != nil || !exists {
+			return "", false
+		}
+		return host.NetAddress, true
+	}
+	for _, contract := range redundantNetAddressContracts(contracts, netAddress) {
+		// Without canceling, the same contracts are found on every run, so
+		// they are only logged at debug level.
+		if !cancel {
+			c.staticLog.Debugf("contract %v with host %v shares its net address with another host", contract.ID, contract.HostPublicKey)
+			continue
+		}
+		c.staticLog.Printf("WARN: canceling contract %v with host %v, it shares its net address with another host", contract.ID, contract.HostPublicKey)
+		if err := c.managedCancelContract(contract.ID); err != nil {
+			c.staticLog.Print("WARNING: Wasn't able to cancel contract in managedPruneSharedNetAddresses", err)
+		}
+	}
+}
+
+// redundantNetAddressContracts groups the contracts by the net address of
+// their hosts and returns the contracts that are redundant. Out of every group
+// with different hosts, the contract that stores the most data is not
+// redundant. Contracts with the same host are never considered redundant.
+func redundantNetAddressContracts(contracts []skymodules.RenterContract, netAddress func(types.SiaPublicKey) (modules.NetAddress, bool)) []skymodules.RenterContract {
+	groups := make(map[modules.NetAddress][]skymodules.RenterContract)
+	for _, contract := range contracts {
+		addr, ok := netAddress(contract.HostPublicKey)
+		if !ok || addr == "" {
+			continue
+		}
+		groups[addr] = append(groups[addr], contract)
+	}
+
+	var redundant []skymodules.RenterContract
+	for _, group := range groups {
+		// Keep the contract with the most data, breaking ties by ID to be
+		// deterministic.
+		keep := 0
+		for i := 1; i < len(group); i++ {
+			if group[i].Size() > group[keep].Size() ||
+				(group[i].Size() == group[keep].Size() && bytes.Compare(group[i].ID[:], group[keep].ID[:]) < 0) {
+				keep = i
+			}
+		}
+		for i, contract := range group {
+			if i == keep || contract.HostPublicKey.Equals(group[keep].HostPublicKey) {
+				continue
+			}
+			redundant = append(redundant, contract)
+		}
+	}
+	return redundant
+}
+
 // managedLimitGFUHosts caps the number of GFU hosts to allowance.Hosts.
 func (c *Contractor) managedLimitGFUHosts() {
 	c.mu.Lock()
@@ -964,6 +1297,46 @@ func (c *Contractor) managedRenew(id types.FileContractID, hpk types.SiaPublicKe
 	return newContract, nil
 }
 
+// renewGiveUpHeightReached returns whether the contract is far enough into its
+// renew window for the contractor to give up on renewing it after too many
+// failed attempts. That is the case in the second half of the renew window,
+// which is delayed by the allowance's renew grace for contracts that store
+// enough data.
+func renewGiveUpHeightReached(blockHeight types.BlockHeight, allowance skymodules.Allowance, contract skymodules.RenterContract) bool {
+	window := allowance.RenewWindow / 2
+	if allowance.RenewGraceBlocks < window && contract.Size() >= allowance.RenewGraceDataThreshold {
+		window -= allowance.RenewGraceBlocks
+	}
+	return blockHeight+window >= contract.EndHeight
+}
+
+// invalidateWithTimeout calls invalidate and waits for it to return. An editor
+// or downloader can only be invalidated once its current operation is done,
+// which might take forever if it is stuck on a dead host. So invalidate is
+// launched in a separate thread of the given thread group and false is returned
+// if it didn't return within the timeout or before the thread group was
+// stopped. The invalidation keeps going in the background in that case and
+// takes effect once the stuck operation returns. Since the operation is bound
+// by the deadline of the host connection, shutdown only waits for the thread
+// until the operation times out.
+func invalidateWithTimeout(tg *threadgroup.ThreadGroup, invalidate func(), timeout time.Duration) bool {
+	done := make(chan struct{})
+	err := tg.Launch(func() {
+		invalidate()
+		close(done)
+	})
+	if err != nil {
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+	case <-tg.StopChan():
+	}
+	return false
+}
+
 // managedRenewContract will use the renew instructions to renew a contract,
 // returning the amount of money that was put into the contract for renewal.
 func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal, currentPeriod types.BlockHeight, allowance skymodules.Allowance, blockHeight, endHeight types.BlockHeight) (fundsSpent types.Currency, err error) {
@@ -1006,12 +1379,20 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	c.mu.RUnlock()
 	if eok {
 		c.staticLog.Debugln("Waiting for editor invalidation")
-		e.callInvalidate()
+		if !invalidateWithTimeout(&c.staticTG, e.callInvalidate, renewInvalidationTimeout) {
+			c.staticLog.Printf("WARN: skipping renewal of %v, editor wasn't invalidated within %v", id, renewInvalidationTimeout)
+			err = errors.Compose(errInvalidationTimeout, s.Close())
+			return
+		}
 		c.staticLog.Debugln("Got editor invalidation")
 	}
 	if dok {
 		c.staticLog.Debugln("Waiting for downloader invalidation")
-		d.callInvalidate()
+		if !invalidateWithTimeout(&c.staticTG, d.callInvalidate, renewInvalidationTimeout) {
+			c.staticLog.Printf("WARN: skipping renewal of %v, downloader wasn't invalidated within %v", id, renewInvalidationTimeout)
+			err = errors.Compose(errInvalidationTimeout, s.Close())
+			return
+		}
 		c.staticLog.Debugln("Got downloader invalidation")
 	}
 
@@ -1028,8 +1409,9 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	// Perform the actual renew. If the renew fails, return the
 	// contract. If the renew fails we check how often it has failed
 	// before. Once it has failed for a certain number of blocks in a
-	// row and reached its second half of the renew window, we give up
-	// on renewing it and set goodForRenew to false.
+	// row and reached its second half of the renew window, plus the
+	// renew grace for contracts with a lot of data, we give up on
+	// renewing it and set goodForRenew to false.
 	c.staticLog.Debugln("calling managedRenew on contract", id)
 	newContract, errRenew := c.managedRenew(id, hostPubKey, amount, endHeight, hostSettings)
 	c.staticLog.Debugln("managedRenew has returned with error:", errRenew)
@@ -1054,9 +1436,9 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 		c.mu.RLock()
 		numRenews, failedBefore := c.numFailedRenews[md.ID]
 		c.mu.RUnlock()
-		secondHalfOfWindow := blockHeight+allowance.RenewWindow/2 >= md.EndHeight
+		giveUp := renewGiveUpHeightReached(blockHeight, allowance, md)
 		replace := numRenews >= ConsecutiveRenewalsBeforeReplacement
-		if failedBefore && secondHalfOfWindow && replace {
+		if failedBefore && giveUp && replace {
 			oldUtility.GoodForRenew = false
 			oldUtility.GoodForUpload = false
 			oldUtility.Locked = true
@@ -1097,16 +1479,24 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 		GoodForUpload: true,
 		GoodForRenew:  true,
 	}
-	if err := c.managedAcquireAndUpdateContractUtility(newContract.ID, newUtility); err != nil {
+	err = c.managedRetryRenewUtilityUpdate(func() error {
+		return c.managedAcquireAndUpdateContractUtility(newContract.ID, newUtility)
+	})
+	if err != nil {
 		c.staticLog.Println("Failed to update the contract utilities", err)
+		c.managedRegisterRenewedContractUtilityAlert(newContract.ID, err)
 		c.staticContracts.Return(oldContract)
 		return amount, nil // Error is not returned because the renew succeeded.
 	}
 	oldUtility.GoodForRenew = false
 	oldUtility.GoodForUpload = false
 	oldUtility.Locked = true
-	if err := c.callUpdateUtility(oldContract, oldUtility, true); err != nil {
+	err = c.managedRetryRenewUtilityUpdate(func() error {
+		return c.callUpdateUtility(oldContract, oldUtility, true)
+	})
+	if err != nil {
 		c.staticLog.Println("Failed to update the contract utilities", err)
+		c.managedRegisterRenewedContractUtilityAlert(id, err)
 		c.staticContracts.Return(oldContract)
 		return amount, nil // Error is not returned because the renew succeeded.
 	}
@@ -1119,10 +1509,9 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	// instead of the old contract.
 	c.mu.Lock()
 	// Link Contracts
-	c.renewedFrom[newContract.ID] = id
-	c.renewedTo[id] = newContract.ID
+	c.linkRenewedContracts(id, newContract.ID)
 	// Store the contract in the record of historic contracts.
-	c.oldContracts[id] = oldContract.Metadata()
+	c.oldContracts.set(oldContract.Metadata())
 	// Save the contractor.
 	err = c.save()
 	if err != nil {
@@ -1132,12 +1521,41 @@ func (c *Contractor) managedRenewContract(renewInstructions fileContractRenewal,
 	// Delete the old contract.
 	c.staticContracts.Delete(oldContract)
 
+	// Both utilities were updated, clear the alerts of previous renewals that
+	// failed to update them.
+	c.staticAlerter.UnregisterAlert(alertIDRenewedContractUtility(id))
+	c.staticAlerter.UnregisterAlert(alertIDRenewedContractUtility(newContract.ID))
+
 	// Signal to the watchdog that it should immediately post the last
 	// revision for this contract.
 	go c.staticWatchdog.threadedSendMostRecentRevision(oldContract.Metadata())
 	return amount, nil
 }
 
+// managedRetryRenewUtilityUpdate calls the provided utility update until it
+// succeeds or renewUtilityUpdateRetries retries have failed.
+func (c *Contractor) managedRetryRenewUtilityUpdate(update func() error) error {
+	err := update()
+	for i := 0; err != nil && i < renewUtilityUpdateRetries; i++ {
+		select {
+		case <-c.staticTG.StopChan():
+			return errors.Compose(err, errors.New("contractor is shutting down"))
+		case <-time.After(renewUtilityUpdateRetryInterval):
+		}
+		c.staticLog.Debugln("Retrying failed utility update after renewal:", err)
+		err = update()
+	}
+	return err
+}
+
+// managedRegisterRenewedContractUtilityAlert registers an alert for a renewed
+// contract whose utility couldn't be updated and needs to be repaired by the
+// operator.
+func (c *Contractor) managedRegisterRenewedContractUtilityAlert(fcID types.FileContractID, err error) {
+	cause := fmt.Sprintf("failed to update utility of contract %v: %v", fcID, err)
+	c.staticAlerter.RegisterAlert(alertIDRenewedContractUtility(fcID), AlertMSGRenewedContractUtility, cause, modules.SeverityError)
+}
+
 // managedFindRecoverableContracts will spawn a thread to rescan parts of the
 // blockchain for recoverable contracts if the wallet has been locked during the
 // last scan.
@@ -1197,6 +1615,49 @@ func (c *Contractor) callUpdateUtility(safeContract *proto.SafeContract, newUtil
 	return safeContract.UpdateUtility(newUtility)
 }
 
+// managedTryLockMaintenance tries to acquire the maintenance lock. If another
+// maintenance run holds the lock and the contractor queues maintenance runs,
+// another run is queued for when the active run finishes.
+func (c *Contractor) managedTryLockMaintenance() bool {
+	if c.maintenanceLock.TryLock() {
+		atomic.StoreUint32(&c.atomicMaintenanceQueued, 0)
+		return true
+	}
+	if !c.staticQueueMaintenance {
+		c.staticLog.Debugln("maintenance lock could not be obtained")
+		return false
+	}
+	// Queue another run. The active run might have finished in the meantime
+	// without noticing, so try to acquire the lock once more.
+	atomic.StoreUint32(&c.atomicMaintenanceQueued, 1)
+	if c.maintenanceLock.TryLock() {
+		atomic.StoreUint32(&c.atomicMaintenanceQueued, 0)
+		return true
+	}
+	c.staticLog.Debugln("maintenance lock could not be obtained, queued another run")
+	return false
+}
+
+// queueContractMaintenance returns whether the contractor should queue
+// maintenance runs according to the SKYD_CONTRACT_MAINTENANCE_MODE environment
+// variable. An unknown mode is logged and the contractor falls back to
+// skipping maintenance runs.
+func queueContractMaintenance(l *persist.Logger) bool {
+	mode, ok := build.ContractMaintenanceMode()
+	if !ok {
+		return false
+	}
+	switch mode {
+	case maintenanceModeQueue:
+		return true
+	case maintenanceModeSkip:
+		return false
+	default:
+		l.Printf("WARN: unknown contract maintenance mode '%v', falling back to '%v'", mode, maintenanceModeSkip)
+		return false
+	}
+}
+
 // threadedContractMaintenance checks the set of contracts that the contractor
 // has against the allownace, renewing any contracts that need to be renewed,
 // dropping contracts which are no longer worthwhile, and adding contracts if
@@ -1221,16 +1682,20 @@ func (c *Contractor) threadedContractMaintenance() {
 
 	// Only one instance of this thread should be running at a time. Under
 	// normal conditions, fine to return early if another thread is already
-	// doing maintenance. The next block will trigger another round. Under
-	// testing, control is insufficient if the maintenance loop isn't guaranteed
-	// to run.
+	// doing maintenance. The next block will trigger another round, unless the
+	// contractor queues maintenance runs. Under testing, control is
+	// insufficient if the maintenance loop isn't guaranteed to run.
 	if build.Release == "testing" {
 		c.maintenanceLock.Lock()
-	} else if !c.maintenanceLock.TryLock() {
-		c.staticLog.Debugln("maintenance lock could not be obtained")
+	} else if !c.managedTryLockMaintenance() {
 		return
 	}
-	defer c.maintenanceLock.Unlock()
+	defer func() {
+		c.maintenanceLock.Unlock()
+		if atomic.CompareAndSwapUint32(&c.atomicMaintenanceQueued, 1, 0) {
+			go c.threadedContractMaintenance()
+		}
+	}()
 
 	// Register the WalletLockedDuringMaintenance alert if necessary.
 	var registerWalletLockedDuringMaintenance bool
@@ -1251,6 +1716,8 @@ func (c *Contractor) threadedContractMaintenance() {
 	c.managedCheckForDuplicates()
 	c.managedUpdatePubKeyToContractIDMap()
 	c.managedPrunedRedundantAddressRange()
+	c.managedPruneSharedNetAddresses()
+	c.managedUpdateBackupHostsNeeded()
 	err = c.managedMarkContractsUtility()
 	if err != nil {
 		c.staticLog.Debugln("Unable to mark contract utilities:", err)
@@ -1373,7 +1840,10 @@ func (c *Contractor) threadedContractMaintenance() {
 		sectorDownloadBandwidthPrice := host.DownloadBandwidthPrice.Mul64(modules.SectorSize)
 		sectorBandwidthPrice := sectorUploadBandwidthPrice.Add(sectorDownloadBandwidthPrice)
 		sectorPrice := sectorStoragePrice.Add(sectorBandwidthPrice)
-		percentRemaining, _ := big.NewRat(0, 1).SetFrac(contract.RenterFunds.Big(), contract.TotalCost.Big()).Float64()
+		if contract.TotalCost.IsZero() {
+			c.staticLog.Printf("WARN: contract %v has a zero total cost, treating it as empty", contract.ID)
+		}
+		percentRemaining := percentFundsRemaining(contract.RenterFunds, contract.TotalCost)
 		lowFundsRefresh := c.staticDeps.Disrupt("LowFundsRefresh")
 		if lowFundsRefresh || ((contract.RenterFunds.Cmp(sectorPrice.Mul64(3)) < 0 || percentRemaining < MinContractFundRenewalThreshold) && !c.staticDeps.Disrupt("disableRenew")) {
 			// Renew the contract with double the amount of funds that the
@@ -1578,11 +2048,44 @@ func (c *Contractor) threadedContractMaintenance() {
 	}
 
 	// Form contracts.
-	lf, wl := c.managedFormContracts(fundsRemaining, hosts, neededContracts, allowance, endHeight)
+	fundsRemaining, lf, wl := c.managedFormContracts(fundsRemaining, hosts, neededContracts, allowance, endHeight)
 
 	// Register alerts if necessary.
 	registerLowFundsAlert = registerLowFundsAlert || lf
 	registerWalletLockedDuringMaintenance = registerWalletLockedDuringMaintenance || wl
+	if lf || wl {
+		return
+	}
+
+	// Form contracts with backup hosts if the regular formation couldn't
+	// provide enough contracts.
+	neededContracts, hosts = c.managedHostsForBackupFormation(allowance)
+	_, lf, wl = c.managedFormContracts(fundsRemaining, hosts, neededContracts, allowance, endHeight)
+	registerLowFundsAlert = registerLowFundsAlert || lf
+	registerWalletLockedDuringMaintenance = registerWalletLockedDuringMaintenance || wl
+}
+
+// managedUpdateBackupHostsNeeded updates whether the contracts with backup
+// hosts are currently needed. If they are not, they are dropped when marking
+// the contract utilities.
+func (c *Contractor) managedUpdateBackupHostsNeeded() {
+	c.mu.RLock()
+	allowance := c.allowance
+	c.mu.RUnlock()
+	needed := backupHostsNeeded(allowance, c.staticContracts.ViewAll())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if needed != c.backupHostsNeeded {
+		c.staticLog.Println("Backup hosts needed:", needed)
+	}
+	c.backupHostsNeeded = needed
+}
+
+// managedHostsForBackupFormation returns the number of contracts needed with
+// backup hosts plus the set of backup hosts to use.
+func (c *Contractor) managedHostsForBackupFormation(allowance skymodules.Allowance) (int, []skymodules.HostDBEntry) {
+	return hostsForBackupFormation(allowance, c.staticContracts.ViewAll(), c.RecoverableContracts(), c.staticHDB.Host, c.staticLog)
 }
 
 // managedHostsForPortalFormation returns the hosts to form contracts with for a
@@ -1599,12 +2102,36 @@ func (c *Contractor) managedHostsForPortalFormation(allowance skymodules.Allowan
 // managedHostsForRegularFormation returns the number of hosts needed for
 // non-portal contract formation plus a set of hosts to use.
 func (c *Contractor) managedHostsForRegularFormation(allowance skymodules.Allowance) (int, []skymodules.HostDBEntry) {
-	return hostsForRegularFormation(allowance, c.staticContracts.ViewAll(), c.RecoverableContracts(), c.staticHDB.RandomHosts, c.staticLog)
+	allContracts := c.staticContracts.ViewAll()
+	neededContracts, hosts := hostsForRegularFormation(allowance, allContracts, c.RecoverableContracts(), c.staticHDB.RandomHosts, c.staticLog)
+	if !allowance.PreferDiverseHosts || len(hosts) == 0 {
+		return neededContracts, hosts
+	}
+
+	// Prefer hosts that are on different networks than the hosts of the
+	// contracts we are going to keep.
+	var existing []skymodules.HostDBEntry
+	for _, contract := range allContracts {
+		if !contract.Utility.GoodForUpload {
+			continue
+		}
+		host, ok, err := c.staticHDB.Host(contract.HostPublicKey)
+		if err != nil || !ok {
+			continue
+		}
+		existing = append(existing, host)
+	}
+	return neededContracts, orderHostsByDiversity(hosts, existing)
 }
 
 // managedFormContracts tries to form up to neededContracts with the hosts given
-// by hosts and the provided budget, allowance and endHeight.
-func (c *Contractor) managedFormContracts(budget types.Currency, hosts []skymodules.HostDBEntry, neededContracts int, allowance skymodules.Allowance, endHeight types.BlockHeight) (lowFunds, walletLocked bool) {
+// by hosts and the provided budget, allowance and endHeight. It returns the
+// remaining budget.
+func (c *Contractor) managedFormContracts(budget types.Currency, hosts []skymodules.HostDBEntry, neededContracts int, allowance skymodules.Allowance, endHeight types.BlockHeight) (remaining types.Currency, lowFunds, walletLocked bool) {
+	defer func() {
+		remaining = budget
+	}()
+
 	// Calculate the anticipated transaction fee.
 	_, maxFee := c.staticTPool.FeeEstimation()
 	txnFee := maxFee.Mul64(skymodules.EstimatedFileContractTransactionSetSize)
@@ -1620,91 +2147,193 @@ func (c *Contractor) managedFormContracts(budget types.Currency, hosts []skymodu
 		currentContracts[contract.HostPublicKey.String()] = contract
 	}
 
-	// Form contracts with the hosts one at a time, until we have enough
-	// contracts.
+	// Portals form contracts with all active hosts, so they do so in
+	// parallel.
+	threads := 1
+	if allowance.PortalMode() {
+		threads = portalFormationThreads()
+	}
+	budget, lowFunds, walletLocked = c.managedFormContractsWithThreads(budget, hosts, neededContracts, allowance, endHeight, txnFee, minInitialContractFunds, maxInitialContractFunds, threads)
+	return
+}
+
+// managedFormContractsWithThreads tries to form up to neededContracts with the
+// hosts given by hosts, processing them with the given number of threads.
+// Before a thread negotiates with a host, it reserves the contract's funding
+// from the shared budget and one of the needed contracts, and it returns
+// whatever wasn't spent afterwards. That way the threads never spend more than
+// the budget or form more contracts than needed.
+func (c *Contractor) managedFormContractsWithThreads(budget types.Currency, hosts []skymodules.HostDBEntry, neededContracts int, allowance skymodules.Allowance, endHeight types.BlockHeight, txnFee, minInitialContractFunds, maxInitialContractFunds types.Currency, threads int) (remaining types.Currency, lowFunds, walletLocked bool) {
+	// Queue the hosts.
+	hostChan := make(chan skymodules.HostDBEntry, len(hosts))
 	for _, host := range hosts {
+		hostChan <- host
+	}
+	close(hostChan)
+
+	// stop is set once a thread encounters a condition that should stop all
+	// of the threads.
+	var mu sync.Mutex
+	var stop bool
+
+	// formContract tries to form a contract with the given host. It returns
+	// false if the thread should stop.
+	formContract := func(host skymodules.HostDBEntry) bool {
 		// Return here if an interrupt or kill signal has been sent.
 		select {
 		case <-c.staticTG.StopChan():
 			c.staticLog.Println("returning because the renter was stopped")
-			return
+			return false
 		case <-c.staticInterruptMaintenance:
 			c.staticLog.Println("returning because maintenance was interrupted")
-			return
+			mu.Lock()
+			stop = true
+			mu.Unlock()
+			return false
 		default:
 		}
 
-		// If no more contracts are needed, break.
-		if neededContracts <= 0 {
-			break
-		}
-
 		// Calculate the contract funding with host
 		contractFunds := initialContractFunding(allowance, host, txnFee, minInitialContractFunds, maxInitialContractFunds)
 
 		// Confirm the wallet is still unlocked
 		unlocked, err := c.staticWallet.Unlocked()
 		if !unlocked || err != nil {
-			walletLocked = true
 			c.staticLog.Println("contractor is attempting to establish new contracts with hosts, however the wallet is locked")
-			return
+			mu.Lock()
+			walletLocked = true
+			stop = true
+			mu.Unlock()
+			return false
 		}
 
-		// Determine if we have enough money to form a new contract.
+		// Reserve the funding and the contract. If no more contracts are
+		// needed or there is not enough money left, stop.
+		mu.Lock()
+		if stop || neededContracts <= 0 {
+			mu.Unlock()
+			return false
+		}
 		if budget.Cmp(contractFunds) < 0 || c.staticDeps.Disrupt("LowFundsFormation") {
 			lowFunds = true
+			stop = true
+			mu.Unlock()
 			c.staticLog.Println("WARN: need to form new contracts, but unable to because of a low allowance")
-			break
+			return false
 		}
-
-		// If we are using a custom resolver we need to replace the domain name
-		// with 127.0.0.1 to be able to form contracts.
-		if c.staticDeps.Disrupt("customResolver") {
-			port := host.NetAddress.Port()
-			host.NetAddress = modules.NetAddress(fmt.Sprintf("127.0.0.1:%s", port))
-		}
-
-		// Attempt forming a contract with this host.
-		start := time.Now()
-		fundsSpent, newContract, err := c.managedNewContract(host, contractFunds, endHeight)
-		if err != nil {
-			c.staticLog.Printf("Attempted to form a contract with %v, time spent %v, but negotiation failed: %v\n", host.NetAddress, time.Since(start).Round(time.Millisecond), err)
-			continue
-		}
-		budget = budget.Sub(fundsSpent)
+		budget = budget.Sub(contractFunds)
 		neededContracts--
+		mu.Unlock()
 
-		sb, err := c.staticHDB.ScoreBreakdown(host)
-		if err == nil {
-			c.staticLog.Println("A new contract has been formed with a host:", newContract.ID)
-			c.staticLog.Println("Score:    ", sb.Score)
-			c.staticLog.Println("Age Adjustment:        ", sb.AgeAdjustment)
-			c.staticLog.Println("Base Price Adjustment: ", sb.BasePriceAdjustment)
-			c.staticLog.Println("Burn Adjustment:       ", sb.BurnAdjustment)
-			c.staticLog.Println("Collateral Adjustment: ", sb.CollateralAdjustment)
-			c.staticLog.Println("Duration Adjustment:   ", sb.DurationAdjustment)
-			c.staticLog.Println("Interaction Adjustment:", sb.InteractionAdjustment)
-			c.staticLog.Println("Price Adjustment:      ", sb.PriceAdjustment)
-			c.staticLog.Println("Storage Adjustment:    ", sb.StorageRemainingAdjustment)
-			c.staticLog.Println("Uptime Adjustment:     ", sb.UptimeAdjustment)
-			c.staticLog.Println("Version Adjustment:    ", sb.VersionAdjustment)
-		}
-
-		// Add this contract to the contractor and save.
-		err = c.managedAcquireAndUpdateContractUtility(newContract.ID, skymodules.ContractUtility{
-			GoodForUpload: true,
-			GoodForRenew:  true,
-		})
+		// Attempt forming a contract with this host and return whatever
+		// wasn't used of the reservation.
+		fundsSpent, newContract, err := c.managedFormContractWithHost(host, contractFunds, endHeight)
+		mu.Lock()
+		budget = budget.Add(contractFunds).Sub(fundsSpent)
 		if err != nil {
-			c.staticLog.Println("Failed to update the contract utilities", err)
-			return
+			neededContracts++
 		}
-		c.mu.Lock()
-		err = c.save()
-		c.mu.Unlock()
+		mu.Unlock()
 		if err != nil {
-			c.staticLog.Println("Unable to save the contractor:", err)
+			return true
 		}
+
+		// Add this contract to the contractor and save.
+		if err := c.managedAddFormedContract(host, newContract); err != nil {
+			mu.Lock()
+			stop = true
+			mu.Unlock()
+			return false
+		}
+		return true
+	}
+
+	// Launch the threads and wait for them to finish.
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range hostChan {
+				if !formContract(host) {
+					return
+				}
+			}
+		}()
 	}
-	return
+	wg.Wait()
+	return budget, lowFunds, walletLocked
+}
+
+// managedFormContractWithHost attempts to form a contract with a host and
+// tracks the formation metrics. It returns the funds spent on the contract.
+func (c *Contractor) managedFormContractWithHost(host skymodules.HostDBEntry, contractFunds types.Currency, endHeight types.BlockHeight) (types.Currency, skymodules.RenterContract, error) {
+	// If we are using a custom resolver we need to replace the domain name
+	// with 127.0.0.1 to be able to form contracts.
+	if c.staticDeps.Disrupt("customResolver") {
+		port := host.NetAddress.Port()
+		host.NetAddress = modules.NetAddress(fmt.Sprintf("127.0.0.1:%s", port))
+	}
+
+	// Attempt forming a contract with this host.
+	start := time.Now()
+	fundsSpent, newContract, err := c.managedNewContract(host, contractFunds, endHeight)
+	if errors.Contains(err, errContractWithHostExists) {
+		// The contractor didn't negotiate with the host, so the attempt is
+		// not tracked in the formation metrics.
+		return fundsSpent, newContract, err
+	}
+	c.staticFormationMetrics.callAddFormation(time.Since(start), err == nil)
+	if err != nil {
+		c.staticLog.Printf("Attempted to form a contract with %v, time spent %v, but negotiation failed: %v\n", host.NetAddress, time.Since(start).Round(time.Millisecond), err)
+	}
+	return fundsSpent, newContract, err
+}
+
+// managedAddFormedContract logs the score of the host a new contract was
+// formed with, marks the contract as good for upload and renew and saves the
+// contractor.
+func (c *Contractor) managedAddFormedContract(host skymodules.HostDBEntry, newContract skymodules.RenterContract) error {
+	sb, err := c.staticHDB.ScoreBreakdown(host)
+	if err == nil {
+		c.staticLog.Println("A new contract has been formed with a host:", newContract.ID)
+		c.staticLog.Println("Score:    ", sb.Score)
+		c.staticLog.Println("Age Adjustment:        ", sb.AgeAdjustment)
+		c.staticLog.Println("Base Price Adjustment: ", sb.BasePriceAdjustment)
+		c.staticLog.Println("Burn Adjustment:       ", sb.BurnAdjustment)
+		c.staticLog.Println("Collateral Adjustment: ", sb.CollateralAdjustment)
+		c.staticLog.Println("Duration Adjustment:   ", sb.DurationAdjustment)
+		c.staticLog.Println("Interaction Adjustment:", sb.InteractionAdjustment)
+		c.staticLog.Println("Price Adjustment:      ", sb.PriceAdjustment)
+		c.staticLog.Println("Storage Adjustment:    ", sb.StorageRemainingAdjustment)
+		c.staticLog.Println("Uptime Adjustment:     ", sb.UptimeAdjustment)
+		c.staticLog.Println("Version Adjustment:    ", sb.VersionAdjustment)
+	}
+
+	// Add this contract to the contractor and save.
+	err = c.managedAcquireAndUpdateContractUtility(newContract.ID, skymodules.ContractUtility{
+		GoodForUpload: true,
+		GoodForRenew:  true,
+	})
+	if err != nil {
+		c.staticLog.Println("Failed to update the contract utilities", err)
+		return err
+	}
+	c.mu.Lock()
+	err = c.save()
+	c.mu.Unlock()
+	if err != nil {
+		c.staticLog.Println("Unable to save the contractor:", err)
+	}
+	return nil
+}
+
+// portalFormationThreads returns the configured number of threads a portal
+// uses to form contracts in parallel.
+func portalFormationThreads() int {
+	threads, ok := build.PortalFormationThreads()
+	if !ok || threads < 1 {
+		return defaultPortalFormationThreads
+	}
+	return threads
 }
@@ -3,10 +3,14 @@ package contractor
 import (
 	"io/ioutil"
 	"math"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
+	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/NebulousLabs/threadgroup"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
@@ -80,6 +84,7 @@ func TestInitialContractFunding(t *testing.T) {
 		txnFee        uint64
 		min           uint64
 		max           uint64
+		floor         uint64
 		result        uint64
 	}{
 		{
@@ -127,12 +132,63 @@ func TestInitialContractFunding(t *testing.T) {
 			max:           math.MaxUint64,
 			result:        math.MaxUint64,
 		},
+		{
+			// Floor below min has no effect.
+			pcif:          0,
+			contractPrice: 100,
+			txnFee:        200,
+			min:           5000,
+			max:           10000,
+			floor:         4000,
+			result:        5000,
+		},
+		{
+			// Floor above min.
+			pcif:          0,
+			contractPrice: 100,
+			txnFee:        200,
+			min:           1,
+			max:           10000,
+			floor:         4000,
+			result:        4000,
+		},
+		{
+			// Floor overrules max.
+			pcif:          0,
+			contractPrice: 100,
+			txnFee:        200,
+			min:           1,
+			max:           2000,
+			floor:         4000,
+			result:        4000,
+		},
+		{
+			// Floor with no max.
+			pcif:          0,
+			contractPrice: 100,
+			txnFee:        200,
+			min:           1,
+			max:           0,
+			floor:         4000,
+			result:        4000,
+		},
+		{
+			// Portal mode overrules floor.
+			pcif:          42,
+			contractPrice: 100,
+			txnFee:        200,
+			min:           1,
+			max:           0,
+			floor:         4000,
+			result:        42,
+		},
 	}
 
 	// Run tests
 	for i, test := range tests {
 		a := skymodules.Allowance{
 			PaymentContractInitialFunding: types.NewCurrency64(test.pcif),
+			MinContractFunding:            types.NewCurrency64(test.floor),
 		}
 		host := skymodules.HostDBEntry{
 			HostExternalSettings: modules.HostExternalSettings{
@@ -339,3 +395,588 @@ func TestHostsForRegularFormation(t *testing.T) {
 		t.Fatal("needed not set")
 	}
 }
+
+// TestHostsForBackupFormation is a unit test for hostsForBackupFormation and
+// backupHostsNeeded.
+func TestHostsForBackupFormation(t *testing.T) {
+	t.Parallel()
+
+	l, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	randomPK := func() types.SiaPublicKey {
+		var spk types.SiaPublicKey
+		spk.Key = fastrand.Bytes(crypto.PublicKeySize)
+		return spk
+	}
+
+	// Create an allowance with 3 backup hosts.
+	backup1, backup2, backup3 := randomPK(), randomPK(), randomPK()
+	a := skymodules.DefaultAllowance
+	a.Hosts = 4
+	a.BackupHosts = []types.SiaPublicKey{backup1, backup2, backup3}
+	a.BackupHostsThreshold = 0.5
+
+	// Create 2 gfu contracts with primary hosts, one contract with a backup
+	// host and a recoverable contract with another backup host.
+	gfu := skymodules.ContractUtility{GoodForUpload: true, GoodForRenew: true}
+	allContracts := []skymodules.RenterContract{
+		{HostPublicKey: randomPK(), Utility: gfu},
+		{HostPublicKey: randomPK(), Utility: gfu},
+		{HostPublicKey: backup1, Utility: gfu},
+	}
+	recoverableContracts := []skymodules.RecoverableContract{
+		{HostPublicKey: backup2},
+	}
+	hostFn := func(pk types.SiaPublicKey) (skymodules.HostDBEntry, bool, error) {
+		return skymodules.HostDBEntry{PublicKey: pk}, true, nil
+	}
+
+	// 2 primary contracts are not below the threshold.
+	if backupHostsNeeded(a, allContracts) {
+		t.Fatal("backup hosts shouldn't be needed")
+	}
+	needed, hosts := hostsForBackupFormation(a, allContracts, recoverableContracts, hostFn, l)
+	if needed != 0 || len(hosts) != 0 {
+		t.Fatal("unexpected result", needed, hosts)
+	}
+
+	// Mark a primary contract !gfu. Now we are below the threshold and need
+	// 2 more contracts. Only backup3 is available though.
+	allContracts[0].Utility.GoodForUpload = false
+	if !backupHostsNeeded(a, allContracts) {
+		t.Fatal("backup hosts should be needed")
+	}
+	needed, hosts = hostsForBackupFormation(a, allContracts, recoverableContracts, hostFn, l)
+	if needed != 1 || len(hosts) != 1 || !hosts[0].PublicKey.Equals(backup3) {
+		t.Fatal("unexpected result", needed, hosts)
+	}
+
+	// Filtered backup hosts are ignored.
+	filteredHostFn := func(pk types.SiaPublicKey) (skymodules.HostDBEntry, bool, error) {
+		return skymodules.HostDBEntry{PublicKey: pk, Filtered: true}, true, nil
+	}
+	needed, hosts = hostsForBackupFormation(a, allContracts, recoverableContracts, filteredHostFn, l)
+	if needed != 0 || len(hosts) != 0 {
+		t.Fatal("unexpected result", needed, hosts)
+	}
+
+	// Without backup hosts they are never needed.
+	a.BackupHosts = nil
+	if backupHostsNeeded(a, allContracts) {
+		t.Fatal("backup hosts shouldn't be needed")
+	}
+}
+
+// TestPercentFundsRemaining is a unit test for percentFundsRemaining.
+func TestPercentFundsRemaining(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		renterFunds types.Currency
+		totalCost   types.Currency
+		expected    float64
+	}{
+		{types.NewCurrency64(50), types.NewCurrency64(100), 0.5},
+		{types.NewCurrency64(100), types.NewCurrency64(100), 1},
+		{types.ZeroCurrency, types.NewCurrency64(100), 0},
+		// A contract with a zero total cost is considered empty.
+		{types.NewCurrency64(100), types.ZeroCurrency, 0},
+		{types.ZeroCurrency, types.ZeroCurrency, 0},
+	}
+	for i, test := range tests {
+		percent := percentFundsRemaining(test.renterFunds, test.totalCost)
+		if percent != test.expected {
+			t.Errorf("%v: expected %v but got %v", i, test.expected, percent)
+		}
+		// A zero total cost contract should always be below the refresh
+		// threshold.
+		if test.totalCost.IsZero() && percent >= MinContractFundRenewalThreshold {
+			t.Errorf("%v: zero total cost contract wouldn't be refreshed", i)
+		}
+	}
+}
+
+// TestApplyRenewFundingFloor is a unit test for applyRenewFundingFloor.
+func TestApplyRenewFundingFloor(t *testing.T) {
+	t.Parallel()
+
+	low := types.NewCurrency64(50)
+	minimum := types.NewCurrency64(100)
+	high := types.NewCurrency64(150)
+	tests := []struct {
+		estimate     types.Currency
+		dataStored   uint64
+		disableFloor bool
+		funding      types.Currency
+		floorApplied bool
+	}{
+		// An estimate above the floor is never changed.
+		{high, 0, false, high, false},
+		{high, 1, true, high, false},
+		// An estimate below the floor is raised by default.
+		{low, 0, false, minimum, true},
+		{low, 1, false, minimum, true},
+		// Disabling the floor only affects contracts that store data.
+		{low, 0, true, minimum, true},
+		{low, 1, true, low, false},
+	}
+	for i, test := range tests {
+		funding, floorApplied := applyRenewFundingFloor(test.estimate, minimum, test.dataStored, test.disableFloor)
+		if !funding.Equals(test.funding) || floorApplied != test.floorApplied {
+			t.Errorf("%v: expected %v %v but got %v %v", i, test.funding, test.floorApplied, funding, floorApplied)
+		}
+	}
+}
+
+// TestInvalidateWithTimeout is a unit test for invalidateWithTimeout.
+func TestInvalidateWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	var tg threadgroup.ThreadGroup
+
+	// An invalidation that returns right away succeeds.
+	var invalidated bool
+	if !invalidateWithTimeout(&tg, func() { invalidated = true }, time.Second) {
+		t.Fatal("invalidation should succeed")
+	}
+	if !invalidated {
+		t.Fatal("invalidate wasn't called")
+	}
+
+	// A stuck invalidation times out.
+	unblock := make(chan struct{})
+	stuck := func() { <-unblock }
+	if invalidateWithTimeout(&tg, stuck, 10*time.Millisecond) {
+		t.Fatal("invalidation should time out")
+	}
+
+	// A stuck invalidation is abandoned on shutdown, but shutdown waits for
+	// the invalidation to return.
+	stopped := make(chan error)
+	go func() {
+		stopped <- tg.Stop()
+	}()
+	<-tg.StopChan()
+	if invalidateWithTimeout(&tg, stuck, time.Hour) {
+		t.Fatal("invalidation should be abandoned")
+	}
+	select {
+	case <-stopped:
+		t.Fatal("thread group shouldn't be stopped before the invalidation returns")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(unblock)
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRetryRenewUtilityUpdate is a unit test for
+// managedRetryRenewUtilityUpdate and the alert that is registered if the
+// utility update after a renewal fails.
+func TestRetryRenewUtilityUpdate(t *testing.T) {
+	t.Parallel()
+
+	l, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Contractor{
+		staticAlerter: modules.NewAlerter("contractor"),
+		staticLog:     l,
+	}
+
+	// An update that fails twice should succeed on the third try.
+	var calls int
+	err = c.managedRetryRenewUtilityUpdate(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatal("unexpected", err, calls)
+	}
+
+	// An update that always fails should be tried once plus the number of
+	// retries.
+	calls = 0
+	err = c.managedRetryRenewUtilityUpdate(func() error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil || calls != renewUtilityUpdateRetries+1 {
+		t.Fatal("unexpected", err, calls)
+	}
+
+	// Register the alert.
+	fcID := types.FileContractID{1}
+	c.managedRegisterRenewedContractUtilityAlert(fcID, err)
+	_, errAlerts, _ := c.Alerts()
+	var found bool
+	for _, alert := range errAlerts {
+		found = found || alert.Msg == AlertMSGRenewedContractUtility
+	}
+	if !found {
+		t.Fatal("alert wasn't registered", errAlerts)
+	}
+}
+
+// TestScoreLeeways is a unit test for scoreLeeways.
+func TestScoreLeeways(t *testing.T) {
+	t.Parallel()
+
+	// Unset values fall back to the defaults.
+	gfr, gfu := scoreLeeways(skymodules.Allowance{})
+	if !gfr.Equals(scoreLeewayGoodForRenew) || !gfu.Equals(scoreLeewayGoodForUpload) {
+		t.Fatal("wrong defaults", gfr, gfu)
+	}
+
+	// Set values are used.
+	gfr, gfu = scoreLeeways(skymodules.Allowance{ScoreLeewayGFR: 100, ScoreLeewayGFU: 10})
+	if !gfr.Equals64(100) || !gfu.Equals64(10) {
+		t.Fatal("wrong leeways", gfr, gfu)
+	}
+}
+
+// TestLinkRenewedContracts tests that linkRenewedContracts reconciles the
+// renewal maps after a renewal was interrupted before linking the contracts.
+func TestLinkRenewedContracts(t *testing.T) {
+	t.Parallel()
+
+	c := &Contractor{
+		renewedFrom:             make(map[types.FileContractID]types.FileContractID),
+		renewedTo:               make(map[types.FileContractID]types.FileContractID),
+		uploadDisabledContracts: make(map[types.FileContractID]struct{}),
+		contractNotes:           make(map[types.FileContractID]string),
+	}
+
+	// A was renewed to B. The renewal of B to C was interrupted after C was
+	// formed, which left C unlinked. A stale link from a previous attempt
+	// points from X to C.
+	a, b, c2, x := types.FileContractID{'a'}, types.FileContractID{'b'}, types.FileContractID{'c'}, types.FileContractID{'x'}
+	c.renewedTo[a] = b
+	c.renewedFrom[b] = a
+	c.renewedTo[x] = c2
+	c.renewedFrom[c2] = x
+	c.uploadDisabledContracts[b] = struct{}{}
+	c.contractNotes[b] = "note"
+
+	// Link the contracts twice to make sure it is safe to retry.
+	for i := 0; i < 2; i++ {
+		c.linkRenewedContracts(b, c2)
+
+		if c.renewedTo[a] != b || c.renewedFrom[b] != a {
+			t.Fatal("link between a and b was changed")
+		}
+		if c.renewedTo[b] != c2 || c.renewedFrom[c2] != b {
+			t.Fatal("b and c weren't linked")
+		}
+		if _, exists := c.renewedTo[x]; exists {
+			t.Fatal("stale link wasn't removed")
+		}
+		if len(c.renewedTo) != 2 || len(c.renewedFrom) != 2 {
+			t.Fatal("wrong number of links", len(c.renewedTo), len(c.renewedFrom))
+		}
+		if _, disabled := c.uploadDisabledContracts[c2]; !disabled || len(c.uploadDisabledContracts) != 1 {
+			t.Fatal("disabled upload utility wasn't carried over")
+		}
+		if c.contractNotes[c2] != "note" || len(c.contractNotes) != 1 {
+			t.Fatal("note wasn't carried over")
+		}
+	}
+
+	// Linking b to a different contract removes the link to c.
+	d := types.FileContractID{'d'}
+	c.linkRenewedContracts(b, d)
+	if c.renewedTo[b] != d || c.renewedFrom[d] != b {
+		t.Fatal("b and d weren't linked")
+	}
+	if _, exists := c.renewedFrom[c2]; exists {
+		t.Fatal("stale link to c wasn't removed")
+	}
+}
+
+// TestRedundantNetAddressContracts is a unit test for
+// redundantNetAddressContracts.
+func TestRedundantNetAddressContracts(t *testing.T) {
+	t.Parallel()
+
+	// newContract creates a contract with the given host and size.
+	newContract := func(id byte, host string, size uint64) skymodules.RenterContract {
+		return skymodules.RenterContract{
+			ID:            types.FileContractID{id},
+			HostPublicKey: types.SiaPublicKey{Key: []byte(host)},
+			Transaction: types.Transaction{
+				FileContractRevisions: []types.FileContractRevision{{NewFileSize: size}},
+			},
+		}
+	}
+
+	// Hosts a and b share an address, c has its own and d is unknown.
+	addresses := map[string]modules.NetAddress{
+		"a": "host1.com:9982",
+		"b": "host1.com:9982",
+		"c": "host2.com:9982",
+	}
+	netAddress := func(pk types.SiaPublicKey) (modules.NetAddress, bool) {
+		addr, ok := addresses[string(pk.Key)]
+		return addr, ok
+	}
+
+	// b stores more data than a, so a is redundant.
+	a := newContract(1, "a", 10)
+	b := newContract(2, "b", 20)
+	c := newContract(3, "c", 10)
+	d := newContract(4, "d", 10)
+	redundant := redundantNetAddressContracts([]skymodules.RenterContract{a, b, c, d}, netAddress)
+	if len(redundant) != 1 || redundant[0].ID != a.ID {
+		t.Fatal("unexpected redundant contracts", redundant)
+	}
+
+	// With equal sizes, the contract with the smaller id is kept.
+	b = newContract(2, "b", 10)
+	redundant = redundantNetAddressContracts([]skymodules.RenterContract{b, a, c, d}, netAddress)
+	if len(redundant) != 1 || redundant[0].ID != b.ID {
+		t.Fatal("unexpected redundant contracts", redundant)
+	}
+
+	// Multiple contracts with the same host are not redundant.
+	a2 := newContract(5, "a", 5)
+	redundant = redundantNetAddressContracts([]skymodules.RenterContract{a, a2, c}, netAddress)
+	if len(redundant) != 0 {
+		t.Fatal("unexpected redundant contracts", redundant)
+	}
+}
+
+// TestResolveDuplicateContracts is a unit test for resolveDuplicateContracts.
+func TestResolveDuplicateContracts(t *testing.T) {
+	t.Parallel()
+
+	contract := func(id byte, startHeight types.BlockHeight, size uint64, funds uint64) skymodules.RenterContract {
+		return skymodules.RenterContract{
+			ID:          types.FileContractID{id},
+			StartHeight: startHeight,
+			RenterFunds: types.NewCurrency64(funds),
+			Transaction: types.Transaction{
+				FileContractRevisions: []types.FileContractRevision{{NewFileSize: size}},
+			},
+		}
+	}
+
+	// a is newer, b stores more data and c has more funds.
+	a := contract('a', 20, 10, 10)
+	b := contract('b', 10, 20, 10)
+	c := contract('c', 10, 10, 20)
+
+	tests := []struct {
+		strategy skymodules.DuplicateContractStrategy
+		x, y     skymodules.RenterContract
+		keep     types.FileContractID
+	}{
+		{"", a, b, a.ID},
+		{"", b, a, a.ID},
+		{skymodules.DuplicateContractStrategyStartHeight, b, a, a.ID},
+		{skymodules.DuplicateContractStrategyDataStored, a, b, b.ID},
+		{skymodules.DuplicateContractStrategyDataStored, b, a, b.ID},
+		{skymodules.DuplicateContractStrategyRenterFunds, a, c, c.ID},
+		{skymodules.DuplicateContractStrategyRenterFunds, c, a, c.ID},
+
+		// Ties are resolved by start height.
+		{skymodules.DuplicateContractStrategyDataStored, a, c, a.ID},
+		{skymodules.DuplicateContractStrategyRenterFunds, b, a, a.ID},
+	}
+	for i, test := range tests {
+		newContract, oldContract := resolveDuplicateContracts(test.strategy, test.x, test.y)
+		if newContract.ID != test.keep {
+			t.Errorf("%v: wrong contract kept, expected %v but got %v", i, test.keep, newContract.ID)
+		}
+		if oldContract.ID == newContract.ID {
+			t.Errorf("%v: old and new contract are the same", i)
+		}
+	}
+}
+
+// TestRenewGiveUpHeightReached is a unit test for renewGiveUpHeightReached.
+func TestRenewGiveUpHeightReached(t *testing.T) {
+	t.Parallel()
+
+	// Create a contract which stores 100 bytes and ends at height 1000.
+	contract := skymodules.RenterContract{
+		EndHeight: 1000,
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{{NewFileSize: 100}},
+		},
+	}
+	allowance := skymodules.Allowance{RenewWindow: 100}
+
+	// Without grace, the contractor gives up in the second half of the
+	// window.
+	if renewGiveUpHeightReached(949, allowance, contract) {
+		t.Fatal("shouldn't give up before the second half of the window")
+	}
+	if !renewGiveUpHeightReached(950, allowance, contract) {
+		t.Fatal("should give up in the second half of the window")
+	}
+
+	// With grace, the contractor keeps trying for longer.
+	allowance.RenewGraceBlocks = 20
+	allowance.RenewGraceDataThreshold = 100
+	if renewGiveUpHeightReached(969, allowance, contract) {
+		t.Fatal("shouldn't give up during the grace")
+	}
+	if !renewGiveUpHeightReached(970, allowance, contract) {
+		t.Fatal("should give up after the grace")
+	}
+
+	// Contracts below the data threshold don't get the grace.
+	allowance.RenewGraceDataThreshold = 101
+	if !renewGiveUpHeightReached(950, allowance, contract) {
+		t.Fatal("contract below the threshold shouldn't get the grace")
+	}
+}
+
+// TestManagedTryLockMaintenance is a unit test for managedTryLockMaintenance.
+func TestManagedTryLockMaintenance(t *testing.T) {
+	t.Parallel()
+
+	l, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Contractor{staticLog: l}
+
+	// Without queueing, a trigger during an active run is dropped.
+	if !c.managedTryLockMaintenance() {
+		t.Fatal("should be able to acquire the lock")
+	}
+	if c.managedTryLockMaintenance() {
+		t.Fatal("shouldn't be able to acquire the lock twice")
+	}
+	if c.atomicMaintenanceQueued != 0 {
+		t.Fatal("run shouldn't be queued")
+	}
+	c.maintenanceLock.Unlock()
+
+	// With queueing, a trigger during an active run is queued.
+	c.staticQueueMaintenance = true
+	if !c.managedTryLockMaintenance() {
+		t.Fatal("should be able to acquire the lock")
+	}
+	if c.managedTryLockMaintenance() {
+		t.Fatal("shouldn't be able to acquire the lock twice")
+	}
+	if c.atomicMaintenanceQueued != 1 {
+		t.Fatal("run should be queued")
+	}
+	c.maintenanceLock.Unlock()
+
+	// Acquiring the lock covers the queued run.
+	if !c.managedTryLockMaintenance() {
+		t.Fatal("should be able to acquire the lock")
+	}
+	if c.atomicMaintenanceQueued != 0 {
+		t.Fatal("queued run should be cleared")
+	}
+	c.maintenanceLock.Unlock()
+}
+
+// TestQueueContractMaintenance is a unit test for queueContractMaintenance.
+func TestQueueContractMaintenance(t *testing.T) {
+	l, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Unsetenv("SKYD_CONTRACT_MAINTENANCE_MODE"); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	tests := []struct {
+		mode  string
+		queue bool
+	}{
+		{maintenanceModeQueue, true},
+		{maintenanceModeSkip, false},
+		{"unknown", false},
+	}
+	for _, test := range tests {
+		if err := os.Setenv("SKYD_CONTRACT_MAINTENANCE_MODE", test.mode); err != nil {
+			t.Fatal(err)
+		}
+		if queue := queueContractMaintenance(l); queue != test.queue {
+			t.Fatalf("mode '%v': expected %v, got %v", test.mode, test.queue, queue)
+		}
+	}
+
+	// Without the environment variable, maintenance runs aren't queued.
+	if err := os.Unsetenv("SKYD_CONTRACT_MAINTENANCE_MODE"); err != nil {
+		t.Fatal(err)
+	}
+	if queueContractMaintenance(l) {
+		t.Fatal("maintenance shouldn't be queued by default")
+	}
+}
+
+// TestOrderHostsByDiversity is a unit test for orderHostsByDiversity.
+func TestOrderHostsByDiversity(t *testing.T) {
+	t.Parallel()
+
+	// newHost is a helper to create a host on the given subnets.
+	newHost := func(ipNets ...string) skymodules.HostDBEntry {
+		var host skymodules.HostDBEntry
+		host.IPNets = ipNets
+		host.PublicKey = types.SiaPublicKey{Key: fastrand.Bytes(32)}
+		return host
+	}
+
+	// Check the networks of a host.
+	networks := hostNetworks(newHost("1.2.3.0/24", "2001:db8:1:2::/54", "invalid"))
+	if !reflect.DeepEqual(networks, []string{"1.2.0.0/16", "2001:db8::/32"}) {
+		t.Fatal("unexpected networks", networks)
+	}
+	networks = hostNetworks(newHost())
+	if !reflect.DeepEqual(networks, []string{""}) {
+		t.Fatal("unexpected networks", networks)
+	}
+
+	// We already have a contract with a host in 1.2.0.0/16.
+	existing := []skymodules.HostDBEntry{newHost("1.2.3.0/24")}
+
+	// Create hosts where the first two share a network with the existing
+	// host and the last three share a network with each other.
+	sameAsExisting1 := newHost("1.2.4.0/24")
+	sameAsExisting2 := newHost("1.2.5.0/24")
+	shared1 := newHost("3.4.1.0/24")
+	shared2 := newHost("3.4.2.0/24")
+	unique := newHost("5.6.1.0/24")
+	hosts := []skymodules.HostDBEntry{sameAsExisting1, sameAsExisting2, shared1, shared2, unique}
+
+	// The hosts on new networks come first, in their original order. After
+	// that, every network is used once and the hosts are picked from the
+	// least used networks.
+	expected := []skymodules.HostDBEntry{shared1, unique, sameAsExisting1, shared2, sameAsExisting2}
+	ordered := orderHostsByDiversity(hosts, existing)
+	if len(ordered) != len(expected) {
+		t.Fatal("wrong number of hosts", len(ordered))
+	}
+	for i := range ordered {
+		if !ordered[i].PublicKey.Equals(expected[i].PublicKey) {
+			t.Fatalf("host %v: expected %v, got %v", i, expected[i].IPNets, ordered[i].IPNets)
+		}
+	}
+
+	// Without existing hosts, the order is only determined by the hosts.
+	ordered = orderHostsByDiversity(hosts, nil)
+	expected = []skymodules.HostDBEntry{sameAsExisting1, shared1, unique, sameAsExisting2, shared2}
+	for i := range ordered {
+		if !ordered[i].PublicKey.Equals(expected[i].PublicKey) {
+			t.Fatalf("host %v: expected %v, got %v", i, expected[i].IPNets, ordered[i].IPNets)
+		}
+	}
+}
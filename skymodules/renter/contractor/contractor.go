@@ -3,6 +3,7 @@ package contractor
 import (
 	"bytes"
 	"io"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
@@ -65,8 +66,18 @@ type Contractor struct {
 	persistDir string
 
 	// Only one thread should be performing contract maintenance at a time.
+	// If staticQueueMaintenance is set, a maintenance trigger that arrives
+	// while another run is active sets atomicMaintenanceQueued and the
+	// maintenance runs again once the active run finishes.
 	staticInterruptMaintenance chan struct{}
 	maintenanceLock            siasync.TryMutex
+	atomicMaintenanceQueued    uint32
+	staticQueueMaintenance     bool
+
+	// Only one thread should change the allowance at a time. This prevents a
+	// temporary allowance revert from overwriting an allowance that was set
+	// concurrently.
+	allowanceLock sync.Mutex
 
 	// Only one thread should be scanning the blockchain for recoverable
 	// contracts at a time.
@@ -91,6 +102,10 @@ type Contractor struct {
 	numFailedRenews map[types.FileContractID]types.BlockHeight
 	renewing        map[types.FileContractID]bool // prevent revising during renewal
 
+	// backupHostsNeeded indicates whether the contracts with the allowance's
+	// backup hosts are currently needed.
+	backupHostsNeeded bool
+
 	// pubKeysToContractID is a map of host pubkeys to the latest contract ID
 	// that is formed with the host. The contract also has to have an end height
 	// in the future
@@ -100,16 +115,26 @@ type Contractor struct {
 	// renewedTo links the old contract's ID to the new contract's ID
 	// doubleSpentContracts keep track of all contracts that were double spent by
 	// either the renter or host.
-	staticContracts      *proto.ContractSet
-	oldContracts         map[types.FileContractID]skymodules.RenterContract
-	preferredHosts       map[string]struct{}
-	doubleSpentContracts map[types.FileContractID]types.BlockHeight
-	recoverableContracts map[types.FileContractID]skymodules.RecoverableContract
-	renewedFrom          map[types.FileContractID]types.FileContractID
-	renewedTo            map[types.FileContractID]types.FileContractID
-
-	staticChurnLimiter *churnLimiter
-	staticWatchdog     *watchdog
+	// uploadDisabledContracts keeps track of all active contracts that were
+	// manually marked as not good for upload.
+	// contractNotes contains the free-form notes set by the user on contracts.
+	staticContracts         *proto.ContractSet
+	oldContracts            *oldContractStore
+	preferredHosts          map[string]struct{}
+	doubleSpentContracts    map[types.FileContractID]types.BlockHeight
+	recoverableContracts    map[types.FileContractID]skymodules.RecoverableContract
+	renewedFrom             map[types.FileContractID]types.FileContractID
+	renewedTo               map[types.FileContractID]types.FileContractID
+	uploadDisabledContracts map[types.FileContractID]struct{}
+	contractNotes           map[types.FileContractID]string
+
+	// temporaryAllowance is set while an allowance set with
+	// TemporaryAllowance is active.
+	temporaryAllowance *temporaryAllowance
+
+	staticChurnLimiter     *churnLimiter
+	staticFormationMetrics *formationMetrics
+	staticWatchdog         *watchdog
 }
 
 // PaymentDetails is a helper struct that contains extra information on a
@@ -157,10 +182,48 @@ func (c *Contractor) InitRecoveryScan() (err error) {
 	return c.callInitRecoveryScan(modules.ConsensusChangeBeginning)
 }
 
+// AllowanceUtilization returns how much of the allowance's funds have been
+// allocated and spent in the current period. The remaining funds are computed
+// the same way contract maintenance computes them.
+func (c *Contractor) AllowanceUtilization() skymodules.AllowanceUtilization {
+	spending, err := c.PeriodSpending()
+	if err != nil {
+		c.staticLog.Println("WARN: error getting period spending:", err)
+		return skymodules.AllowanceUtilization{}
+	}
+	return allowanceUtilization(c.Allowance().Funds, spending)
+}
+
+// allowanceUtilization computes the utilization of an allowance with the
+// given funds from the period spending.
+func allowanceUtilization(funds types.Currency, spending skymodules.ContractorSpending) skymodules.AllowanceUtilization {
+	totalSpent, _, _ := spending.SpendingBreakdown()
+	au := skymodules.AllowanceUtilization{
+		Funds:          funds,
+		TotalAllocated: spending.TotalAllocated,
+		TotalSpent:     totalSpent,
+	}
+	// Check for an underflow. This can happen if the user reduced their
+	// allowance at some point to less than what we've already spent.
+	if spending.TotalAllocated.Cmp(funds) < 0 {
+		au.FundsRemaining = funds.Sub(spending.TotalAllocated)
+	}
+	if !funds.IsZero() {
+		utilized, _ := big.NewRat(0, 1).SetFrac(spending.TotalAllocated.Big(), funds.Big()).Float64()
+		au.PercentUtilized = 100 * utilized
+	}
+	return au
+}
+
 // PeriodSpending returns the amount spent on contracts during the current
 // billing period.
 func (c *Contractor) PeriodSpending() (skymodules.ContractorSpending, error) {
 	allContracts := c.staticContracts.ViewAll()
+	// Only use the old contracts in memory to avoid reading the spilled ones
+	// from disk on every call. Contracts of the current period are never
+	// spilled, so the spilled ones only add to the previous spending.
+	oldContracts := c.oldContracts.allInMemory()
+	spilledSpending := c.oldContracts.spilledSpending()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -187,7 +250,7 @@ func (c *Contractor) PeriodSpending() (skymodules.ContractorSpending, error) {
 	}
 
 	// Calculate needed spending to be reported from old contracts
-	for _, contract := range c.oldContracts {
+	for _, contract := range oldContracts {
 		// Don't count double-spent contracts.
 		if _, doubleSpent := c.doubleSpentContracts[contract.ID]; doubleSpent {
 			continue
@@ -225,6 +288,8 @@ func (c *Contractor) PeriodSpending() (skymodules.ContractorSpending, error) {
 		}
 	}
 
+	spending.PreviousSpending = spending.PreviousSpending.Add(spilledSpending)
+
 	// Calculate amount of spent money to get unspent money.
 	allSpending := spending.ContractFees
 	allSpending = allSpending.Add(spending.DownloadSpending)
@@ -379,7 +444,7 @@ func (c *Contractor) RefreshedContract(fcid types.FileContractID) bool {
 	}
 
 	// Grab the contract to check its end height
-	contract, ok := c.oldContracts[fcid]
+	contract, ok := c.oldContracts.get(fcid)
 	if !ok {
 		c.staticLog.Debugln("Contract not found in oldContracts, despite there being a renewal to the contract")
 		return false
@@ -388,7 +453,7 @@ func (c *Contractor) RefreshedContract(fcid types.FileContractID) bool {
 	// Grab the contract it was renewed to to check its end height
 	newContract, ok := c.staticContracts.View(newFCID)
 	if !ok {
-		newContract, ok = c.oldContracts[newFCID]
+		newContract, ok = c.oldContracts.get(newFCID)
 		if !ok {
 			c.staticLog.Debugln("Contract was not found in the database, despite their being another contract that claims to have renewed to it.")
 			return false
@@ -483,22 +548,26 @@ func contractorBlockingStartup(cs modules.ConsensusSet, w modules.Wallet, tp mod
 		staticWallet:  w,
 
 		staticInterruptMaintenance: make(chan struct{}),
+		staticQueueMaintenance:     queueContractMaintenance(l),
 		synced:                     make(chan struct{}),
 
-		staticContracts:      contractSet,
-		downloaders:          make(map[types.FileContractID]*hostDownloader),
-		editors:              make(map[types.FileContractID]*hostEditor),
-		sessions:             make(map[types.FileContractID]*hostSession),
-		oldContracts:         make(map[types.FileContractID]skymodules.RenterContract),
-		doubleSpentContracts: make(map[types.FileContractID]types.BlockHeight),
-		preferredHosts:       make(map[string]struct{}),
-		recoverableContracts: make(map[types.FileContractID]skymodules.RecoverableContract),
-		renewing:             make(map[types.FileContractID]bool),
-		renewedFrom:          make(map[types.FileContractID]types.FileContractID),
-		renewedTo:            make(map[types.FileContractID]types.FileContractID),
-		staticWorkerPool:     emptyWorkerPool{},
+		staticContracts:         contractSet,
+		downloaders:             make(map[types.FileContractID]*hostDownloader),
+		editors:                 make(map[types.FileContractID]*hostEditor),
+		sessions:                make(map[types.FileContractID]*hostSession),
+		oldContracts:            newOldContractStore(filepath.Join(persistDir, oldContractsDir), maxOldContractsInMemory(), l),
+		doubleSpentContracts:    make(map[types.FileContractID]types.BlockHeight),
+		preferredHosts:          make(map[string]struct{}),
+		recoverableContracts:    make(map[types.FileContractID]skymodules.RecoverableContract),
+		renewing:                make(map[types.FileContractID]bool),
+		renewedFrom:             make(map[types.FileContractID]types.FileContractID),
+		renewedTo:               make(map[types.FileContractID]types.FileContractID),
+		uploadDisabledContracts: make(map[types.FileContractID]struct{}),
+		contractNotes:           make(map[types.FileContractID]string),
+		staticWorkerPool:        emptyWorkerPool{},
 	}
 	c.staticChurnLimiter = newChurnLimiter(c)
+	c.staticFormationMetrics = newFormationMetrics()
 	c.staticWatchdog = newWatchdog(c)
 
 	// Close the contract set and logger upon shutdown.
@@ -516,10 +585,15 @@ func contractorBlockingStartup(cs modules.ConsensusSet, w modules.Wallet, tp mod
 	}
 
 	// Load the prior persistence structures.
+	err = c.oldContracts.load()
+	if err != nil {
+		return nil, err
+	}
 	err = c.load()
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
+	c.managedEvictOldContracts()
 
 	// Update the pubkeyToContractID map
 	c.managedUpdatePubKeyToContractIDMap()
@@ -548,6 +622,11 @@ func contractorBlockingStartup(cs modules.ConsensusSet, w modules.Wallet, tp mod
 	if err != nil {
 		return nil, err
 	}
+
+	// Resume waiting for the revert of a temporary allowance.
+	if c.temporaryAllowance != nil {
+		go c.threadedRevertTemporaryAllowance(c.temporaryAllowance.RevertAt)
+	}
 	return c, nil
 }
 
@@ -668,21 +747,15 @@ func (c *Contractor) managedSynced() bool {
 // newPayByContractRequest is a helper function that takes a revision,
 // signature and refund account and creates a PayByContractRequest object.
 func newPayByContractRequest(rev types.FileContractRevision, sig crypto.Signature, refundAccount modules.AccountID) modules.PayByContractRequest {
-	req := modules.PayByContractRequest{
+	valid, missed := skymodules.ProofValues(rev)
+	return modules.PayByContractRequest{
 		ContractID:           rev.ID(),
 		NewRevisionNumber:    rev.NewRevisionNumber,
-		NewValidProofValues:  make([]types.Currency, len(rev.NewValidProofOutputs)),
-		NewMissedProofValues: make([]types.Currency, len(rev.NewMissedProofOutputs)),
+		NewValidProofValues:  valid,
+		NewMissedProofValues: missed,
 		RefundAccount:        refundAccount,
 		Signature:            sig[:],
 	}
-	for i, o := range rev.NewValidProofOutputs {
-		req.NewValidProofValues[i] = o.Value
-	}
-	for i, o := range rev.NewMissedProofOutputs {
-		req.NewMissedProofValues[i] = o.Value
-	}
-	return req
 }
 
 // RenewContract takes an established connection to a host and renews the
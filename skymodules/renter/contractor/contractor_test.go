@@ -925,3 +925,44 @@ func TestPaymentMissingStorageObligation(t *testing.T) {
 		t.Fatal("Contract should not be locked")
 	}
 }
+
+// TestAllowanceUtilization is a unit test for allowanceUtilization.
+func TestAllowanceUtilization(t *testing.T) {
+	t.Parallel()
+
+	spending := skymodules.ContractorSpending{
+		ContractFees:   types.NewCurrency64(10),
+		TotalAllocated: types.NewCurrency64(250),
+		UploadSpending: types.NewCurrency64(40),
+	}
+
+	// Regular utilization.
+	au := allowanceUtilization(types.NewCurrency64(1000), spending)
+	if !au.Funds.Equals64(1000) || !au.TotalAllocated.Equals64(250) {
+		t.Fatal("wrong funds or allocation", au)
+	}
+	if !au.TotalSpent.Equals64(50) {
+		t.Fatal("wrong spending", au.TotalSpent)
+	}
+	if !au.FundsRemaining.Equals64(750) {
+		t.Fatal("wrong remaining funds", au.FundsRemaining)
+	}
+	if au.PercentUtilized != 25 {
+		t.Fatal("wrong utilization", au.PercentUtilized)
+	}
+
+	// Allocated more than the funds.
+	au = allowanceUtilization(types.NewCurrency64(200), spending)
+	if !au.FundsRemaining.IsZero() {
+		t.Fatal("expected no remaining funds", au.FundsRemaining)
+	}
+	if au.PercentUtilized != 125 {
+		t.Fatal("wrong utilization", au.PercentUtilized)
+	}
+
+	// No allowance.
+	au = allowanceUtilization(types.ZeroCurrency, spending)
+	if au.PercentUtilized != 0 || !au.FundsRemaining.IsZero() {
+		t.Fatal("unexpected utilization", au)
+	}
+}
@@ -1,9 +1,13 @@
 package contractor
 
 import (
+	"fmt"
+	"sort"
+
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"gitlab.com/SkynetLabs/skyd/skymodules/renter/proto"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -137,6 +141,54 @@ func (c *Contractor) Contracts() []skymodules.RenterContract {
 	return c.staticContracts.ViewAll()
 }
 
+// OrphanedContracts returns the active contracts whose hosts have no entry in
+// the hostdb anymore, e.g. because the host never re-announced after a change
+// of its address. The contractor can't reach these hosts, so operators need to
+// decide whether to wait for them, cancel the contracts or recover the data
+// manually.
+func (c *Contractor) OrphanedContracts() ([]skymodules.RenterContract, error) {
+	var orphaned []skymodules.RenterContract
+	for _, contract := range c.staticContracts.ViewAll() {
+		_, exists, err := c.staticHDB.Host(contract.HostPublicKey)
+		if err != nil {
+			return nil, errors.AddContext(err, fmt.Sprintf("failed to look up host of contract %v", contract.ID))
+		}
+		if !exists {
+			orphaned = append(orphaned, contract)
+		}
+	}
+	return orphaned, nil
+}
+
+// ContractsByHostRemainingStorage returns the active GoodForUpload contracts
+// paired with the remaining storage their hosts last reported to the hostdb,
+// sorted by the remaining storage in descending order. Uploads can use this to
+// prefer hosts that still have room and avoid running into out of storage
+// errors. Contracts whose hosts are missing from the hostdb are skipped.
+func (c *Contractor) ContractsByHostRemainingStorage() ([]skymodules.ContractCapacity, error) {
+	var capacities []skymodules.ContractCapacity
+	for _, contract := range c.staticContracts.ViewAll() {
+		if !contract.Utility.GoodForUpload {
+			continue
+		}
+		host, exists, err := c.staticHDB.Host(contract.HostPublicKey)
+		if err != nil {
+			return nil, errors.AddContext(err, fmt.Sprintf("failed to look up host of contract %v", contract.ID))
+		}
+		if !exists {
+			continue
+		}
+		capacities = append(capacities, skymodules.ContractCapacity{
+			Contract:         contract,
+			RemainingStorage: host.RemainingStorage,
+		})
+	}
+	sort.SliceStable(capacities, func(i, j int) bool {
+		return capacities[i].RemainingStorage > capacities[j].RemainingStorage
+	})
+	return capacities, nil
+}
+
 // ContractUtility returns the utility fields for the given contract.
 func (c *Contractor) ContractUtility(pk types.SiaPublicKey) (skymodules.ContractUtility, bool) {
 	c.mu.RLock()
@@ -164,15 +216,10 @@ func (c *Contractor) MarkContractBad(id types.FileContractID) error {
 }
 
 // OldContracts returns the contracts formed by the contractor that have
-// expired
+// expired. The old contracts are protected by their own lock, so the
+// contractor's lock isn't held while the spilled ones are read from disk.
 func (c *Contractor) OldContracts() []skymodules.RenterContract {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	contracts := make([]skymodules.RenterContract, 0, len(c.oldContracts))
-	for _, c := range c.oldContracts {
-		contracts = append(contracts, c)
-	}
-	return contracts
+	return c.oldContracts.all()
 }
 
 // RecoverableContracts returns the contracts that the contractor deems
@@ -189,6 +236,115 @@ func (c *Contractor) RecoverableContracts() []skymodules.RecoverableContract {
 	return contracts
 }
 
+// RepostRevision submits the most recent revision of the contract with the
+// given id to the transaction pool. It is meant as a manual remediation tool
+// for contracts that are contested on-chain.
+func (c *Contractor) RepostRevision(fcID types.FileContractID) error {
+	if err := c.staticTG.Add(); err != nil {
+		return err
+	}
+	defer c.staticTG.Done()
+
+	sc, exists := c.staticContracts.Acquire(fcID)
+	if !exists {
+		return errContractNotFound
+	}
+	metadata := sc.Metadata()
+	c.staticContracts.Return(sc)
+
+	revisionTxn := metadata.Transaction
+	if len(revisionTxn.FileContractRevisions) == 0 {
+		return errors.New("contract has no revision to repost")
+	}
+	revNum := revisionTxn.FileContractRevisions[0].NewRevisionNumber
+	c.staticLog.Printf("Reposting revision %v of contract %v", revNum, fcID)
+
+	err := c.staticTPool.AcceptTransactionSet([]types.Transaction{revisionTxn})
+	if err != nil && !errors.Contains(err, modules.ErrDuplicateTransactionSet) {
+		return errors.AddContext(err, "failed to submit revision to the transaction pool")
+	}
+	return nil
+}
+
+// SetContractUploadable sets whether the contract with the given id is good for
+// upload without affecting whether it is good for renew. The setting is
+// persisted and respected by contract maintenance.
+func (c *Contractor) SetContractUploadable(fcID types.FileContractID, uploadable bool) error {
+	if err := c.staticTG.Add(); err != nil {
+		return err
+	}
+	defer c.staticTG.Done()
+
+	sc, exists := c.staticContracts.Acquire(fcID)
+	if !exists {
+		return errContractNotFound
+	}
+	defer c.staticContracts.Return(sc)
+
+	// A renewed contract must never be marked as good for upload.
+	c.mu.Lock()
+	_, renewed := c.renewedTo[fcID]
+	c.mu.Unlock()
+	if renewed && uploadable {
+		return errors.New("cannot mark a renewed contract as good for upload")
+	}
+
+	// Persist the setting before updating the utility.
+	c.mu.Lock()
+	if uploadable {
+		delete(c.uploadDisabledContracts, fcID)
+	} else {
+		c.uploadDisabledContracts[fcID] = struct{}{}
+	}
+	err := c.save()
+	c.mu.Unlock()
+	if err != nil {
+		return errors.AddContext(err, "failed to save contractor")
+	}
+
+	// Only contracts that are good for renew may be good for upload. Other
+	// checks are left to the next maintenance run which might revert the
+	// change.
+	u := sc.Utility()
+	u.GoodForUpload = uploadable && u.GoodForRenew
+	return c.callUpdateUtility(sc, u, false)
+}
+
+// SetContractNote sets a free-form note on the contract with the given id. The
+// note is purely informational, it is persisted and carried over to the
+// renewed contract. An empty note removes the existing note.
+func (c *Contractor) SetContractNote(fcID types.FileContractID, note string) error {
+	if err := c.staticTG.Add(); err != nil {
+		return err
+	}
+	defer c.staticTG.Done()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The contract needs to be either an active or an old contract.
+	_, active := c.staticContracts.View(fcID)
+	old := c.oldContracts.exists(fcID)
+	if !active && !old {
+		return errContractNotFound
+	}
+
+	if note == "" {
+		delete(c.contractNotes, fcID)
+	} else {
+		c.contractNotes[fcID] = note
+	}
+	return errors.AddContext(c.save(), "failed to save contractor")
+}
+
+// GetContractNote returns the note of the contract with the given id. If no
+// note was set, an empty string is returned.
+func (c *Contractor) GetContractNote(fcID types.FileContractID) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.contractNotes[fcID]
+}
+
 // managedMarkContractBad marks an already acquired SafeContract as bad.
 func (c *Contractor) managedMarkContractBad(sc *proto.SafeContract) error {
 	u := sc.Utility()
@@ -3,6 +3,7 @@ package contractor
 import (
 	"testing"
 
+	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/types"
@@ -31,7 +32,7 @@ func TestPubkeysToContractIDMap(t *testing.T) {
 	}
 
 	// acquire the contract maintenance lock for the duration of the test. This
-	// prevents theadedContractMaintenance from running.
+	// prevents threadedContractMaintenance from running.
 	c.maintenanceLock.Lock()
 	defer c.maintenanceLock.Unlock()
 
@@ -75,6 +76,21 @@ func TestPubkeysToContractIDMap(t *testing.T) {
 		t.Fatal("pubkey map should have 1 entry")
 	}
 
+	// Forming another contract with the host should fail before negotiating.
+	_, _, err = c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if !errors.Contains(err, errContractWithHostExists) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// The rejection isn't tracked as a failed formation.
+	_, _, err = c.managedFormContractWithHost(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if !errors.Contains(err, errContractWithHostExists) {
+		t.Fatal("unexpected error", err)
+	}
+	if fm := c.FormationMetrics(); fm.Failures != 0 || fm.Successes != 0 {
+		t.Fatal("rejected formation shouldn't be tracked", fm)
+	}
+
 	// Add a slice of contracts
 	_, pubkey := crypto.GenerateKeyPair()
 	spk := types.SiaPublicKey{
@@ -158,3 +174,227 @@ func TestTryAddContractToPubKeyMap(t *testing.T) {
 	// Can't test the case of a pubkey already in the pubkey map as that results
 	// in a Critical log
 }
+
+// TestRepostRevision tests the RepostRevision method.
+func TestRepostRevision(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	_, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Reposting an unknown contract should fail.
+	err = c.RepostRevision(types.FileContractID{1})
+	if !errors.Contains(err, errContractNotFound) {
+		t.Fatal("expected errContractNotFound, got", err)
+	}
+
+	// Reposting the revision of the new contract should succeed.
+	if err := c.RepostRevision(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSetContractUploadable tests the SetContractUploadable method.
+func TestSetContractUploadable(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	_, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Updating an unknown contract should fail.
+	err = c.SetContractUploadable(types.FileContractID{1}, false)
+	if !errors.Contains(err, errContractNotFound) {
+		t.Fatal("expected errContractNotFound, got", err)
+	}
+
+	// Mark the contract as good.
+	err = c.managedAcquireAndUpdateContractUtility(contract.ID, skymodules.ContractUtility{GoodForUpload: true, GoodForRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Disable uploads.
+	if err := c.SetContractUploadable(contract.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	u, ok := c.managedContractUtility(contract.ID)
+	if !ok {
+		t.Fatal("contract not found")
+	}
+	if u.GoodForUpload || !u.GoodForRenew {
+		t.Fatal("unexpected utility", u)
+	}
+	c.mu.Lock()
+	data := c.persistData()
+	c.mu.Unlock()
+	if len(data.UploadDisabledContracts) != 1 || data.UploadDisabledContracts[0] != contract.ID {
+		t.Fatal("setting wasn't persisted", data.UploadDisabledContracts)
+	}
+
+	// The maintenance checks should respect the setting.
+	contract, _ = c.staticContracts.View(contract.ID)
+	u, _ = uploadDisabledCheck(contract.Utility, true)
+	if u.GoodForUpload {
+		t.Fatal("contract shouldn't be good for upload")
+	}
+
+	// Enable uploads again.
+	if err := c.SetContractUploadable(contract.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	u, _ = c.managedContractUtility(contract.ID)
+	if !u.GoodForUpload || !u.GoodForRenew {
+		t.Fatal("unexpected utility", u)
+	}
+	c.mu.Lock()
+	data = c.persistData()
+	c.mu.Unlock()
+	if len(data.UploadDisabledContracts) != 0 {
+		t.Fatal("setting wasn't removed", data.UploadDisabledContracts)
+	}
+}
+
+// TestContractNote tests setting and getting a contract's note.
+func TestContractNote(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	_, c, _, cf, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Setting a note on an unknown contract should fail.
+	fcID := types.FileContractID{1}
+	err = c.SetContractNote(fcID, "note")
+	if !errors.Contains(err, errContractNotFound) {
+		t.Fatal("expected errContractNotFound, got", err)
+	}
+
+	// Add an old contract and set its note.
+	c.mu.Lock()
+	c.oldContracts.set(skymodules.RenterContract{ID: fcID})
+	c.mu.Unlock()
+	note := "host owner contacted about downtime"
+	if err := c.SetContractNote(fcID, note); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetContractNote(fcID) != note {
+		t.Fatal("unexpected note", c.GetContractNote(fcID))
+	}
+	c.mu.Lock()
+	data := c.persistData()
+	c.mu.Unlock()
+	if data.ContractNotes[fcID.String()] != note {
+		t.Fatal("note wasn't persisted", data.ContractNotes)
+	}
+
+	// Remove the note again.
+	if err := c.SetContractNote(fcID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetContractNote(fcID) != "" {
+		t.Fatal("note wasn't removed", c.GetContractNote(fcID))
+	}
+	c.mu.Lock()
+	data = c.persistData()
+	c.mu.Unlock()
+	if len(data.ContractNotes) != 0 {
+		t.Fatal("note wasn't removed from persistence", data.ContractNotes)
+	}
+}
+
+// TestOrphanedContracts tests that contracts with hosts that are missing from
+// the hostdb are returned by OrphanedContracts.
+func TestOrphanedContracts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// The host is in the hostdb, so the contract isn't orphaned.
+	orphaned, err := c.OrphanedContracts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphaned) != 0 {
+		t.Fatal("expected no orphaned contracts", orphaned)
+	}
+
+	// Remove the host from the hostdb. The contract should be orphaned now.
+	hdb := c.staticHDB.(*testHostDB)
+	hdb.setMissing(h.PublicKey(), true)
+	orphaned, err = c.OrphanedContracts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphaned) != 1 || orphaned[0].ID != contract.ID {
+		t.Fatal("expected the contract to be orphaned", orphaned)
+	}
+}
+
+// TestContractsByHostRemainingStorage tests that GoodForUpload contracts are
+// returned with the remaining storage of their hosts.
+func TestContractsByHostRemainingStorage(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// The contract should be returned with the host's remaining storage.
+	hdb := c.staticHDB.(*testHostDB)
+	hdb.setRemainingStorage(h.PublicKey(), 42)
+	capacities, err := c.ContractsByHostRemainingStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capacities) != 1 || capacities[0].Contract.ID != contract.ID || capacities[0].RemainingStorage != 42 {
+		t.Fatal("unexpected capacities", capacities)
+	}
+
+	// Remove the host from the hostdb. The contract should be skipped.
+	hdb.setMissing(h.PublicKey(), true)
+	capacities, err = c.ContractsByHostRemainingStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capacities) != 0 {
+		t.Fatal("expected no capacities", capacities)
+	}
+
+	// Cancel the contract. It's not GoodForUpload anymore and should be
+	// skipped as well.
+	hdb.setMissing(h.PublicKey(), false)
+	if err := c.managedCancelContract(contract.ID); err != nil {
+		t.Fatal(err)
+	}
+	capacities, err = c.ContractsByHostRemainingStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capacities) != 0 {
+		t.Fatal("expected no capacities", capacities)
+	}
+}
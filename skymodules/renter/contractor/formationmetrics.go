@@ -0,0 +1,96 @@
+package contractor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+)
+
+// formationMetricsWindowSize is the number of most recent formation attempts
+// that are taken into account when computing the formation time metrics.
+const formationMetricsWindowSize = 100
+
+// formationMetrics keeps track of how long it takes the contractor to form
+// contracts. The durations of the most recent successful formations are kept
+// in a ring buffer while the number of successful and failed formations is
+// accumulated since startup.
+type formationMetrics struct {
+	durations []time.Duration
+	next      int
+
+	duplicates uint64
+	failures   uint64
+	successes  uint64
+
+	mu sync.Mutex
+}
+
+// newFormationMetrics creates a new formationMetrics object.
+func newFormationMetrics() *formationMetrics {
+	return &formationMetrics{
+		durations: make([]time.Duration, 0, formationMetricsWindowSize),
+	}
+}
+
+// callAddFormation adds the result of a formation attempt to the metrics. Only
+// the durations of successful formations are tracked.
+func (fm *formationMetrics) callAddFormation(d time.Duration, success bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if !success {
+		fm.failures++
+		return
+	}
+	fm.successes++
+	if len(fm.durations) < formationMetricsWindowSize {
+		fm.durations = append(fm.durations, d)
+		return
+	}
+	fm.durations[fm.next] = d
+	fm.next = (fm.next + 1) % formationMetricsWindowSize
+}
+
+// callAddDuplicateFormation adds a formation that raced with another formation
+// with the same host to the metrics. It returns the number of duplicate
+// formations since startup.
+func (fm *formationMetrics) callAddDuplicateFormation() uint64 {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.duplicates++
+	return fm.duplicates
+}
+
+// callMetrics returns the current metrics.
+func (fm *formationMetrics) callMetrics() skymodules.FormationMetrics {
+	fm.mu.Lock()
+	durations := append([]time.Duration{}, fm.durations...)
+	metrics := skymodules.FormationMetrics{
+		DuplicateFormations: fm.duplicates,
+		Failures:            fm.failures,
+		Successes:           fm.successes,
+	}
+	fm.mu.Unlock()
+
+	if len(durations) == 0 {
+		return metrics
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	metrics.AverageFormationTime = total / time.Duration(len(durations))
+	metrics.P90FormationTime = durations[len(durations)*90/100]
+	metrics.P99FormationTime = durations[len(durations)*99/100]
+	return metrics
+}
+
+// FormationMetrics returns metrics about the time it takes to form contracts.
+func (c *Contractor) FormationMetrics() skymodules.FormationMetrics {
+	return c.staticFormationMetrics.callMetrics()
+}
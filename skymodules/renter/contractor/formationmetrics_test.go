@@ -0,0 +1,62 @@
+package contractor
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFormationMetrics is a unit test for the formationMetrics.
+func TestFormationMetrics(t *testing.T) {
+	t.Parallel()
+
+	fm := newFormationMetrics()
+
+	// No formations yet.
+	metrics := fm.callMetrics()
+	if metrics.AverageFormationTime != 0 || metrics.Successes != 0 || metrics.Failures != 0 {
+		t.Fatal("unexpected metrics", metrics)
+	}
+
+	// Add 100 successful formations taking 1ms to 100ms and a few failures.
+	for i := 1; i <= formationMetricsWindowSize; i++ {
+		fm.callAddFormation(time.Duration(i)*time.Millisecond, true)
+	}
+	fm.callAddFormation(time.Hour, false)
+	fm.callAddFormation(time.Hour, false)
+	metrics = fm.callMetrics()
+	if metrics.Successes != formationMetricsWindowSize || metrics.Failures != 2 {
+		t.Fatal("unexpected counts", metrics)
+	}
+	if metrics.AverageFormationTime != 50500*time.Microsecond {
+		t.Fatal("unexpected average", metrics.AverageFormationTime)
+	}
+	if metrics.P90FormationTime != 91*time.Millisecond {
+		t.Fatal("unexpected p90", metrics.P90FormationTime)
+	}
+	if metrics.P99FormationTime != 100*time.Millisecond {
+		t.Fatal("unexpected p99", metrics.P99FormationTime)
+	}
+
+	// Adding more formations should replace the oldest ones.
+	for i := 0; i < formationMetricsWindowSize; i++ {
+		fm.callAddFormation(time.Second, true)
+	}
+	metrics = fm.callMetrics()
+	if metrics.Successes != 2*formationMetricsWindowSize {
+		t.Fatal("unexpected successes", metrics.Successes)
+	}
+	if metrics.AverageFormationTime != time.Second || metrics.P90FormationTime != time.Second || metrics.P99FormationTime != time.Second {
+		t.Fatal("oldest formations weren't replaced", metrics)
+	}
+
+	// Track duplicate formations.
+	if n := fm.callAddDuplicateFormation(); n != 1 {
+		t.Fatal("unexpected duplicates", n)
+	}
+	if n := fm.callAddDuplicateFormation(); n != 2 {
+		t.Fatal("unexpected duplicates", n)
+	}
+	if metrics = fm.callMetrics(); metrics.DuplicateFormations != 2 {
+		t.Fatal("unexpected duplicates", metrics.DuplicateFormations)
+	}
+}
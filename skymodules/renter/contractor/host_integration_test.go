@@ -6,6 +6,8 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -115,8 +117,52 @@ func newTestingHost(testdir string, cs modules.ConsensusSet, tp modules.Transact
 	return newCustomTestingHost(testdir, cs, tp, mux, modules.ProdDependencies)
 }
 
+// testHostDB is a hostDB that allows tests to hide hosts from the contractor
+// and to override the remaining storage of hosts.
+type testHostDB struct {
+	skymodules.HostDB
+
+	missing          map[string]struct{}
+	remainingStorage map[string]uint64
+	mu               sync.Mutex
+}
+
+// Host implements the hostDB interface.
+func (hdb *testHostDB) Host(pk types.SiaPublicKey) (skymodules.HostDBEntry, bool, error) {
+	hdb.mu.Lock()
+	_, missing := hdb.missing[pk.String()]
+	remainingStorage, override := hdb.remainingStorage[pk.String()]
+	hdb.mu.Unlock()
+	if missing {
+		return skymodules.HostDBEntry{}, false, nil
+	}
+	host, exists, err := hdb.HostDB.Host(pk)
+	if override {
+		host.RemainingStorage = remainingStorage
+	}
+	return host, exists, err
+}
+
+// setMissing hides the host from the contractor or makes it visible again.
+func (hdb *testHostDB) setMissing(pk types.SiaPublicKey, missing bool) {
+	hdb.mu.Lock()
+	defer hdb.mu.Unlock()
+	if missing {
+		hdb.missing[pk.String()] = struct{}{}
+	} else {
+		delete(hdb.missing, pk.String())
+	}
+}
+
+// setRemainingStorage overrides the remaining storage of the host.
+func (hdb *testHostDB) setRemainingStorage(pk types.SiaPublicKey, remainingStorage uint64) {
+	hdb.mu.Lock()
+	defer hdb.mu.Unlock()
+	hdb.remainingStorage[pk.String()] = remainingStorage
+}
+
 // newTestingContractor is a helper function that creates a ready-to-use
-// contractor.
+// contractor. The contractor's hostdb is wrapped in a testHostDB.
 func newTestingContractor(testdir string, g modules.Gateway, cs modules.ConsensusSet, tp modules.TransactionPool, rl *ratelimit.RateLimit, deps modules.Dependencies) (*Contractor, closeFn, error) {
 	w, walletCF, err := newTestingWallet(testdir, cs, tp)
 	if err != nil {
@@ -131,7 +177,12 @@ func newTestingContractor(testdir string, g modules.Gateway, cs modules.Consensu
 	if err := <-errChan; err != nil {
 		return nil, nil, err
 	}
-	contractor, errChan := newWithDeps(cs, w, tp, hdb, rl, filepath.Join(testdir, "contractor"), deps)
+	thdb := &testHostDB{
+		HostDB:           hdb,
+		missing:          make(map[string]struct{}),
+		remainingStorage: make(map[string]uint64),
+	}
+	contractor, errChan := newWithDeps(cs, w, tp, thdb, rl, filepath.Join(testdir, "contractor"), deps)
 	err = <-errChan
 	if err != nil {
 		return nil, nil, err
@@ -148,6 +199,44 @@ func newTestingTrio(name string) (modules.Host, *Contractor, modules.TestMiner,
 	return newTestingTrioWithContractorDeps(name, modules.ProdDependencies)
 }
 
+// newTestingTrioWithContract creates a Host, Contractor, and TestMiner and forms
+// a contract between the contractor and the host. The contract maintenance lock
+// is held until the returned closeFn is called to prevent
+// threadedContractMaintenance from interfering with the test.
+func newTestingTrioWithContract(name string) (modules.Host, *Contractor, skymodules.RenterContract, closeFn, error) {
+	h, c, _, cf, err := newTestingTrio(name)
+	if err != nil {
+		return nil, nil, skymodules.RenterContract{}, nil, err
+	}
+	c.maintenanceLock.Lock()
+	closeAndUnlock := func() error {
+		c.maintenanceLock.Unlock()
+		return cf()
+	}
+
+	// get the host's entry from the db
+	hostEntry, ok, err := c.staticHDB.Host(h.PublicKey())
+	if err != nil {
+		return nil, nil, skymodules.RenterContract{}, nil, errors.Compose(err, closeAndUnlock())
+	}
+	if !ok {
+		return nil, nil, skymodules.RenterContract{}, nil, errors.Compose(errors.New("no entry for host in db"), closeAndUnlock())
+	}
+
+	// set an allowance but don't use SetAllowance to avoid automatic contract
+	// formation.
+	c.mu.Lock()
+	c.allowance = skymodules.DefaultAllowance
+	c.mu.Unlock()
+
+	// form a contract with the host
+	_, contract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		return nil, nil, skymodules.RenterContract{}, nil, errors.Compose(err, closeAndUnlock())
+	}
+	return h, c, contract, closeAndUnlock, nil
+}
+
 // newTestingTrioWithContractorDeps creates a Host, Contractor, and TestMiner
 // that can be used for testing host/renter interactions.
 func newTestingTrioWithContractorDeps(name string, deps modules.Dependencies) (modules.Host, *Contractor, modules.TestMiner, closeFn, error) {
@@ -269,7 +358,7 @@ func TestIntegrationFormContract(t *testing.T) {
 	defer tryClose(cf, t)
 
 	// acquire the contract maintenance lock for the duration of the test. This
-	// prevents theadedContractMaintenance from running.
+	// prevents threadedContractMaintenance from running.
 	c.maintenanceLock.Lock()
 	defer c.maintenanceLock.Unlock()
 
@@ -334,6 +423,114 @@ func TestFormContractSmallAllowance(t *testing.T) {
 	}
 }
 
+// TestIntegrationFormContractsWithThreads tests that forming contracts with
+// multiple threads forms exactly the needed number of contracts and only
+// spends the funding of the formed contracts.
+func TestIntegrationFormContractsWithThreads(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	h, c, m, cf, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// split the wallet's funds into multiple outputs. Otherwise the threads
+	// compete for the same output and only one of them can fund a contract
+	// at a time.
+	var outputs []types.SiacoinOutput
+	for i := 0; i < 6; i++ {
+		uc, err := c.staticWallet.NextAddress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, types.SiacoinOutput{
+			Value:      types.SiacoinPrecision.Mul64(1000),
+			UnlockHash: uc.UnlockHash(),
+		})
+	}
+	if _, err := c.staticWallet.SendSiacoinsMulti(outputs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// acquire the contract maintenance lock for the duration of the test. This
+	// prevents threadedContractMaintenance from running.
+	c.maintenanceLock.Lock()
+	defer c.maintenanceLock.Unlock()
+
+	// get the host's entry from the db
+	hostEntry, ok, err := c.staticHDB.Host(h.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// set an allowance but don't use SetAllowance to avoid automatic contract
+	// formation.
+	allowance := skymodules.DefaultAllowance
+	c.mu.Lock()
+	c.allowance = allowance
+	endHeight := c.blockHeight + 100
+	c.mu.Unlock()
+
+	// surround the host with offline hosts that can't form contracts.
+	var hosts []skymodules.HostDBEntry
+	for i := 0; i < 5; i++ {
+		offline := hostEntry
+		offline.PublicKey = types.SiaPublicKey{
+			Algorithm: types.SignatureEd25519,
+			Key:       fastrand.Bytes(crypto.PublicKeySize),
+		}
+		offline.NetAddress = "127.0.0.1:1"
+		hosts = append(hosts, offline)
+		if i == 2 {
+			hosts = append(hosts, hostEntry)
+		}
+	}
+
+	// form the contracts with multiple threads. Only a single contract should
+	// be formed, even though more are needed.
+	budget := types.SiacoinPrecision.Mul64(1000)
+	funds := types.SiacoinPrecision.Mul64(50)
+	remaining, lowFunds, walletLocked := c.managedFormContractsWithThreads(budget, hosts, 3, allowance, endHeight, types.ZeroCurrency, funds, funds, 3)
+	if lowFunds || walletLocked {
+		t.Fatal("unexpected", lowFunds, walletLocked)
+	}
+	contracts := c.staticContracts.ViewAll()
+	if len(contracts) != 1 {
+		t.Fatal("expected 1 contract, got", len(contracts))
+	}
+	if !contracts[0].HostPublicKey.Equals(h.PublicKey()) {
+		t.Fatal("contract formed with wrong host")
+	}
+
+	// only the contract's funding should have been spent.
+	spent := budget.Sub(remaining)
+	if spent.IsZero() || spent.Cmp(funds) > 0 {
+		t.Fatal("unexpected spending", spent)
+	}
+	if !contracts[0].TotalCost.Equals(spent) {
+		t.Fatal("spending doesn't match the contract", spent, contracts[0].TotalCost)
+	}
+
+	// without enough budget for a single contract, no contract should be
+	// formed.
+	remaining, lowFunds, _ = c.managedFormContractsWithThreads(funds.Sub64(1), hosts, 3, allowance, endHeight, types.ZeroCurrency, funds, funds, 3)
+	if !lowFunds || !remaining.Equals(funds.Sub64(1)) {
+		t.Fatal("unexpected", lowFunds, remaining)
+	}
+	if len(c.staticContracts.ViewAll()) != 1 {
+		t.Fatal("no contract should have been formed")
+	}
+}
+
 // TestIntegrationReviseContract tests that the contractor can revise a
 // contract previously formed with a host.
 func TestIntegrationReviseContract(t *testing.T) {
@@ -349,7 +546,7 @@ func TestIntegrationReviseContract(t *testing.T) {
 	defer tryClose(cf, t)
 
 	// acquire the contract maintenance lock for the duration of the test. This
-	// prevents theadedContractMaintenance from running.
+	// prevents threadedContractMaintenance from running.
 	c.maintenanceLock.Lock()
 	defer c.maintenanceLock.Unlock()
 
@@ -588,6 +785,134 @@ func TestIntegrationRenew(t *testing.T) {
 	}
 }
 
+// dependencyInterruptLegacyRenew forces the legacy renewal and interrupts the
+// renewal before the contracts are linked once Fail was called.
+type dependencyInterruptLegacyRenew struct {
+	*dependencies.DependencyInterruptOnceOnKeyword
+}
+
+// Disrupt returns true for the legacy renewal and for the interrupt of the
+// wrapped dependency.
+func (d *dependencyInterruptLegacyRenew) Disrupt(s string) bool {
+	return s == "LegacyRenew" || d.DependencyInterruptOnceOnKeyword.Disrupt(s)
+}
+
+// TestIntegrationInterruptedRenew tests that managedCheckForDuplicates links
+// the contracts of a renewal that was interrupted before the contractor could
+// link them.
+func TestIntegrationInterruptedRenew(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	// create testing trio
+	deps := &dependencyInterruptLegacyRenew{dependencies.NewDependencyInterruptContractSaveToDiskAfterDeletion()}
+	h, c, m, cf, err := newTestingTrioWithContractorDeps(t.Name(), deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// prevent threadedContractMaintenance from interfering with the test.
+	c.maintenanceLock.Lock()
+	defer c.maintenanceLock.Unlock()
+
+	// get the host's entry from the db
+	hostEntry, ok, err := c.staticHDB.Host(h.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("no entry for host in db")
+	}
+
+	// set an allowance but don't use SetAllowance to avoid automatic contract
+	// formation.
+	c.mu.Lock()
+	c.allowance = skymodules.DefaultAllowance
+	c.mu.Unlock()
+
+	// form a contract with the host and give it a note.
+	_, oldContract, err := c.managedNewContract(hostEntry, types.SiacoinPrecision.Mul64(50), c.blockHeight+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.managedAcquireAndUpdateContractUtility(oldContract.ID, skymodules.ContractUtility{GoodForUpload: true, GoodForRenew: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	c.contractNotes[oldContract.ID] = "note"
+	c.mu.Unlock()
+
+	// mine a block so that the renewed contract has a higher start height.
+	if _, err := m.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		if c.blockHeight <= oldContract.StartHeight {
+			return errors.New("contractor didn't process the block")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// renew the contract and interrupt the renewal before the contracts are
+	// linked.
+	deps.Fail()
+	renewal := fileContractRenewal{
+		id:         oldContract.ID,
+		amount:     types.SiacoinPrecision.Mul64(50),
+		hostPubKey: oldContract.HostPublicKey,
+	}
+	c.mu.RLock()
+	allowance, currentPeriod, blockHeight := c.allowance, c.currentPeriod, c.blockHeight
+	c.mu.RUnlock()
+	_, err = c.managedRenewContract(renewal, currentPeriod, allowance, blockHeight, blockHeight+100)
+	if err == nil || !strings.Contains(err.Error(), "InterruptContractSaveToDiskAfterDeletion") {
+		t.Fatal("expected renewal to be interrupted", err)
+	}
+
+	// both contracts should be active and unlinked.
+	contracts := c.staticContracts.ViewAll()
+	if len(contracts) != 2 {
+		t.Fatal("expected 2 contracts, got", len(contracts))
+	}
+	newContract := contracts[0]
+	if newContract.ID == oldContract.ID {
+		newContract = contracts[1]
+	}
+	c.mu.RLock()
+	_, linked := c.renewedTo[oldContract.ID]
+	c.mu.RUnlock()
+	if linked {
+		t.Fatal("contracts shouldn't be linked yet")
+	}
+
+	// clean up the duplicate. The old contract should be linked to the new
+	// one and archived.
+	c.managedCheckForDuplicates()
+	contracts = c.staticContracts.ViewAll()
+	if len(contracts) != 1 || contracts[0].ID != newContract.ID {
+		t.Fatal("expected only the new contract to remain", contracts)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.renewedTo[oldContract.ID] != newContract.ID || c.renewedFrom[newContract.ID] != oldContract.ID {
+		t.Fatal("contracts weren't linked")
+	}
+	if _, exists := c.oldContracts.get(oldContract.ID); !exists {
+		t.Fatal("old contract wasn't archived")
+	}
+	if c.contractNotes[newContract.ID] != "note" {
+		t.Fatal("note wasn't carried over")
+	}
+}
+
 // TestIntegrationDownloaderCaching tests that downloaders are properly cached
 // by the contractor. When two downloaders are requested for the same
 // contract, only one underlying downloader should be created.
@@ -108,6 +108,8 @@ func (c *Contractor) managedUtilityChecks(contract skymodules.RenterContract, ho
 	renewWindow := c.allowance.RenewWindow
 	period := c.allowance.Period
 	_, renewed := c.renewedTo[contract.ID]
+	_, uploadDisabled := c.uploadDisabledContracts[contract.ID]
+	unneededBackup := allowance.IsBackupHost(contract.HostPublicKey) && !c.backupHostsNeeded
 	c.mu.RUnlock()
 
 	// Init uus to no update and the utility with the contract's utility.
@@ -127,6 +129,20 @@ func (c *Contractor) managedUtilityChecks(contract skymodules.RenterContract, ho
 	uus = uus.Merge(needsUpdate)
 	newUtility = newUtility.Merge(u)
 
+	// A contract that has ended but is still within the archive grace period
+	// can't be used or renewed anymore.
+	u, needsUpdate = endedCheck(contract.Utility, contract.EndHeight, blockHeight)
+	uus = uus.Merge(needsUpdate)
+	newUtility = newUtility.Merge(u)
+
+	u, needsUpdate = uploadDisabledCheck(contract.Utility, uploadDisabled)
+	uus = uus.Merge(needsUpdate)
+	newUtility = newUtility.Merge(u)
+
+	u, needsUpdate = backupHostCheck(contract.Utility, unneededBackup)
+	uus = uus.Merge(needsUpdate)
+	newUtility = newUtility.Merge(u)
+
 	u, needsUpdate = maxRevisionCheck(contract.Utility, revision.NewRevisionNumber)
 	uus = uus.Merge(needsUpdate)
 	newUtility = newUtility.Merge(u)
@@ -274,6 +290,41 @@ func renewedCheck(u skymodules.ContractUtility, renewed bool) (skymodules.Contra
 	return u, noUpdate
 }
 
+// endedCheck will return a contract with no utility and a required update if
+// the contract has ended, no changes otherwise. Ended contracts are only kept
+// in the active set during the allowance's contract archive grace period.
+func endedCheck(u skymodules.ContractUtility, endHeight, blockHeight types.BlockHeight) (skymodules.ContractUtility, utilityUpdateStatus) {
+	if blockHeight > endHeight {
+		u.GoodForUpload = false
+		u.GoodForRenew = false
+		return u, necessaryUtilityUpdate
+	}
+	return u, noUpdate
+}
+
+// uploadDisabledCheck will return a contract that is not good for upload and a
+// required update if uploads to the contract were manually disabled, no
+// changes otherwise.
+func uploadDisabledCheck(u skymodules.ContractUtility, disabled bool) (skymodules.ContractUtility, utilityUpdateStatus) {
+	if disabled {
+		u.GoodForUpload = false
+		return u, necessaryUtilityUpdate
+	}
+	return u, noUpdate
+}
+
+// backupHostCheck will return a contract with no utility and a required update
+// if the contract was formed with a backup host that is no longer needed, no
+// changes otherwise.
+func backupHostCheck(u skymodules.ContractUtility, unneededBackup bool) (skymodules.ContractUtility, utilityUpdateStatus) {
+	if unneededBackup {
+		u.GoodForUpload = false
+		u.GoodForRenew = false
+		return u, necessaryUtilityUpdate
+	}
+	return u, noUpdate
+}
+
 // storageGougingCheck makes sure the host's storage price isn't too expensive.
 func storageGougingCheck(contract skymodules.RenterContract, allowance skymodules.Allowance, host skymodules.HostDBEntry, contractSize uint64) (skymodules.ContractUtility, utilityUpdateStatus) {
 	u := contract.Utility
@@ -102,6 +102,86 @@ func TestDeadScoreCheck(t *testing.T) {
 	}
 }
 
+// TestEndedCheck is a unit test for endedCheck.
+func TestEndedCheck(t *testing.T) {
+	t.Parallel()
+
+	goodUtility := skymodules.ContractUtility{
+		GoodForUpload: true,
+		GoodForRenew:  true,
+	}
+	badUtility := skymodules.ContractUtility{}
+
+	// Contract ends at the current height.
+	utility, uus := endedCheck(goodUtility, 100, 100)
+	if uus != noUpdate {
+		t.Fatal(uus)
+	}
+	if !reflect.DeepEqual(utility, goodUtility) {
+		t.Fatal("wrong utility")
+	}
+	// Contract has ended.
+	utility, uus = endedCheck(goodUtility, 100, 101)
+	if uus != necessaryUtilityUpdate {
+		t.Fatal(uus)
+	}
+	if !reflect.DeepEqual(utility, badUtility) {
+		t.Fatal("wrong utility")
+	}
+}
+
+// TestUtilityChecksArchiveGrace verifies that a contract which is kept in the
+// active set during the archive grace period is neither good for upload nor
+// good for renew. That way it is neither renewed again nor counted towards the
+// allowance's hosts.
+func TestUtilityChecksArchiveGrace(t *testing.T) {
+	t.Parallel()
+
+	logger, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Contractor{
+		allowance: skymodules.Allowance{
+			Period:               100,
+			RenewWindow:          10,
+			ContractArchiveGrace: 10,
+		},
+		staticLog: logger,
+	}
+	contract := skymodules.RenterContract{
+		EndHeight:   100,
+		RenterFunds: types.SiacoinPrecision,
+		TotalCost:   types.SiacoinPrecision,
+		Transaction: types.Transaction{
+			FileContractRevisions: []types.FileContractRevision{{}},
+		},
+		Utility: skymodules.ContractUtility{
+			GoodForUpload: true,
+			GoodForRenew:  true,
+		},
+	}
+	host := skymodules.HostDBEntry{
+		ScanHistory: skymodules.HostDBScans{{Success: true}},
+	}
+	sb := skymodules.HostScoreBreakdown{Score: types.NewCurrency64(2)}
+
+	// Within the renew window, the contract is still good for renew.
+	c.blockHeight = 100
+	u, _ := c.managedUtilityChecks(contract, host, sb, types.ZeroCurrency, types.ZeroCurrency)
+	if !u.GoodForRenew {
+		t.Fatal("contract should be good for renew", u)
+	}
+
+	// After the end height, the contract is in the grace period and has no
+	// utility.
+	c.blockHeight = 101
+	u, uus := c.managedUtilityChecks(contract, host, sb, types.ZeroCurrency, types.ZeroCurrency)
+	if u.GoodForRenew || u.GoodForUpload || uus != necessaryUtilityUpdate {
+		t.Fatal("contract in grace period shouldn't have utility", u, uus)
+	}
+}
+
 // TestStorageGougingCheck is a unit test for storageGougingCheck.
 func TestStorageGougingCheck(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,310 @@
+package contractor
+
+import (
+	"container/list"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/SkynetLabs/skyd/build"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// oldContractsDir is the name of the directory within the contractor's
+	// persist dir that old contracts are spilled to once they are evicted
+	// from memory.
+	oldContractsDir = "oldcontracts"
+
+	// oldContractExtension is the extension of a spilled old contract's file.
+	oldContractExtension = ".json"
+)
+
+var (
+	// oldContractMeta is the metadata of a spilled old contract's file.
+	oldContractMeta = persist.Metadata{
+		Header:  "Old Contract",
+		Version: "1.0",
+	}
+
+	// defaultMaxOldContractsInMemory is the default number of old contracts
+	// the contractor keeps in memory. It can be overwritten with the
+	// SKYD_MAX_OLD_CONTRACTS environment variable.
+	defaultMaxOldContractsInMemory = build.Select(build.Var{
+		Dev:      1000,
+		Standard: 10000,
+		Testing:  50,
+	}).(int)
+)
+
+// oldContractStore holds the contractor's old contracts. At most maxInMemory
+// contracts are kept in memory. Once that limit is exceeded, the least recently
+// accessed contracts are spilled to disk and loaded again on demand. Contracts
+// that are relevant to the current period are never evicted, since they are
+// the ones most likely to be accessed.
+type oldContractStore struct {
+	// inMemory contains the contracts in memory. lru orders their ids from the
+	// most recently to the least recently accessed one.
+	inMemory map[types.FileContractID]*list.Element
+	lru      *list.List
+
+	// onDisk contains the ids of the contracts that were spilled to disk
+	// together with their spending. A contract can be both in memory and on
+	// disk, in which case the version in memory takes precedence.
+	onDisk map[types.FileContractID]types.Currency
+
+	// maxInMemory is the max number of contracts in memory. A value of 0
+	// disables the limit.
+	maxInMemory int
+
+	staticDir string
+	staticLog *persist.Logger
+	mu        sync.Mutex
+}
+
+// oldContractEntry is an element of the old contract store's lru.
+type oldContractEntry struct {
+	contract skymodules.RenterContract
+}
+
+// maxOldContractsInMemory returns the configured max number of old contracts
+// in memory.
+func maxOldContractsInMemory() int {
+	max, ok := build.MaxOldContracts()
+	if !ok {
+		return defaultMaxOldContractsInMemory
+	}
+	return max
+}
+
+// newOldContractStore creates a new, empty store which spills contracts to
+// the given dir. If dir is empty, all contracts are kept in memory.
+func newOldContractStore(dir string, maxInMemory int, log *persist.Logger) *oldContractStore {
+	return &oldContractStore{
+		inMemory:    make(map[types.FileContractID]*list.Element),
+		lru:         list.New(),
+		onDisk:      make(map[types.FileContractID]types.Currency),
+		maxInMemory: maxInMemory,
+		staticDir:   dir,
+		staticLog:   log,
+	}
+}
+
+// load initializes the store with the contracts that were previously spilled
+// to disk. Every spilled contract is read once to remember its spending.
+func (s *oldContractStore) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.staticDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.staticDir, 0700); err != nil {
+		return errors.AddContext(err, "failed to create old contracts dir")
+	}
+	fis, err := ioutil.ReadDir(s.staticDir)
+	if err != nil {
+		return errors.AddContext(err, "failed to read old contracts dir")
+	}
+	for _, fi := range fis {
+		name := fi.Name()
+		if fi.IsDir() || !strings.HasSuffix(name, oldContractExtension) {
+			continue
+		}
+		var fcid types.FileContractID
+		if err := fcid.LoadString(strings.TrimSuffix(name, oldContractExtension)); err != nil {
+			continue // ignore unknown files
+		}
+		contract, err := s.readContract(fcid)
+		if err != nil {
+			s.logf("WARN: failed to read old contract %v from disk: %v", fcid, err)
+			continue
+		}
+		s.onDisk[fcid] = oldContractSpending(contract)
+	}
+	return nil
+}
+
+// all returns all contracts of the store, including the ones on disk. The
+// contracts on disk are not moved into memory.
+func (s *oldContractStore) all() []skymodules.RenterContract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contracts := make([]skymodules.RenterContract, 0, len(s.inMemory)+len(s.onDisk))
+	for _, e := range s.inMemory {
+		contracts = append(contracts, e.Value.(*oldContractEntry).contract)
+	}
+	for fcid := range s.onDisk {
+		if _, exists := s.inMemory[fcid]; exists {
+			continue
+		}
+		contract, err := s.readContract(fcid)
+		if err != nil {
+			s.logf("WARN: failed to read old contract %v from disk: %v", fcid, err)
+			continue
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts
+}
+
+// allInMemory returns the contracts that are currently in memory.
+func (s *oldContractStore) allInMemory() []skymodules.RenterContract {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contracts := make([]skymodules.RenterContract, 0, len(s.inMemory))
+	for _, e := range s.inMemory {
+		contracts = append(contracts, e.Value.(*oldContractEntry).contract)
+	}
+	return contracts
+}
+
+// spilledSpending returns the total spending of the contracts that are only on
+// disk without reading them.
+func (s *oldContractStore) spilledSpending() types.Currency {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	spending := types.ZeroCurrency
+	for fcid, contractSpending := range s.onDisk {
+		if _, exists := s.inMemory[fcid]; exists {
+			continue
+		}
+		spending = spending.Add(contractSpending)
+	}
+	return spending
+}
+
+// delete removes a contract from the store.
+func (s *oldContractStore) delete(fcid types.FileContractID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, exists := s.inMemory[fcid]; exists {
+		s.lru.Remove(e)
+		delete(s.inMemory, fcid)
+	}
+	if _, exists := s.onDisk[fcid]; exists {
+		if err := persist.RemoveFile(s.contractPath(fcid)); err != nil {
+			s.logf("WARN: failed to remove old contract %v from disk: %v", fcid, err)
+		}
+		delete(s.onDisk, fcid)
+	}
+}
+
+// exists returns whether the store contains a contract with the given id.
+func (s *oldContractStore) exists(fcid types.FileContractID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, inMemory := s.inMemory[fcid]
+	_, onDisk := s.onDisk[fcid]
+	return inMemory || onDisk
+}
+
+// evict spills the least recently accessed contracts to disk until the number
+// of contracts in memory no longer exceeds the limit. Contracts that start or
+// end within the current period are not evicted. Since evict writes to disk,
+// it shouldn't be called while holding the contractor's lock.
+func (s *oldContractStore) evict(currentPeriod types.BlockHeight) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.staticDir == "" || s.maxInMemory <= 0 {
+		return
+	}
+	for e := s.lru.Back(); e != nil && len(s.inMemory) > s.maxInMemory; {
+		prev := e.Prev()
+		contract := e.Value.(*oldContractEntry).contract
+		if contract.StartHeight >= currentPeriod || contract.EndHeight >= currentPeriod {
+			e = prev
+			continue
+		}
+		if err := s.writeContract(contract); err != nil {
+			s.logf("WARN: failed to spill old contract %v to disk: %v", contract.ID, err)
+			e = prev
+			continue
+		}
+		s.onDisk[contract.ID] = oldContractSpending(contract)
+		s.lru.Remove(e)
+		delete(s.inMemory, contract.ID)
+		e = prev
+	}
+}
+
+// get returns the contract with the given id. If the contract was spilled to
+// disk, it is loaded back into memory.
+func (s *oldContractStore) get(fcid types.FileContractID) (skymodules.RenterContract, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, exists := s.inMemory[fcid]; exists {
+		s.lru.MoveToFront(e)
+		return e.Value.(*oldContractEntry).contract, true
+	}
+	if _, exists := s.onDisk[fcid]; !exists {
+		return skymodules.RenterContract{}, false
+	}
+	contract, err := s.readContract(fcid)
+	if err != nil {
+		s.logf("WARN: failed to read old contract %v from disk: %v", fcid, err)
+		return skymodules.RenterContract{}, false
+	}
+	s.inMemory[fcid] = s.lru.PushFront(&oldContractEntry{contract: contract})
+	return contract, true
+}
+
+// len returns the number of contracts in the store.
+func (s *oldContractStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.onDisk)
+	for fcid := range s.inMemory {
+		if _, onDisk := s.onDisk[fcid]; !onDisk {
+			n++
+		}
+	}
+	return n
+}
+
+// set adds a contract to the store or updates an existing one.
+func (s *oldContractStore) set(contract skymodules.RenterContract) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, exists := s.inMemory[contract.ID]; exists {
+		e.Value.(*oldContractEntry).contract = contract
+		s.lru.MoveToFront(e)
+		return
+	}
+	s.inMemory[contract.ID] = s.lru.PushFront(&oldContractEntry{contract: contract})
+}
+
+// oldContractSpending returns the total amount spent on a contract.
+func oldContractSpending(contract skymodules.RenterContract) types.Currency {
+	return contract.ContractFee.Add(contract.TxnFee).Add(contract.SiafundFee).
+		Add(contract.DownloadSpending).Add(contract.UploadSpending).Add(contract.StorageSpending).
+		Add(contract.FundAccountSpending).Add(contract.MaintenanceSpending.Sum())
+}
+
+// contractPath returns the path of a spilled contract's file.
+func (s *oldContractStore) contractPath(fcid types.FileContractID) string {
+	return filepath.Join(s.staticDir, fcid.String()+oldContractExtension)
+}
+
+// logf logs a message if the store has a logger.
+func (s *oldContractStore) logf(format string, args ...interface{}) {
+	if s.staticLog != nil {
+		s.staticLog.Printf(format, args...)
+	}
+}
+
+// readContract reads a spilled contract from disk.
+func (s *oldContractStore) readContract(fcid types.FileContractID) (skymodules.RenterContract, error) {
+	var contract skymodules.RenterContract
+	err := persist.LoadJSON(oldContractMeta, &contract, s.contractPath(fcid))
+	return contract, err
+}
+
+// writeContract spills a contract to disk.
+func (s *oldContractStore) writeContract(contract skymodules.RenterContract) error {
+	return persist.SaveJSON(oldContractMeta, contract, s.contractPath(contract.ID))
+}
@@ -0,0 +1,154 @@
+package contractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/SkynetLabs/skyd/build"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/types"
+)
+
+// TestOldContractStore tests spilling old contracts to disk and loading them
+// again.
+func TestOldContractStore(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	dir := build.TempDir("contractor", t.Name())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	s := newOldContractStore(dir, 1, nil)
+	if err := s.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add two contracts of a previous period and two of the current one.
+	currentPeriod := types.BlockHeight(100)
+	a := skymodules.RenterContract{ID: types.FileContractID{'a'}, StartHeight: 10, EndHeight: 50}
+	b := skymodules.RenterContract{ID: types.FileContractID{'b'}, StartHeight: 50, EndHeight: 90}
+	c := skymodules.RenterContract{ID: types.FileContractID{'c'}, StartHeight: 90, EndHeight: 130}
+	d := skymodules.RenterContract{ID: types.FileContractID{'d'}, StartHeight: 100, EndHeight: 140}
+	for _, contract := range []skymodules.RenterContract{a, b, c, d} {
+		s.set(contract)
+	}
+
+	// Evict. Only the contracts of the previous period should be spilled,
+	// even though the limit is exceeded afterwards.
+	s.evict(currentPeriod)
+	if len(s.inMemory) != 2 || len(s.onDisk) != 2 {
+		t.Fatal("wrong number of contracts in memory and on disk", len(s.inMemory), len(s.onDisk))
+	}
+	for _, fcid := range []types.FileContractID{a.ID, b.ID} {
+		if _, err := os.Stat(filepath.Join(dir, fcid.String()+oldContractExtension)); err != nil {
+			t.Fatal("contract wasn't spilled", err)
+		}
+	}
+	if s.len() != 4 || len(s.all()) != 4 || len(s.allInMemory()) != 2 {
+		t.Fatal("wrong number of contracts", s.len(), len(s.all()), len(s.allInMemory()))
+	}
+
+	// Fetch a spilled contract. It should be loaded back into memory.
+	contract, ok := s.get(a.ID)
+	if !ok {
+		t.Fatal("contract not found")
+	}
+	if contract.ID != a.ID || contract.EndHeight != a.EndHeight {
+		t.Fatal("wrong contract", contract)
+	}
+	if _, exists := s.inMemory[a.ID]; !exists {
+		t.Fatal("contract wasn't loaded into memory")
+	}
+	if s.len() != 4 {
+		t.Fatal("wrong number of contracts", s.len())
+	}
+
+	// Evict again. The loaded contract should be spilled again.
+	s.evict(currentPeriod)
+	if _, exists := s.inMemory[a.ID]; exists {
+		t.Fatal("contract wasn't evicted")
+	}
+
+	// Delete a and reload the store from disk. Only b should be left.
+	s.delete(a.ID)
+	if s.exists(a.ID) {
+		t.Fatal("contract wasn't deleted")
+	}
+	s = newOldContractStore(dir, 1, nil)
+	if err := s.load(); err != nil {
+		t.Fatal(err)
+	}
+	if s.exists(a.ID) || !s.exists(b.ID) || s.len() != 1 {
+		t.Fatal("wrong contracts after reload", s.all())
+	}
+}
+
+// TestPeriodSpendingSpilledContracts tests that the spending of old contracts
+// that were spilled to disk is still reported by PeriodSpending.
+func TestPeriodSpendingSpilledContracts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	_, c, _, cf, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Replace the old contracts with a store that only keeps one contract
+	// in memory.
+	dir := build.TempDir("contractor", t.Name(), oldContractsDir)
+	c.oldContracts = newOldContractStore(dir, 1, nil)
+	if err := c.oldContracts.load(); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	c.currentPeriod = 100
+	c.mu.Unlock()
+	for i := byte(0); i < 3; i++ {
+		c.oldContracts.set(skymodules.RenterContract{
+			ID:               types.FileContractID{i},
+			StartHeight:      10,
+			EndHeight:        50,
+			DownloadSpending: types.SiacoinPrecision,
+		})
+	}
+	before, err := c.PeriodSpending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !before.PreviousSpending.Equals(types.SiacoinPrecision.Mul64(3)) {
+		t.Fatal("wrong previous spending", before.PreviousSpending)
+	}
+
+	// Evict the contracts. The spending shouldn't change.
+	c.managedEvictOldContracts()
+	if len(c.oldContracts.allInMemory()) != 1 {
+		t.Fatal("contracts weren't spilled", len(c.oldContracts.allInMemory()))
+	}
+	after, err := c.PeriodSpending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.PreviousSpending.Equals(before.PreviousSpending) {
+		t.Fatal("previous spending changed after eviction", before.PreviousSpending, after.PreviousSpending)
+	}
+
+	// PeriodSpending shouldn't read the spilled contracts from disk.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	after, err = c.PeriodSpending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.PreviousSpending.Equals(before.PreviousSpending) {
+		t.Fatal("previous spending changed after removing the spilled contracts", before.PreviousSpending, after.PreviousSpending)
+	}
+}
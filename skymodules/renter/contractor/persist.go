@@ -41,6 +41,10 @@ type contractorPersist struct {
 	RenewedTo            map[string]types.FileContractID  `json:"renewedto"`
 	Synced               bool                             `json:"synced"`
 
+	ContractNotes           map[string]string      `json:"contractnotes"`
+	TemporaryAllowance      *temporaryAllowance    `json:"temporaryallowance,omitempty"`
+	UploadDisabledContracts []types.FileContractID `json:"uploaddisabledcontracts"`
+
 	// Subsystem persistence:
 	ChurnLimiter churnLimiterPersist `json:"churnlimiter"`
 	WatchdogData watchdogPersist     `json:"watchdogdata"`
@@ -72,7 +76,7 @@ func (c *Contractor) persistData() contractorPersist {
 	for k, v := range c.renewedTo {
 		data.RenewedTo[k.String()] = v
 	}
-	for _, contract := range c.oldContracts {
+	for _, contract := range c.oldContracts.allInMemory() {
 		data.OldContracts = append(data.OldContracts, contract)
 	}
 	for fcID, height := range c.doubleSpentContracts {
@@ -84,6 +88,19 @@ func (c *Contractor) persistData() contractorPersist {
 	for host := range c.preferredHosts {
 		data.PreferredHosts = append(data.PreferredHosts, host)
 	}
+	if len(c.contractNotes) > 0 {
+		data.ContractNotes = make(map[string]string, len(c.contractNotes))
+	}
+	for fcID, note := range c.contractNotes {
+		data.ContractNotes[fcID.String()] = note
+	}
+	for fcID := range c.uploadDisabledContracts {
+		data.UploadDisabledContracts = append(data.UploadDisabledContracts, fcID)
+	}
+	if c.temporaryAllowance != nil {
+		ta := *c.temporaryAllowance
+		data.TemporaryAllowance = &ta
+	}
 	data.ChurnLimiter = c.staticChurnLimiter.callPersistData()
 	data.WatchdogData = c.staticWatchdog.callPersistData()
 	return data
@@ -142,7 +159,7 @@ func (c *Contractor) load() error {
 		c.renewedTo[fcid] = v
 	}
 	for _, contract := range data.OldContracts {
-		c.oldContracts[contract.ID] = contract
+		c.oldContracts.set(contract)
 	}
 	for fcIDString, height := range data.DoubleSpentContracts {
 		if err := fcid.LoadString(fcIDString); err != nil {
@@ -156,6 +173,16 @@ func (c *Contractor) load() error {
 	for _, host := range data.PreferredHosts {
 		c.preferredHosts[host] = struct{}{}
 	}
+	for k, note := range data.ContractNotes {
+		if err := fcid.LoadString(k); err != nil {
+			return err
+		}
+		c.contractNotes[fcid] = note
+	}
+	for _, fcID := range data.UploadDisabledContracts {
+		c.uploadDisabledContracts[fcID] = struct{}{}
+	}
+	c.temporaryAllowance = data.TemporaryAllowance
 
 	c.staticChurnLimiter = newChurnLimiterFromPersist(c, data.ChurnLimiter)
 
@@ -168,6 +195,15 @@ func (c *Contractor) load() error {
 	return nil
 }
 
+// managedEvictOldContracts spills old contracts that exceed the in-memory
+// limit to disk.
+func (c *Contractor) managedEvictOldContracts() {
+	c.mu.RLock()
+	currentPeriod := c.currentPeriod
+	c.mu.RUnlock()
+	c.oldContracts.evict(currentPeriod)
+}
+
 // save saves the Contractor persistence data to disk.
 func (c *Contractor) save() error {
 	// c.persistData is broken out because stack traces will not include the
@@ -78,11 +78,10 @@ func TestSaveLoad(t *testing.T) {
 		{2}: expectedArchivedContract,
 	}
 
-	c.oldContracts = map[types.FileContractID]skymodules.RenterContract{
-		{0}: {ID: types.FileContractID{0}, HostPublicKey: types.SiaPublicKey{Key: []byte("foo")}},
-		{1}: {ID: types.FileContractID{1}, HostPublicKey: types.SiaPublicKey{Key: []byte("bar")}},
-		{2}: {ID: types.FileContractID{2}, HostPublicKey: types.SiaPublicKey{Key: []byte("baz")}},
-	}
+	c.oldContracts = newOldContractStore("", 0, nil)
+	c.oldContracts.set(skymodules.RenterContract{ID: types.FileContractID{0}, HostPublicKey: types.SiaPublicKey{Key: []byte("foo")}})
+	c.oldContracts.set(skymodules.RenterContract{ID: types.FileContractID{1}, HostPublicKey: types.SiaPublicKey{Key: []byte("bar")}})
+	c.oldContracts.set(skymodules.RenterContract{ID: types.FileContractID{2}, HostPublicKey: types.SiaPublicKey{Key: []byte("baz")}})
 
 	c.renewedFrom = map[types.FileContractID]types.FileContractID{
 		{1}: {2},
@@ -102,7 +101,7 @@ func TestSaveLoad(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c.oldContracts = make(map[types.FileContractID]skymodules.RenterContract)
+	c.oldContracts = newOldContractStore("", 0, nil)
 	c.renewedFrom = make(map[types.FileContractID]types.FileContractID)
 	c.renewedTo = make(map[types.FileContractID]types.FileContractID)
 	err = c.load()
@@ -110,11 +109,11 @@ func TestSaveLoad(t *testing.T) {
 		t.Fatal(err)
 	}
 	// Check that all fields were restored
-	_, ok0 := c.oldContracts[types.FileContractID{0}]
-	_, ok1 := c.oldContracts[types.FileContractID{1}]
-	_, ok2 := c.oldContracts[types.FileContractID{2}]
+	ok0 := c.oldContracts.exists(types.FileContractID{0})
+	ok1 := c.oldContracts.exists(types.FileContractID{1})
+	ok2 := c.oldContracts.exists(types.FileContractID{2})
 	if !ok0 || !ok1 || !ok2 {
-		t.Fatal("oldContracts were not restored properly:", c.oldContracts)
+		t.Fatal("oldContracts were not restored properly:", c.oldContracts.all())
 	}
 	id := types.FileContractID{2}
 	if c.renewedFrom[types.FileContractID{1}] != id {
@@ -153,7 +152,7 @@ func TestSaveLoad(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	c.oldContracts = make(map[types.FileContractID]skymodules.RenterContract)
+	c.oldContracts = newOldContractStore("", 0, nil)
 	c.renewedFrom = make(map[types.FileContractID]types.FileContractID)
 	c.renewedTo = make(map[types.FileContractID]types.FileContractID)
 	c.synced = make(chan struct{})
@@ -162,11 +161,11 @@ func TestSaveLoad(t *testing.T) {
 		t.Fatal(err)
 	}
 	// check that all fields were restored
-	_, ok0 = c.oldContracts[types.FileContractID{0}]
-	_, ok1 = c.oldContracts[types.FileContractID{1}]
-	_, ok2 = c.oldContracts[types.FileContractID{2}]
+	ok0 = c.oldContracts.exists(types.FileContractID{0})
+	ok1 = c.oldContracts.exists(types.FileContractID{1})
+	ok2 = c.oldContracts.exists(types.FileContractID{2})
 	if !ok0 || !ok1 || !ok2 {
-		t.Fatal("oldContracts were not restored properly:", c.oldContracts)
+		t.Fatal("oldContracts were not restored properly:", c.oldContracts.all())
 	}
 	if c.renewedFrom[types.FileContractID{1}] != id {
 		t.Fatal("renewedFrom not restored properly:", c.renewedFrom)
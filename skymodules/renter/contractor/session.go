@@ -53,6 +53,12 @@ type Session interface {
 	// Upload revises the underlying contract to store the new data. It
 	// returns the Merkle root of the data.
 	Upload(data []byte) (crypto.Hash, error)
+
+	// UploadJournaled appends multiple sectors in batches of at most
+	// batchSize sectors and records the progress in the journal. An
+	// interrupted upload is resumed from the last sector confirmed by the
+	// host when called again with the same journal.
+	UploadJournaled(j *proto.UploadJournal, sectors [][]byte, batchSize uint64) error
 }
 
 // A hostSession modifies a Contract via the renter-host RPC loop. It
@@ -179,6 +185,19 @@ func (hs *hostSession) Upload(data []byte) (crypto.Hash, error) {
 	return sectorRoot, nil
 }
 
+// UploadJournaled negotiates revisions that add the sectors to a file contract
+// and records the progress in the journal.
+func (hs *hostSession) UploadJournaled(j *proto.UploadJournal, sectors [][]byte, batchSize uint64) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if hs.invalid {
+		return errInvalidSession
+	}
+
+	_, err := hs.staticSession.AppendJournaled(j, sectors, batchSize)
+	return errors.AddContext(err, "unable to perform journaled upload in session")
+}
+
 // Replace replaces the sector at the specified index with data.
 func (hs *hostSession) Replace(data []byte, sectorIndex uint64, trim bool) (crypto.Hash, error) {
 	hs.mu.Lock()
@@ -0,0 +1,136 @@
+package contractor
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+)
+
+var (
+	// ErrTemporaryAllowanceEmpty is returned if the empty allowance is set as
+	// a temporary allowance.
+	ErrTemporaryAllowanceEmpty = errors.New("temporary allowance must not be empty")
+
+	// ErrTemporaryAllowanceZeroDuration is returned if a temporary allowance
+	// is set without a duration.
+	ErrTemporaryAllowanceZeroDuration = errors.New("temporary allowance duration must be non-zero")
+)
+
+// temporaryAllowance describes an allowance that was set temporarily and the
+// allowance it is reverted to.
+type temporaryAllowance struct {
+	// Previous is the allowance that was set before the temporary allowance
+	// and that will be restored at RevertAt.
+	Previous skymodules.Allowance `json:"previous"`
+	RevertAt time.Time            `json:"revertat"`
+}
+
+// TemporaryAllowance sets an allowance for the given duration, e.g. to raise
+// the funds and hosts for a planned burst of uploads. Once the duration has
+// passed, the contractor reverts to the allowance that was set before. The
+// revert time is persisted, so a restart doesn't prevent the revert. While the
+// temporary allowance is active, it is used by the contract maintenance like
+// any other allowance.
+//
+// If TemporaryAllowance is called while a temporary allowance is active, the
+// active one is replaced and the new duration applies, but the contractor
+// still reverts to the allowance that was set before the first temporary
+// allowance. If SetAllowance is called while a temporary allowance is active,
+// the pending revert is dropped and the manually set allowance stays in place,
+// even if the revert is already in progress.
+func (c *Contractor) TemporaryAllowance(a skymodules.Allowance, duration time.Duration) error {
+	if reflect.DeepEqual(a, skymodules.Allowance{}) {
+		return ErrTemporaryAllowanceEmpty
+	}
+	if duration <= 0 {
+		return ErrTemporaryAllowanceZeroDuration
+	}
+
+	c.allowanceLock.Lock()
+	defer c.allowanceLock.Unlock()
+
+	c.mu.RLock()
+	previous := c.allowance
+	if c.temporaryAllowance != nil {
+		previous = c.temporaryAllowance.Previous
+	}
+	c.mu.RUnlock()
+
+	// Apply the allowance.
+	if err := c.managedSetAllowance(a); err != nil {
+		return err
+	}
+
+	// Remember the allowance to revert to.
+	revertAt := time.Now().Add(duration)
+	c.mu.Lock()
+	c.temporaryAllowance = &temporaryAllowance{
+		Previous: previous,
+		RevertAt: revertAt,
+	}
+	err := c.save()
+	c.mu.Unlock()
+	if err != nil {
+		c.staticLog.Println("Unable to save contractor after setting temporary allowance:", err)
+	}
+	c.staticLog.Printf("INFO: set temporary allowance until %v, reverting to %v afterwards", revertAt, previous)
+
+	go c.threadedRevertTemporaryAllowance(revertAt)
+	return nil
+}
+
+// threadedRevertTemporaryAllowance waits until the given revert time and then
+// reverts the temporary allowance. If the temporary allowance was replaced or
+// dropped in the meantime, nothing is reverted. The temporary allowance is
+// cleared before the previous allowance is restored while holding the
+// allowanceLock, so a concurrent SetAllowance either drops the revert or is
+// applied after it. If the revert fails, the temporary allowance is restored so
+// that the revert is retried after a restart.
+func (c *Contractor) threadedRevertTemporaryAllowance(revertAt time.Time) {
+	if err := c.staticTG.Add(); err != nil {
+		return
+	}
+	defer c.staticTG.Done()
+
+	select {
+	case <-c.staticTG.StopChan():
+		return
+	case <-time.After(time.Until(revertAt)):
+	}
+
+	c.allowanceLock.Lock()
+	defer c.allowanceLock.Unlock()
+
+	// Clear the temporary allowance unless it was replaced or dropped in the
+	// meantime.
+	c.mu.Lock()
+	ta := c.temporaryAllowance
+	if ta == nil || !ta.RevertAt.Equal(revertAt) {
+		c.mu.Unlock()
+		return
+	}
+	c.temporaryAllowance = nil
+	c.mu.Unlock()
+
+	// Give tests a chance to change the allowance during the revert.
+	c.staticDeps.Disrupt("BlockTemporaryAllowanceRevert")
+
+	// Restore the previous allowance.
+	c.staticLog.Println("INFO: temporary allowance expired, reverting to", ta.Previous)
+	err := c.managedSetAllowance(ta.Previous)
+	if err != nil {
+		c.staticLog.Println("ERROR: failed to revert temporary allowance:", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.temporaryAllowance = ta
+		return
+	}
+	if err := c.save(); err != nil {
+		c.staticLog.Println("Unable to save contractor after expiring temporary allowance:", err)
+	}
+}
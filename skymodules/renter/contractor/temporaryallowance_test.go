@@ -0,0 +1,193 @@
+package contractor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/SkynetLabs/skyd/build"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// TestTemporaryAllowance tests setting a temporary allowance and reverting it.
+func TestTemporaryAllowance(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	_, c, _, cf, err := newTestingTrio(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Set a regular allowance.
+	a := skymodules.DefaultAllowance
+	if err := c.SetAllowance(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid temporary allowances should be rejected.
+	burst := a
+	burst.Funds = burst.Funds.Mul64(2)
+	burst.Hosts++
+	if err := c.TemporaryAllowance(skymodules.Allowance{}, time.Second); !errors.Contains(err, ErrTemporaryAllowanceEmpty) {
+		t.Fatal("unexpected error", err)
+	}
+	if err := c.TemporaryAllowance(burst, 0); !errors.Contains(err, ErrTemporaryAllowanceZeroDuration) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Set a temporary allowance. It should be active and persisted.
+	if err := c.TemporaryAllowance(burst, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Allowance(), burst) {
+		t.Fatal("temporary allowance wasn't applied")
+	}
+	c.mu.Lock()
+	data := c.persistData()
+	c.mu.Unlock()
+	if data.TemporaryAllowance == nil || !reflect.DeepEqual(data.TemporaryAllowance.Previous, a) {
+		t.Fatal("temporary allowance wasn't persisted", data.TemporaryAllowance)
+	}
+
+	// The allowance should be reverted after the duration.
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		if !reflect.DeepEqual(c.Allowance(), a) {
+			return errors.New("allowance wasn't reverted")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	reverted := c.temporaryAllowance == nil
+	c.mu.Unlock()
+	if !reverted {
+		t.Fatal("temporary allowance wasn't cleared")
+	}
+
+	// Set a temporary allowance again and change the allowance manually. The
+	// manual allowance should stay in place.
+	if err := c.TemporaryAllowance(burst, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	manual := a
+	manual.Funds = manual.Funds.Add(types.SiacoinPrecision)
+	if err := c.SetAllowance(manual); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Second)
+	if !reflect.DeepEqual(c.Allowance(), manual) {
+		t.Fatal("manual allowance was reverted")
+	}
+
+	// Set a temporary allowance with an invalid previous allowance. The
+	// revert fails and the temporary allowance should stay persisted.
+	if err := c.TemporaryAllowance(burst, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	invalid := a
+	invalid.Hosts = 0
+	c.mu.Lock()
+	c.temporaryAllowance = &temporaryAllowance{
+		Previous: invalid,
+		RevertAt: c.temporaryAllowance.RevertAt,
+	}
+	c.mu.Unlock()
+	time.Sleep(2 * time.Second)
+	if !reflect.DeepEqual(c.Allowance(), burst) {
+		t.Fatal("allowance shouldn't have changed", c.Allowance())
+	}
+	c.mu.Lock()
+	data = c.persistData()
+	c.mu.Unlock()
+	if data.TemporaryAllowance == nil {
+		t.Fatal("temporary allowance was cleared after a failed revert")
+	}
+}
+
+// dependencyBlockTemporaryAllowanceRevert blocks the revert of a temporary
+// allowance until release is closed and closes reached once the revert is
+// blocked.
+type dependencyBlockTemporaryAllowanceRevert struct {
+	modules.ProductionDependencies
+	reached chan struct{}
+	release chan struct{}
+}
+
+// Disrupt blocks the temporary allowance revert.
+func (d *dependencyBlockTemporaryAllowanceRevert) Disrupt(s string) bool {
+	if s != "BlockTemporaryAllowanceRevert" {
+		return false
+	}
+	close(d.reached)
+	<-d.release
+	return true
+}
+
+// TestTemporaryAllowanceRevertConcurrentSetAllowance tests that an allowance
+// set while a temporary allowance is being reverted isn't overwritten by the
+// revert.
+func TestTemporaryAllowanceRevertConcurrentSetAllowance(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	deps := &dependencyBlockTemporaryAllowanceRevert{
+		reached: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	_, c, _, cf, err := newTestingTrioWithContractorDeps(t.Name(), deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	a := skymodules.DefaultAllowance
+	if err := c.SetAllowance(a); err != nil {
+		t.Fatal(err)
+	}
+	burst := a
+	burst.Funds = burst.Funds.Mul64(2)
+	if err := c.TemporaryAllowance(burst, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the revert to start and set an allowance while it is blocked.
+	select {
+	case <-deps.reached:
+	case <-time.After(10 * time.Second):
+		t.Fatal("revert didn't start")
+	}
+	manual := a
+	manual.Funds = manual.Funds.Add(types.SiacoinPrecision)
+	errChan := make(chan error)
+	go func() {
+		errChan <- c.SetAllowance(manual)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(deps.release)
+	if err := <-errChan; err != nil {
+		t.Fatal(err)
+	}
+
+	// The manual allowance should be in place and the temporary allowance
+	// should be gone.
+	if !reflect.DeepEqual(c.Allowance(), manual) {
+		t.Fatal("manual allowance was overwritten", c.Allowance())
+	}
+	c.mu.Lock()
+	ta := c.temporaryAllowance
+	c.mu.Unlock()
+	if ta != nil {
+		t.Fatal("temporary allowance wasn't cleared")
+	}
+}
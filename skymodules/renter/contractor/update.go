@@ -32,12 +32,19 @@ func hasFCIdentifier(txn types.Transaction) (skymodules.ContractSignedIdentifier
 	return csi, hostKey, true
 }
 
+// contractArchivable returns whether a contract that ended at endHeight can be
+// archived at the current height, taking the archive grace period into account.
+func contractArchivable(currentHeight, endHeight, grace types.BlockHeight) bool {
+	return currentHeight > endHeight+grace
+}
+
 // managedArchiveContracts will figure out which contracts are no longer needed
 // and move them to the historic set of contracts.
 func (c *Contractor) managedArchiveContracts() {
-	// Determine the current block height.
+	// Determine the current block height and archive grace period.
 	c.mu.RLock()
 	currentHeight := c.blockHeight
+	grace := c.allowance.ContractArchiveGrace
 	c.mu.RUnlock()
 
 	// Loop through the current set of contracts and migrate any expired ones to
@@ -49,10 +56,12 @@ func (c *Contractor) managedArchiveContracts() {
 		c.mu.RLock()
 		_, renewed := c.renewedTo[contract.ID]
 		c.mu.RUnlock()
-		if currentHeight > contract.EndHeight || renewed {
+		if renewed || contractArchivable(currentHeight, contract.EndHeight, grace) {
 			id := contract.ID
 			c.mu.Lock()
-			c.oldContracts[id] = contract
+			c.oldContracts.set(contract)
+			// The upload setting only applies to active contracts.
+			delete(c.uploadDisabledContracts, id)
 			c.mu.Unlock()
 			expired = append(expired, id)
 			c.staticLog.Println("INFO: archived expired contract", id)
@@ -64,11 +73,13 @@ func (c *Contractor) managedArchiveContracts() {
 	c.save()
 	c.mu.Unlock()
 
-	// Delete all the expired contracts from the contract set.
+	// Delete all the expired contracts from the contract set and clear the
+	// alerts about their utilities since they are no longer in use.
 	for _, id := range expired {
 		if sc, ok := c.staticContracts.Acquire(id); ok {
 			c.staticContracts.Delete(sc)
 		}
+		c.staticAlerter.UnregisterAlert(alertIDRenewedContractUtility(id))
 	}
 }
 
@@ -124,7 +135,7 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 		// COMPATv1.0.4-lts
 		// if we were storing a special metrics contract, it will be invalid
 		// after we enter the next period.
-		delete(c.oldContracts, metricsContractID)
+		c.oldContracts.delete(metricsContractID)
 	}
 
 	// Check if c.synced already signals that the contractor is synced.
@@ -150,17 +161,25 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 		c.staticWatchdog.callCheckContracts()
 	}
 
+	// Add to churnLimiter budget. This happens before saving, so that the
+	// persisted budget matches the last processed consensus change. After a
+	// restart, the consensus changes since then are processed again, which
+	// adds the budget for the blocks that were mined while the contractor was
+	// offline.
+	numBlocksAdded := len(cc.AppliedBlocks) - len(cc.RevertedBlocks)
+	c.staticChurnLimiter.callBumpChurnBudget(numBlocksAdded, c.allowance)
+
 	c.lastChange = cc.ID
 	err = c.save()
 	if err != nil {
 		c.staticLog.Println("Unable to save while processing a consensus change:", err)
 	}
-	a := c.allowance
 	c.mu.Unlock()
 
-	// Add to churnLimiter budget.
-	numBlocksAdded := len(cc.AppliedBlocks) - len(cc.RevertedBlocks)
-	c.staticChurnLimiter.callBumpChurnBudget(numBlocksAdded, a.Period)
+	// Spill old contracts to disk. This happens after saving, so that an
+	// evicted contract is already on disk by the time it is dropped from the
+	// contractor's persistence on the next save.
+	c.managedEvictOldContracts()
 
 	// Perform contract maintenance if our blockchain is synced. Use a separate
 	// goroutine so that the rest of the contractor is not blocked during
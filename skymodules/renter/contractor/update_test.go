@@ -239,3 +239,99 @@ func TestIntegrationRenewInvalidate(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestContractArchivable is a unit test for contractArchivable.
+func TestContractArchivable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		current, end, grace types.BlockHeight
+		archivable          bool
+	}{
+		{current: 100, end: 100, grace: 0, archivable: false},
+		{current: 101, end: 100, grace: 0, archivable: true},
+		{current: 101, end: 100, grace: 10, archivable: false},
+		{current: 110, end: 100, grace: 10, archivable: false},
+		{current: 111, end: 100, grace: 10, archivable: true},
+	}
+	for i, test := range tests {
+		if archivable := contractArchivable(test.current, test.end, test.grace); archivable != test.archivable {
+			t.Errorf("%v: expected %v but got %v", i, test.archivable, archivable)
+		}
+	}
+}
+
+// TestArchiveContractsUnregistersUtilityAlert tests that archiving a contract
+// clears the alert about its utility.
+func TestArchiveContractsUnregistersUtilityAlert(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	_, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Register the alert for the contract and mark it as renewed to get it
+	// archived.
+	c.managedRegisterRenewedContractUtilityAlert(contract.ID, errors.New("failed"))
+	c.mu.Lock()
+	c.renewedTo[contract.ID] = types.FileContractID{1}
+	c.mu.Unlock()
+	hasAlert := func() bool {
+		_, errAlerts, _ := c.Alerts()
+		for _, alert := range errAlerts {
+			if alert.Msg == AlertMSGRenewedContractUtility {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasAlert() {
+		t.Fatal("alert wasn't registered")
+	}
+
+	// Archive the contract. The alert should be gone.
+	c.managedArchiveContracts()
+	if _, ok := c.staticContracts.View(contract.ID); ok {
+		t.Fatal("contract wasn't archived")
+	}
+	if hasAlert() {
+		t.Fatal("alert wasn't unregistered")
+	}
+}
+
+// TestArchiveContractsRemovesUploadDisabled tests that archiving a contract
+// removes it from the contracts with manually disabled uploads.
+func TestArchiveContractsRemovesUploadDisabled(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	_, c, contract, cf, err := newTestingTrioWithContract(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tryClose(cf, t)
+
+	// Disable uploads and mark the contract as renewed to get it archived.
+	if err := c.SetContractUploadable(contract.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	c.renewedTo[contract.ID] = types.FileContractID{1}
+	c.mu.Unlock()
+
+	// Archive the contract. The setting should be removed from memory and
+	// from the persistence.
+	c.managedArchiveContracts()
+	c.mu.Lock()
+	_, disabled := c.uploadDisabledContracts[contract.ID]
+	data := c.persistData()
+	c.mu.Unlock()
+	if disabled || len(data.UploadDisabledContracts) != 0 {
+		t.Fatal("setting wasn't removed", data.UploadDisabledContracts)
+	}
+}
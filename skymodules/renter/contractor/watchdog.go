@@ -138,7 +138,12 @@ func (c *Contractor) ContractStatus(fcID types.FileContractID) (skymodules.Contr
 		return skymodules.ContractWatchStatus{}, false
 	}
 	defer c.staticTG.Done()
-	return c.staticWatchdog.managedContractStatus(fcID)
+	status, ok := c.staticWatchdog.managedContractStatus(fcID)
+	if !ok {
+		return skymodules.ContractWatchStatus{}, false
+	}
+	status.Note = c.GetContractNote(fcID)
+	return status, true
 }
 
 // callAllowanceUpdated informs the watchdog of an allowance change.
@@ -749,7 +754,7 @@ func (w *watchdog) threadedCheckMonitorRevision(fcID types.FileContractID, heigh
 		// Try old contracts. If the contract was renewed already it won't be in the
 		// contractset.
 		w.staticContractor.mu.RLock()
-		contract, ok := w.staticContractor.oldContracts[fcID]
+		contract, ok := w.staticContractor.oldContracts.get(fcID)
 		if !ok {
 			w.staticContractor.staticLog.Debugln("Unable to Acquire monitored contract from oldContracts", fcID)
 			w.staticContractor.mu.RUnlock()
@@ -52,9 +52,11 @@ type (
 		staticParams downloadParams
 
 		// Retrieval settings for the file.
-		staticLatencyTarget time.Duration // In milliseconds. Lower latency results in lower total system throughput.
-		staticOverdrive     int           // How many extra pieces to download to prevent slow hosts from being a bottleneck.
-		staticPriority      uint64        // Downloads with higher priority will complete first.
+		staticLatencyTarget time.Duration         // In milliseconds. Lower latency results in lower total system throughput.
+		staticOverdrive     int                   // How many extra pieces to download to prevent slow hosts from being a bottleneck.
+		staticPriority      uint64                // Downloads with higher priority will complete first.
+		staticPriorityClass downloadPriorityClass // The class the download's retrieval settings are derived from.
+		staticClassParams   downloadPriorityClassParams
 
 		// Utilities.
 		staticRenter *Renter    // The renter that was used to create the download.
@@ -63,17 +65,19 @@ type (
 
 	// downloadParams is the set of parameters to use when downloading a file.
 	downloadParams struct {
-		destination       downloadDestination // The place to write the downloaded data.
-		destinationType   string              // "file", "buffer", "http stream", etc.
-		destinationString string              // The string to report to the user for the destination.
-		disableLocalFetch bool                // Whether or not the file can be fetched from disk if available.
-		file              *siafile.Snapshot   // The file to download.
-		latencyTarget     time.Duration       // Workers above this latency will be automatically put on standby initially.
-		length            uint64              // Length of download. Cannot be 0.
-		needsMemory       bool                // Whether new memory needs to be allocated to perform the download.
-		offset            uint64              // Offset within the file to start the download. Must be less than the total filesize.
-		overdrive         int                 // How many extra pieces to download to prevent slow hosts from being a bottleneck.
-		priority          uint64              // Files with a higher priority will be downloaded first.
+		destination       downloadDestination   // The place to write the downloaded data.
+		destinationType   string                // "file", "buffer", "http stream", etc.
+		destinationString string                // The string to report to the user for the destination.
+		disableLocalFetch bool                  // Whether or not the file can be fetched from disk if available.
+		file              *siafile.Snapshot     // The file to download.
+		length            uint64                // Length of download. Cannot be 0.
+		needsMemory       bool                  // Whether new memory needs to be allocated to perform the download.
+		offset            uint64                // Offset within the file to start the download. Must be less than the total filesize.
+		minOverdrive      uint64                // The number of extra pieces to download for each chunk of a single chunk download.
+		maxOverdrive      uint64                // The maximum number of extra pieces to download for each chunk. See scaledOverdrive. If 0, the priority class's bounds are used.
+		priorityClass     downloadPriorityClass // The class that determines the priority, latency target, default overdrive and gouging strictness.
+		priorityPieces    []uint64              // The indices of the pieces to launch workers for first in every chunk, e.g. the data pieces.
+		stickyWorkers     bool                  // Whether to prefer the workers that served the previous download of a chunk.
 
 		staticMemoryManager *memoryManager
 
@@ -239,6 +243,42 @@ func (r *Renter) DownloadAsync(p skymodules.RenterDownloadParameters, f func(err
 	}, d.managedCancel, nil
 }
 
+// DownloadToWriter downloads the given range of a file sequentially into the
+// provided writer and blocks until the download is finished. Contrary to the
+// streamer, the data is not cached which makes it more efficient for
+// downloading large ranges or full files. A length of 0 downloads the file
+// from the offset until the end.
+func (r *Renter) DownloadToWriter(siaPath skymodules.SiaPath, w io.Writer, offset, length uint64) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	if w == nil {
+		return errors.New("no writer provided")
+	}
+	d, err := r.managedDownload(skymodules.RenterDownloadParameters{
+		Httpwriter: w,
+		Length:     length,
+		Offset:     offset,
+		SiaPath:    siaPath,
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.Start(); err != nil {
+		return err
+	}
+	// Block until the download has completed. Cancel the download on shutdown
+	// to stop writing to w.
+	select {
+	case <-d.completeChan:
+		return d.Err()
+	case <-r.tg.StopChan():
+		d.managedCancel()
+		return errors.New("download interrupted by shutdown")
+	}
+}
+
 // managedDownload performs a file download using the passed parameters and
 // returns the download object and an error that indicates if the download
 // setup was successful.
@@ -323,12 +363,15 @@ func (r *Renter) managedDownload(p skymodules.RenterDownloadParameters) (_ *down
 		disableLocalFetch: p.DisableDiskFetch,
 		file:              snap,
 
-		latencyTarget: 25e3 * time.Millisecond, // TODO: high default until full latency support is added.
 		length:        p.Length,
 		needsMemory:   true,
 		offset:        p.Offset,
-		overdrive:     3, // TODO: moderate default until full overdrive support is added.
-		priority:      5, // TODO: moderate default until full priority support is added.
+		minOverdrive:  p.MinOverdrive,
+		maxOverdrive:  p.MaxOverdrive,
+		priorityClass: downloadPriorityClassUserBatch,
+		stickyWorkers: p.StickyWorkers,
+
+		priorityPieces: p.PriorityPieces,
 
 		staticMemoryManager:    r.staticUserDownloadMemoryManager, // user initiated download
 		staticSpendingCategory: categoryDownload,
@@ -378,6 +421,25 @@ func (r *Renter) managedNewDownload(params downloadParams) (*download, error) {
 	if params.offset+params.length > params.file.Size() {
 		return nil, errors.New("download is requesting data past the boundary of the file")
 	}
+	classParams, err := params.priorityClass.params()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the overdrive bounds of the priority class unless the caller
+	// provided custom ones.
+	if params.maxOverdrive == 0 {
+		params.minOverdrive = classParams.minOverdrive
+		params.maxOverdrive = classParams.maxOverdrive
+	}
+	if params.minOverdrive > params.maxOverdrive {
+		return nil, errors.New("download min overdrive cannot be larger than its max overdrive")
+	}
+
+	// Determine the overdrive of the download's chunks once based on the
+	// number of chunks.
+	minChunk, _, maxChunk, _ := downloadChunkRange(params.file, params.offset, params.length)
+	overdrive := scaledOverdrive(params.minOverdrive, params.maxOverdrive, maxChunk-minChunk+1)
 
 	// Create the download object.
 	d := &download{
@@ -389,12 +451,14 @@ func (r *Renter) managedNewDownload(params downloadParams) (*download, error) {
 		destinationString:     params.destinationString,
 		staticDestinationType: params.destinationType,
 		staticUID:             skymodules.DownloadID(hex.EncodeToString(fastrand.Bytes(16))),
-		staticLatencyTarget:   params.latencyTarget,
+		staticLatencyTarget:   classParams.latencyTarget,
 		staticLength:          params.length,
 		staticOffset:          params.offset,
-		staticOverdrive:       params.overdrive,
+		staticOverdrive:       overdrive,
 		staticSiaPath:         params.file.SiaPath(),
-		staticPriority:        params.priority,
+		staticPriority:        classParams.priority,
+		staticPriorityClass:   params.priorityClass,
+		staticClassParams:     classParams,
 
 		staticRenter: r,
 		staticParams: params,
@@ -425,16 +489,8 @@ func (d *download) Start() error {
 
 	// Determine which chunks to download.
 	params := d.staticParams
-	minChunk, minChunkOffset := params.file.ChunkIndexByOffset(params.offset)
-	maxChunk, maxChunkOffset := params.file.ChunkIndexByOffset(params.offset + params.length)
+	minChunk, minChunkOffset, maxChunk, maxChunkOffset := downloadChunkRange(params.file, params.offset, params.length)
 
-	// If the maxChunkOffset is exactly 0 we need to subtract 1 chunk. e.g. if
-	// the chunkSize is 100 bytes and we want to download 100 bytes from offset
-	// 0, maxChunk would be 1 and maxChunkOffset would be 0. We want maxChunk
-	// to be 0 though since we don't actually need any data from chunk 1.
-	if maxChunk > 0 && maxChunkOffset == 0 {
-		maxChunk--
-	}
 	// Make sure the requested chunks are within the boundaries.
 	if minChunk == params.file.NumChunks() || maxChunk == params.file.NumChunks() {
 		return errors.New("download is requesting a chunk that is past the boundary of the file")
@@ -491,9 +547,11 @@ func (d *download) Start() error {
 			// set based on the number of pieces 'n', and the 'n' fastest
 			// workers that we have.
 			staticDisableDiskFetch: params.disableLocalFetch,
+			staticSticky:           params.stickyWorkers,
 			staticLatencyTarget:    d.staticLatencyTarget + (25 * time.Duration(i-minChunk)), // Increase target by 25ms per chunk.
 			staticNeedsMemory:      params.needsMemory,
-			staticPriority:         params.priority,
+			staticPriority:         d.staticPriority,
+			staticClassParams:      d.staticClassParams,
 
 			completedPieces:   make([]bool, params.file.ErasureCode().NumPieces()),
 			physicalChunkData: make([][]byte, params.file.ErasureCode().NumPieces()),
@@ -523,10 +581,21 @@ func (d *download) Start() error {
 		udc.staticWriteOffset = writeOffset
 		writeOffset += int64(udc.staticFetchLength)
 
-		// TODO: Currently all chunks are given overdrive. This should probably
-		// be changed once the hostdb knows how to measure host speed/latency
-		// and once we can assign overdrive dynamically.
-		udc.staticOverdrive = params.overdrive
+		// TODO: Currently all chunks of a download are given the same
+		// overdrive. This should probably be changed once the hostdb knows how
+		// to measure host speed/latency and once we can assign overdrive
+		// dynamically.
+		udc.staticOverdrive = d.staticOverdrive
+
+		// Prefer the workers that served the previous download of the chunk.
+		if params.stickyWorkers {
+			udc.staticStickyWorkers = d.staticRenter.staticStickyWorkers.callWorkers(udc.staticCacheID)
+		}
+
+		// Launch the workers for the priority pieces first. The hosts that
+		// are waited for are set once the chunk is distributed to the
+		// workers.
+		udc.staticPriorityPieces = newPriorityPieces(params.priorityPieces, params.file.ErasureCode().NumPieces())
 
 		// Add this chunk to the chunk heap, and notify the download loop that
 		// there is work to do.
@@ -539,6 +608,44 @@ func (d *download) Start() error {
 	return nil
 }
 
+// downloadChunkRange returns the indices of the first and last chunk of the file
+// that contain data within the range of the download, as well as the offsets
+// of the range within those chunks.
+func downloadChunkRange(file *siafile.Snapshot, offset, length uint64) (minChunk, minChunkOffset, maxChunk, maxChunkOffset uint64) {
+	minChunk, minChunkOffset = file.ChunkIndexByOffset(offset)
+	maxChunk, maxChunkOffset = file.ChunkIndexByOffset(offset + length)
+
+	// If the maxChunkOffset is exactly 0 we need to subtract 1 chunk. e.g. if
+	// the chunkSize is 100 bytes and we want to download 100 bytes from offset
+	// 0, maxChunk would be 1 and maxChunkOffset would be 0. We want maxChunk
+	// to be 0 though since we don't actually need any data from chunk 1.
+	if maxChunk > 0 && maxChunkOffset == 0 {
+		maxChunk--
+	}
+	return
+}
+
+// scaledOverdrive returns the number of extra pieces to download for each chunk
+// of a download with numChunks chunks. Larger downloads benefit from more
+// parallelism while the overdrive of small downloads is mostly wasted money, so
+// the overdrive scales with the size of the download:
+//
+//	overdrive = min(maxOverdrive, minOverdrive + (numChunks-1) / downloadChunksPerOverdrive)
+//
+// A single chunk download uses minOverdrive and every additional
+// downloadChunksPerOverdrive chunks add another overdrive piece until
+// maxOverdrive is reached.
+func scaledOverdrive(minOverdrive, maxOverdrive, numChunks uint64) int {
+	overdrive := minOverdrive
+	if numChunks > 0 {
+		overdrive += (numChunks - 1) / downloadChunksPerOverdrive
+	}
+	if overdrive > maxOverdrive {
+		overdrive = maxOverdrive
+	}
+	return int(overdrive)
+}
+
 // DownloadByUID returns a single download from the history by it's UID.
 func (r *Renter) DownloadByUID(uid skymodules.DownloadID) (skymodules.DownloadInfo, bool) {
 	d, exists := r.staticDownloadHistory.callFetchDownload(uid)
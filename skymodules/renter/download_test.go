@@ -0,0 +1,126 @@
+package renter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"gitlab.com/SkynetLabs/skyd/skymodules/renter/filesystem"
+	"go.sia.tech/siad/persist"
+)
+
+// TestRenterDownloadToWriter verifies that DownloadToWriter validates its
+// input and writes the requested data to the provided writer.
+func TestRenterDownloadToWriter(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// A nil writer should fail.
+	err = rt.renter.DownloadToWriter(skymodules.RandomSiaPath(), nil, 0, 0)
+	if err == nil {
+		t.Fatal("expected download without writer to fail")
+	}
+
+	// A file that doesn't exist should fail.
+	var buf bytes.Buffer
+	err = rt.renter.DownloadToWriter(skymodules.RandomSiaPath(), &buf, 0, 0)
+	if !errors.Contains(err, filesystem.ErrNotExist) {
+		t.Fatal("expected ErrNotExist, got", err)
+	}
+
+	// Create a file.
+	entry, err := rt.renter.newRenterTestFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	siaPath := rt.renter.staticFileSystem.FileSiaPath(entry)
+	size := entry.Size()
+	if err := entry.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid range should fail.
+	err = rt.renter.DownloadToWriter(siaPath, &buf, size, 1)
+	if err == nil {
+		t.Fatal("expected download with invalid range to fail")
+	}
+	if buf.Len() != 0 {
+		t.Fatal("nothing should have been written", buf.Len())
+	}
+
+	// Back the file with a local file so it can be downloaded without hosts.
+	data := fastrand.Bytes(int(size))
+	localPath := filepath.Join(rt.dir, "localfile")
+	if err := ioutil.WriteFile(localPath, data, persist.DefaultDiskPermissionsTest); err != nil {
+		t.Fatal(err)
+	}
+	entry, err = rt.renter.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = entry.SetLocalPath(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entry.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Download the whole file.
+	err = rt.renter.DownloadToWriter(siaPath, &buf, 0, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("downloaded data doesn't match")
+	}
+
+	// Download a range of the file.
+	buf.Reset()
+	offset, length := size/4, size/2
+	err = rt.renter.DownloadToWriter(siaPath, &buf, offset, length)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data[offset:offset+length]) {
+		t.Fatal("downloaded range doesn't match")
+	}
+}
+
+// TestScaledOverdrive is a unit test for scaledOverdrive.
+func TestScaledOverdrive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		min, max, numChunks uint64
+		result              int
+	}{
+		{0, 0, 1000, 0},
+		{1, 5, 0, 1},
+		{1, 5, 1, 1},
+		{1, 5, downloadChunksPerOverdrive, 1},
+		{1, 5, downloadChunksPerOverdrive + 1, 2},
+		{1, 5, 2*downloadChunksPerOverdrive + 1, 3},
+		{1, 5, 1000, 5},
+		{5, 5, 1, 5},
+	}
+	for i, test := range tests {
+		if od := scaledOverdrive(test.min, test.max, test.numChunks); od != test.result {
+			t.Errorf("%v: expected %v but got %v", i, test.result, od)
+		}
+	}
+}
@@ -61,6 +61,23 @@ type unfinishedDownloadChunk struct {
 	staticOverdrive        int
 	staticPriority         uint64
 
+	// staticClassParams are the parameters of the download's priority class.
+	staticClassParams downloadPriorityClassParams
+
+	// staticSticky indicates whether the hosts that served the chunk are
+	// remembered for future downloads of the chunk. staticStickyWorkers are
+	// the hosts that served the previous download of the chunk and are
+	// preferred over other workers until standby workers are needed.
+	staticSticky        bool
+	staticStickyWorkers map[string]struct{}
+
+	// staticPriorityPieces are the indices of the pieces whose workers are
+	// launched before the workers of the other pieces.
+	// priorityHostsPending are the hosts that store a priority piece and
+	// whose workers haven't processed the chunk yet.
+	staticPriorityPieces map[uint64]struct{}
+	priorityHostsPending map[string]struct{}
+
 	// Download chunk state - need mutex to access.
 	completedPieces   []bool    // Which pieces were downloaded successfully.
 	failed            bool      // Indicates if the chunk has been marked as failed.
@@ -69,6 +86,7 @@ type unfinishedDownloadChunk struct {
 	piecesCompleted   int       // Number of pieces that have successfully completed.
 	piecesRegistered  int       // Number of pieces that workers are actively fetching.
 	recoveryComplete  bool      // Whether or not the recovery has completed and the chunk memory released.
+	criteriaRelaxed   bool      // Whether workers that fail the extra criteria may be used because standby workers were needed.
 	workersRemaining  int       // Number of workers still able to fetch the chunk.
 	workersStandby    []*worker // Set of workers that are able to work on this download, but are not needed unless other workers fail.
 
@@ -110,6 +128,13 @@ func (udc *unfinishedDownloadChunk) managedCleanUp() {
 	// Return any excess memory.
 	udc.returnMemory()
 
+	// Once the chunk failed or completed, there is nothing left to reserve
+	// pieces for.
+	chunkComplete := udc.piecesCompleted >= udc.erasureCode.MinPieces()
+	if udc.failed || chunkComplete {
+		udc.priorityHostsPending = nil
+	}
+
 	// Nothing to do if the chunk has failed.
 	if udc.failed {
 		udc.mu.Unlock()
@@ -117,7 +142,6 @@ func (udc *unfinishedDownloadChunk) managedCleanUp() {
 	}
 
 	// Check whether standby workers are required.
-	chunkComplete := udc.piecesCompleted >= udc.erasureCode.MinPieces()
 	desiredPiecesRegistered := udc.erasureCode.MinPieces() + udc.staticOverdrive - udc.piecesCompleted
 	standbyWorkersRequired := !chunkComplete && udc.piecesRegistered < desiredPiecesRegistered
 	if !standbyWorkersRequired {
@@ -134,6 +158,7 @@ func (udc *unfinishedDownloadChunk) managedCleanUp() {
 		standbyWorkers = append(standbyWorkers, udc.workersStandby[i])
 	}
 	udc.workersStandby = udc.workersStandby[:0] // Workers have been taken off of standby.
+	udc.criteriaRelaxed = true                  // Standby workers must not be put on standby again.
 	udc.mu.Unlock()
 	for i := 0; i < len(standbyWorkers); i++ {
 		go standbyWorkers[i].threadedPerformDownloadChunkJob(udc)
@@ -148,8 +173,17 @@ func (udc *unfinishedDownloadChunk) managedFinalizeRecovery() {
 	udc.mu.Lock()
 	udc.physicalChunkData = nil
 	udc.recoveryComplete = true
+	var usedHosts map[string]struct{}
+	if udc.staticSticky {
+		usedHosts = udc.usedHosts()
+	}
 	udc.mu.Unlock()
 
+	// Remember the hosts that served the chunk.
+	if udc.staticSticky {
+		udc.staticDownload.staticRenter.staticStickyWorkers.callUpdate(udc.staticCacheID, usedHosts)
+	}
+
 	// Update the download and signal completion of this chunk.
 	udc.staticDownload.mu.Lock()
 	defer udc.staticDownload.mu.Unlock()
@@ -158,6 +158,7 @@ func (r *Renter) managedDistributeDownloadChunkToWorkers(udc *unfinishedDownload
 	r.staticWorkerPool.mu.RLock()
 	udc.mu.Lock()
 	udc.workersRemaining = len(r.staticWorkerPool.workers)
+	udc.priorityHostsPending = newPriorityHostsPending(udc.staticChunkMap, udc.staticPriorityPieces, r.staticWorkerPool.workers)
 	udc.mu.Unlock()
 	for _, worker := range r.staticWorkerPool.workers {
 		go worker.threadedPerformDownloadChunkJob(udc)
@@ -0,0 +1,77 @@
+package renter
+
+// newPriorityPieces converts the given piece indices into a set. Indices that
+// are out of bounds for the given number of pieces are ignored. If no index is
+// left, nil is returned.
+func newPriorityPieces(indices []uint64, numPieces int) map[uint64]struct{} {
+	var pieces map[uint64]struct{}
+	for _, index := range indices {
+		if index >= uint64(numPieces) {
+			continue
+		}
+		if pieces == nil {
+			pieces = make(map[uint64]struct{})
+		}
+		pieces[index] = struct{}{}
+	}
+	return pieces
+}
+
+// newPriorityHostsPending returns the hosts of the chunk map that store one of
+// the priority pieces and have a worker. Hosts without a worker never process
+// the chunk, so they would otherwise reserve their piece forever.
+func newPriorityHostsPending(chunkMap map[string]downloadPieceInfo, priorityPieces map[uint64]struct{}, workers map[string]*worker) map[string]struct{} {
+	if len(priorityPieces) == 0 {
+		return nil
+	}
+	hosts := make(map[string]struct{})
+	for hpk, pieceInfo := range chunkMap {
+		if _, priority := priorityPieces[pieceInfo.index]; !priority {
+			continue
+		}
+		if _, hasWorker := workers[hpk]; !hasWorker {
+			continue
+		}
+		hosts[hpk] = struct{}{}
+	}
+	return hosts
+}
+
+// isPriorityPiece returns whether the piece with the given index is one of the
+// chunk's priority pieces.
+func (udc *unfinishedDownloadChunk) isPriorityPiece(index uint64) bool {
+	_, priority := udc.staticPriorityPieces[index]
+	return priority
+}
+
+// releasePriorityHost releases the reservation of the given host's piece. It
+// returns whether the host had a reservation.
+func (udc *unfinishedDownloadChunk) releasePriorityHost(hpk string) bool {
+	_, pending := udc.priorityHostsPending[hpk]
+	delete(udc.priorityHostsPending, hpk)
+	return pending
+}
+
+// reservedPriorityPieces returns the number of priority pieces that are neither
+// being fetched nor completed and whose workers haven't processed the chunk
+// yet. Workers for other pieces leave room for these pieces, so that the
+// priority pieces are launched first. The host with the given key is not
+// counted.
+//
+// A worker stops reserving its piece as soon as it processed the chunk, no
+// matter whether it was registered, put on standby or removed. That way a
+// priority piece whose worker is unusable can't starve the chunk.
+func (udc *unfinishedDownloadChunk) reservedPriorityPieces(hpk string) int {
+	reserved := make(map[uint64]struct{})
+	for host := range udc.priorityHostsPending {
+		if host == hpk {
+			continue
+		}
+		index := udc.staticChunkMap[host].index
+		if udc.pieceUsage[index] || udc.completedPieces[index] {
+			continue
+		}
+		reserved[index] = struct{}{}
+	}
+	return len(reserved)
+}
@@ -0,0 +1,143 @@
+package renter
+
+import (
+	"testing"
+)
+
+// TestNewPriorityPieces is a unit test for newPriorityPieces and
+// newPriorityHostsPending.
+func TestNewPriorityPieces(t *testing.T) {
+	t.Parallel()
+
+	// Without indices there are no priority pieces.
+	if pieces := newPriorityPieces(nil, 3); pieces != nil {
+		t.Fatal("expected nil", pieces)
+	}
+
+	// Indices out of bounds are ignored.
+	if pieces := newPriorityPieces([]uint64{3, 4}, 3); pieces != nil {
+		t.Fatal("expected nil", pieces)
+	}
+	pieces := newPriorityPieces([]uint64{0, 2, 2, 5}, 3)
+	if len(pieces) != 2 {
+		t.Fatal("wrong number of pieces", pieces)
+	}
+	for _, index := range []uint64{0, 2} {
+		if _, exists := pieces[index]; !exists {
+			t.Fatal("missing piece", index)
+		}
+	}
+
+	// Only the hosts of priority pieces are pending.
+	chunkMap := map[string]downloadPieceInfo{
+		"a": {index: 0},
+		"b": {index: 1},
+		"c": {index: 2},
+	}
+	workers := map[string]*worker{"a": {}, "b": {}, "c": {}}
+	if hosts := newPriorityHostsPending(chunkMap, nil, workers); hosts != nil {
+		t.Fatal("expected nil", hosts)
+	}
+	hosts := newPriorityHostsPending(chunkMap, pieces, workers)
+	if len(hosts) != 2 {
+		t.Fatal("wrong number of hosts", hosts)
+	}
+	for _, hpk := range []string{"a", "c"} {
+		if _, exists := hosts[hpk]; !exists {
+			t.Fatal("missing host", hpk)
+		}
+	}
+}
+
+// TestReservedPriorityPieces is a unit test for reservedPriorityPieces.
+func TestReservedPriorityPieces(t *testing.T) {
+	t.Parallel()
+
+	// Create a chunk where pieces 0 and 1 are prioritized. Piece 0 is stored
+	// on two hosts.
+	udc := &unfinishedDownloadChunk{
+		staticChunkMap: map[string]downloadPieceInfo{
+			"a": {index: 0},
+			"b": {index: 0},
+			"c": {index: 1},
+			"d": {index: 2},
+		},
+		completedPieces: make([]bool, 3),
+		pieceUsage:      make([]bool, 3),
+	}
+	udc.staticPriorityPieces = newPriorityPieces([]uint64{0, 1}, 3)
+	workers := map[string]*worker{"a": {}, "b": {}, "c": {}, "d": {}}
+	udc.priorityHostsPending = newPriorityHostsPending(udc.staticChunkMap, udc.staticPriorityPieces, workers)
+	if !udc.isPriorityPiece(0) || !udc.isPriorityPiece(1) || udc.isPriorityPiece(2) {
+		t.Fatal("wrong priority pieces")
+	}
+
+	// Both priority pieces are reserved. A piece that is stored on two hosts
+	// is only reserved once.
+	if reserved := udc.reservedPriorityPieces("d"); reserved != 2 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+
+	// The host's own piece is not reserved for itself.
+	if reserved := udc.reservedPriorityPieces("c"); reserved != 1 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+
+	// Pieces that are being fetched aren't reserved.
+	udc.pieceUsage[0] = true
+	if reserved := udc.reservedPriorityPieces("d"); reserved != 1 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+
+	// Neither are completed pieces.
+	udc.pieceUsage[0] = false
+	udc.completedPieces[1] = true
+	if reserved := udc.reservedPriorityPieces("d"); reserved != 1 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+
+	// Once all workers of a piece processed the chunk, it's not reserved
+	// anymore.
+	if !udc.releasePriorityHost("a") {
+		t.Fatal("host should have been pending")
+	}
+	if udc.releasePriorityHost("a") {
+		t.Fatal("host shouldn't be pending anymore")
+	}
+	if reserved := udc.reservedPriorityPieces("d"); reserved != 1 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+	udc.releasePriorityHost("b")
+	if reserved := udc.reservedPriorityPieces("d"); reserved != 0 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+}
+
+// TestPriorityHostWithoutWorker verifies that a priority host without a worker
+// doesn't reserve its piece, since it never processes the chunk.
+func TestPriorityHostWithoutWorker(t *testing.T) {
+	t.Parallel()
+
+	// Create a chunk where piece 0 is prioritized and stored on host "a",
+	// which has no worker.
+	udc := &unfinishedDownloadChunk{
+		staticChunkMap: map[string]downloadPieceInfo{
+			"a": {index: 0},
+			"b": {index: 1},
+		},
+		completedPieces: make([]bool, 2),
+		pieceUsage:      make([]bool, 2),
+	}
+	udc.staticPriorityPieces = newPriorityPieces([]uint64{0}, 2)
+	workers := map[string]*worker{"b": {}}
+	udc.priorityHostsPending = newPriorityHostsPending(udc.staticChunkMap, udc.staticPriorityPieces, workers)
+
+	// The host isn't pending, so the worker of the other piece doesn't leave
+	// room for its piece.
+	if len(udc.priorityHostsPending) != 0 {
+		t.Fatal("host without worker is pending", udc.priorityHostsPending)
+	}
+	if reserved := udc.reservedPriorityPieces("b"); reserved != 0 {
+		t.Fatal("wrong number of reserved pieces", reserved)
+	}
+}
@@ -0,0 +1,108 @@
+package renter
+
+import (
+	"fmt"
+	"time"
+)
+
+// downloadPriorityClass describes what kind of download is performed. Every
+// class maps to a coherent set of defaults for the download's priority, latency
+// target, overdrive and gouging strictness. This saves callers from having to
+// tune every download parameter independently. The classes don't map to a
+// price per millisecond since the download code they apply to doesn't trade
+// cost for latency. Skynet downloads take the price per millisecond from the
+// caller instead.
+type downloadPriorityClass int
+
+const (
+	// downloadPriorityClassUserInteractive is the class of downloads that a
+	// user is actively waiting on, e.g. streams. They are scheduled first and
+	// use the workers' high priority read queue so that background work never
+	// starves them on a shared worker. Their gouging checks are the most
+	// lenient to avoid failing a download the user is waiting on.
+	downloadPriorityClassUserInteractive downloadPriorityClass = iota
+
+	// downloadPriorityClassUserBatch is the class of user initiated downloads
+	// that are not latency sensitive, e.g. downloads to disk.
+	downloadPriorityClassUserBatch
+
+	// downloadPriorityClassBackground is the class of downloads the renter
+	// performs on its own, e.g. to repair files. They are scheduled last and
+	// don't pay for lower latency.
+	downloadPriorityClassBackground
+)
+
+// downloadPriorityClassParams are the download parameters a priority class
+// maps to.
+type downloadPriorityClassParams struct {
+	// gougingFractionDenom is the denominator of the fraction of the
+	// allowance's expected download that a host's prices need to cover
+	// without exceeding the allowance's funds. A smaller denominator means
+	// stricter gouging checks.
+	gougingFractionDenom uint64
+
+	// latencyTarget is the latency above which workers are put on standby
+	// initially.
+	latencyTarget time.Duration
+
+	// lowPrio indicates whether the workers' low priority read queue is used.
+	lowPrio bool
+
+	// minOverdrive and maxOverdrive are the default overdrive bounds of the
+	// class. See scaledOverdrive.
+	minOverdrive uint64
+	maxOverdrive uint64
+
+	// priority is the priority of the download's chunks in the download heap.
+	// Chunks with a higher priority are downloaded first.
+	priority uint64
+}
+
+// String implements the fmt.Stringer interface.
+func (c downloadPriorityClass) String() string {
+	switch c {
+	case downloadPriorityClassUserInteractive:
+		return "UserInteractive"
+	case downloadPriorityClassUserBatch:
+		return "UserBatch"
+	case downloadPriorityClassBackground:
+		return "Background"
+	default:
+		return fmt.Sprintf("unknown download priority class %d", int(c))
+	}
+}
+
+// params returns the download parameters of the priority class.
+func (c downloadPriorityClass) params() (downloadPriorityClassParams, error) {
+	switch c {
+	case downloadPriorityClassUserInteractive:
+		return downloadPriorityClassParams{
+			gougingFractionDenom: 2 * downloadGougingFractionDenom,
+			latencyTarget:        50 * time.Millisecond, // TODO: low default until full latency support is added.
+			lowPrio:              false,
+			minOverdrive:         5, // TODO: high default until full overdrive support is added.
+			maxOverdrive:         5,
+			priority:             1000,
+		}, nil
+	case downloadPriorityClassUserBatch:
+		return downloadPriorityClassParams{
+			gougingFractionDenom: downloadGougingFractionDenom,
+			latencyTarget:        25e3 * time.Millisecond, // TODO: high default until full latency support is added.
+			lowPrio:              true,
+			minOverdrive:         DefaultDownloadOverdrive,
+			maxOverdrive:         DefaultDownloadOverdrive,
+			priority:             5,
+		}, nil
+	case downloadPriorityClassBackground:
+		return downloadPriorityClassParams{
+			gougingFractionDenom: downloadGougingFractionDenom,
+			latencyTarget:        200e3, // No need to rush latency on background downloads.
+			lowPrio:              true,
+			minOverdrive:         0, // No need to rush the latency on background downloads.
+			maxOverdrive:         0,
+			priority:             0, // Background downloads are completely de-prioritized.
+		}, nil
+	default:
+		return downloadPriorityClassParams{}, fmt.Errorf("unknown download priority class %d", int(c))
+	}
+}
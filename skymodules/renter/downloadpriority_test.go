@@ -0,0 +1,67 @@
+package renter
+
+import (
+	"testing"
+)
+
+// TestDownloadPriorityClassParams is a unit test for the params of the
+// download priority classes.
+func TestDownloadPriorityClassParams(t *testing.T) {
+	t.Parallel()
+
+	interactive, err := downloadPriorityClassUserInteractive.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := downloadPriorityClassUserBatch.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	background, err := downloadPriorityClassBackground.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Interactive downloads are scheduled first and are never queued behind
+	// low priority reads.
+	if interactive.priority <= batch.priority || batch.priority <= background.priority {
+		t.Fatal("wrong priority order", interactive.priority, batch.priority, background.priority)
+	}
+	if interactive.lowPrio || !batch.lowPrio || !background.lowPrio {
+		t.Fatal("wrong read queues", interactive.lowPrio, batch.lowPrio, background.lowPrio)
+	}
+	if interactive.latencyTarget >= batch.latencyTarget {
+		t.Fatal("interactive downloads should have a lower latency target", interactive.latencyTarget, batch.latencyTarget)
+	}
+
+	// Interactive downloads use more lenient gouging checks. The other
+	// classes use the baseline.
+	if interactive.gougingFractionDenom <= downloadGougingFractionDenom {
+		t.Fatal("wrong gouging fraction", interactive.gougingFractionDenom)
+	}
+	if batch.gougingFractionDenom != downloadGougingFractionDenom || background.gougingFractionDenom != downloadGougingFractionDenom {
+		t.Fatal("wrong gouging fraction", batch.gougingFractionDenom, background.gougingFractionDenom)
+	}
+
+	// Background downloads don't overdrive.
+	if background.maxOverdrive != 0 {
+		t.Fatal("background downloads shouldn't overdrive", background.maxOverdrive)
+	}
+
+	// Batch downloads use a fixed overdrive by default.
+	if batch.minOverdrive != DefaultDownloadOverdrive || batch.maxOverdrive != DefaultDownloadOverdrive {
+		t.Fatal("wrong batch overdrive", batch.minOverdrive, batch.maxOverdrive)
+	}
+
+	// The overdrive bounds of every class need to be valid.
+	for _, params := range []downloadPriorityClassParams{interactive, batch, background} {
+		if params.minOverdrive > params.maxOverdrive {
+			t.Fatal("invalid overdrive bounds", params.minOverdrive, params.maxOverdrive)
+		}
+	}
+
+	// Unknown classes return an error.
+	if _, err := downloadPriorityClass(-1).params(); err == nil {
+		t.Fatal("expected error for unknown class")
+	}
+}
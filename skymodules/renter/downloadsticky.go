@@ -0,0 +1,85 @@
+package renter
+
+import (
+	"sync"
+)
+
+const (
+	// maxStickyWorkerChunks is the maximum number of chunks for which the
+	// sticky worker tracker remembers the workers that served them.
+	maxStickyWorkerChunks = 10000
+)
+
+// stickyWorkerTracker remembers which workers served the previous download of
+// a chunk. Downloads that enable sticky workers prefer these workers when
+// downloading the same chunk again, which improves the cache locality on the
+// hosts and makes the performance on hot files more predictable.
+type stickyWorkerTracker struct {
+	// chunks maps the cache id of a chunk to the set of hosts that served
+	// the chunk's last download.
+	chunks map[string]map[string]struct{}
+	mu     sync.Mutex
+}
+
+// newStickyWorkerTracker creates a new, empty tracker.
+func newStickyWorkerTracker() *stickyWorkerTracker {
+	return &stickyWorkerTracker{
+		chunks: make(map[string]map[string]struct{}),
+	}
+}
+
+// callWorkers returns the hosts that served the previous download of the
+// chunk with the given cache id or nil if the chunk wasn't downloaded before.
+func (swt *stickyWorkerTracker) callWorkers(cacheID string) map[string]struct{} {
+	swt.mu.Lock()
+	defer swt.mu.Unlock()
+	hosts, exists := swt.chunks[cacheID]
+	if !exists {
+		return nil
+	}
+	hostsCopy := make(map[string]struct{}, len(hosts))
+	for hpk := range hosts {
+		hostsCopy[hpk] = struct{}{}
+	}
+	return hostsCopy
+}
+
+// callUpdate sets the hosts that served the chunk with the given cache id. If
+// the tracker is full, an arbitrary chunk is evicted first.
+func (swt *stickyWorkerTracker) callUpdate(cacheID string, hosts map[string]struct{}) {
+	if len(hosts) == 0 {
+		return
+	}
+	swt.mu.Lock()
+	defer swt.mu.Unlock()
+	if _, exists := swt.chunks[cacheID]; !exists && len(swt.chunks) >= maxStickyWorkerChunks {
+		for id := range swt.chunks {
+			delete(swt.chunks, id)
+			break
+		}
+	}
+	swt.chunks[cacheID] = hosts
+}
+
+// meetsStickyCriteria returns whether the worker for the host with the given
+// key should be used right away for the chunk. If the chunk has a set of
+// sticky workers, other workers are put on standby until the chunk needs
+// standby workers.
+func (udc *unfinishedDownloadChunk) meetsStickyCriteria(hpk string) bool {
+	if len(udc.staticStickyWorkers) == 0 || udc.criteriaRelaxed {
+		return true
+	}
+	_, sticky := udc.staticStickyWorkers[hpk]
+	return sticky
+}
+
+// usedHosts returns the hosts that completed a piece of the chunk.
+func (udc *unfinishedDownloadChunk) usedHosts() map[string]struct{} {
+	hosts := make(map[string]struct{})
+	for hpk, pieceInfo := range udc.staticChunkMap {
+		if udc.completedPieces[pieceInfo.index] {
+			hosts[hpk] = struct{}{}
+		}
+	}
+	return hosts
+}
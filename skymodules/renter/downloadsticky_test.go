@@ -0,0 +1,87 @@
+package renter
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestStickyWorkerTracker is a unit test for the stickyWorkerTracker.
+func TestStickyWorkerTracker(t *testing.T) {
+	t.Parallel()
+
+	swt := newStickyWorkerTracker()
+
+	// Unknown chunks have no sticky workers.
+	if hosts := swt.callWorkers("chunk"); hosts != nil {
+		t.Fatal("expected nil", hosts)
+	}
+
+	// Empty updates are ignored.
+	swt.callUpdate("chunk", map[string]struct{}{})
+	if hosts := swt.callWorkers("chunk"); hosts != nil {
+		t.Fatal("expected nil", hosts)
+	}
+
+	// Set some hosts and make sure a copy is returned.
+	swt.callUpdate("chunk", map[string]struct{}{"a": {}, "b": {}})
+	hosts := swt.callWorkers("chunk")
+	if len(hosts) != 2 {
+		t.Fatal("wrong number of hosts", len(hosts))
+	}
+	delete(hosts, "a")
+	if len(swt.callWorkers("chunk")) != 2 {
+		t.Fatal("tracker was modified through returned map")
+	}
+
+	// The tracker is bounded.
+	for i := 0; i < maxStickyWorkerChunks+10; i++ {
+		swt.callUpdate(fmt.Sprint(i), map[string]struct{}{"a": {}})
+	}
+	if len(swt.chunks) != maxStickyWorkerChunks {
+		t.Fatal("wrong number of chunks", len(swt.chunks))
+	}
+}
+
+// TestMeetsStickyCriteria is a unit test for meetsStickyCriteria and
+// usedHosts.
+func TestMeetsStickyCriteria(t *testing.T) {
+	t.Parallel()
+
+	// Without sticky workers every worker meets the criteria.
+	udc := &unfinishedDownloadChunk{}
+	if !udc.meetsStickyCriteria("a") {
+		t.Fatal("worker should meet criteria")
+	}
+
+	// With sticky workers only those meet the criteria.
+	udc.staticStickyWorkers = map[string]struct{}{"a": {}}
+	if !udc.meetsStickyCriteria("a") {
+		t.Fatal("sticky worker should meet criteria")
+	}
+	if udc.meetsStickyCriteria("b") {
+		t.Fatal("non-sticky worker shouldn't meet criteria")
+	}
+
+	// Once relaxed, every worker meets the criteria.
+	udc.criteriaRelaxed = true
+	if !udc.meetsStickyCriteria("b") {
+		t.Fatal("worker should meet criteria")
+	}
+
+	// Only hosts with completed pieces are used hosts.
+	udc.staticChunkMap = map[string]downloadPieceInfo{
+		"a": {index: 0},
+		"b": {index: 1},
+		"c": {index: 2},
+	}
+	udc.completedPieces = []bool{true, false, true}
+	used := udc.usedHosts()
+	if len(used) != 2 {
+		t.Fatal("wrong number of used hosts", used)
+	}
+	for _, hpk := range []string{"a", "c"} {
+		if _, ok := used[hpk]; !ok {
+			t.Fatal("missing used host", hpk)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gitlab.com/NebulousLabs/errors"
@@ -13,7 +14,30 @@ import (
 	"gitlab.com/SkynetLabs/skyd/skymodules/renter/filesystem/siafile"
 )
 
+var (
+	// errStreamerInterrupted is returned if a streamer's download is
+	// interrupted by the renter shutting down.
+	errStreamerInterrupted = errors.New("download interrupted by shutdown")
+)
+
 type (
+	// StreamerOptions are the options that can be passed when creating a
+	// streamer.
+	StreamerOptions struct {
+		// DisableLocalFetch prevents the streamer from fetching data from the
+		// local disk.
+		DisableLocalFetch bool
+
+		// FillCacheRetries is the number of times a failed download is
+		// retried while filling the cache before the error is returned to
+		// the reader.
+		FillCacheRetries int
+
+		// FillCacheRetryBackoff is the time to wait before the first retry.
+		// It doubles with every following retry.
+		FillCacheRetryBackoff time.Duration
+	}
+
 	// streamer is a skymodules.Streamer that can be used to stream downloads from
 	// the sia network.
 	streamer struct {
@@ -61,8 +85,16 @@ type (
 		cacheReady              chan struct{}
 		staticDisableLocalFetch bool
 		readErr                 error
+		staticRetries           int
+		staticRetryBackoff      time.Duration
 		targetCacheSize         int64
 
+		// cacheMemory is the memory the streamer reserved from the renter's
+		// user download memory manager to grow its cache beyond the initial
+		// size. It is returned when the streamer is closed.
+		cacheMemory int64
+		closed      bool
+
 		// Mutex to protect the offset variable, and all of the cacheing
 		// variables.
 		mu sync.Mutex
@@ -166,64 +198,16 @@ func (s *streamer) managedFillCache() bool {
 		fetchLen = fileSize - fetchOffset
 	}
 
-	// Perform the actual download.
-	buffer := bytes.NewBuffer([]byte{})
-	ddw := newDownloadDestinationWriter(buffer)
-	d, err := s.staticRenter.managedNewDownload(downloadParams{
-		destination:       ddw,
-		destinationType:   destinationTypeSeekStream,
-		destinationString: "httpresponse",
-		disableLocalFetch: s.staticDisableLocalFetch,
-		file:              s.staticFile,
-
-		latencyTarget: 50 * time.Millisecond, // TODO: low default until full latency support is added.
-		length:        uint64(fetchLen),
-		needsMemory:   true,
-		offset:        uint64(fetchOffset),
-		overdrive:     5,    // TODO: high default until full overdrive support is added.
-		priority:      1000, // TODO: high default until full priority support is added.
-
-		staticMemoryManager:    s.staticRenter.staticUserDownloadMemoryManager, // user initiated download
-		staticSpendingCategory: categoryDownload,
+	// Perform the actual download, retrying on failure.
+	data, err := s.managedFetchWithRetries(func() ([]byte, error) {
+		return s.managedFetch(fetchOffset, fetchLen)
 	})
 	if err != nil {
-		closeErr := ddw.Close()
 		s.mu.Lock()
-		readErr := errors.Compose(s.readErr, err, closeErr)
+		readErr := errors.Compose(s.readErr, err)
 		s.readErr = readErr
 		s.mu.Unlock()
-		s.staticRenter.staticLog.Println("Error downloading for stream file:", readErr)
-		return false
-	}
-	// Register some cleanup for when the download is done.
-	d.OnComplete(func(_ error) error {
-		// close the destination buffer to avoid deadlocks.
-		return ddw.Close()
-	})
-	// Start the download.
-	if err := d.Start(); err != nil {
-		return false
-	}
-	// Block until the download has completed.
-	select {
-	case <-d.completeChan:
-		err := d.Err()
-		if err != nil {
-			completeErr := errors.AddContext(err, "download failed")
-			s.mu.Lock()
-			readErr := errors.Compose(s.readErr, completeErr)
-			s.readErr = readErr
-			s.mu.Unlock()
-			s.staticRenter.staticLog.Println("Error during stream download:", readErr)
-			return false
-		}
-	case <-s.staticRenter.tg.StopChan():
-		stopErr := errors.New("download interrupted by shutdown")
-		s.mu.Lock()
-		readErr := errors.Compose(s.readErr, stopErr)
-		s.readErr = readErr
-		s.mu.Unlock()
-		s.staticRenter.staticLog.Debugln(stopErr)
+		s.staticRenter.staticLog.Println("Error during stream download:", readErr)
 		return false
 	}
 
@@ -248,11 +232,7 @@ func (s *streamer) managedFillCache() bool {
 	targetCacheUnderLimit := s.targetCacheSize < maxStreamerCacheSize
 	cacheExists := cacheLen > 0
 	if cacheExists && partialDownloadsSupported && targetCacheUnderLimit && streamOffsetInTail {
-		if s.targetCacheSize*2 > maxStreamerCacheSize {
-			s.targetCacheSize = maxStreamerCacheSize
-		} else {
-			s.targetCacheSize *= 2
-		}
+		s.growTargetCacheSize(s.targetCacheSize * 2)
 	}
 
 	// Update the cache based on whether the entire cache needs to be replaced
@@ -261,11 +241,11 @@ func (s *streamer) managedFillCache() bool {
 	// supported, and also in the event that the stream offset is complete
 	// outside the previous cache.
 	if !partialDownloadsSupported || streamOffset >= cacheOffset+cacheLen || streamOffset < cacheOffset {
-		s.cache = buffer.Bytes()
+		s.cache = data
 		s.cacheOffset = fetchOffset
 	} else {
 		s.cache = s.cache[streamOffset-cacheOffset:]
-		s.cache = append(s.cache, buffer.Bytes()...)
+		s.cache = append(s.cache, data...)
 		s.cacheOffset = streamOffset
 	}
 
@@ -275,6 +255,68 @@ func (s *streamer) managedFillCache() bool {
 	return true
 }
 
+// managedFetchWithRetries calls fetch until it succeeds. A failed fetch is
+// retried up to staticRetries times, waiting staticRetryBackoff before the first
+// retry and doubling the backoff with every following retry.
+func (s *streamer) managedFetchWithRetries(fetch func() ([]byte, error)) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, err := fetch()
+		if err == nil || errors.Contains(err, errStreamerInterrupted) || attempt >= s.staticRetries {
+			return data, err
+		}
+		s.staticRenter.staticLog.Debugf("Retrying stream download after error (attempt %v/%v): %v", attempt+1, s.staticRetries, err)
+		select {
+		case <-time.After(s.staticRetryBackoff << uint(attempt)):
+		case <-s.staticRenter.tg.StopChan():
+			return nil, errors.Compose(err, errStreamerInterrupted)
+		}
+	}
+}
+
+// managedFetch downloads fetchLen bytes of the streamer's file starting at
+// fetchOffset.
+func (s *streamer) managedFetch(fetchOffset, fetchLen int64) ([]byte, error) {
+	buffer := bytes.NewBuffer([]byte{})
+	ddw := newDownloadDestinationWriter(buffer)
+	d, err := s.staticRenter.managedNewDownload(downloadParams{
+		destination:       ddw,
+		destinationType:   destinationTypeSeekStream,
+		destinationString: "httpresponse",
+		disableLocalFetch: s.staticDisableLocalFetch,
+		file:              s.staticFile,
+
+		length:        uint64(fetchLen),
+		needsMemory:   true,
+		offset:        uint64(fetchOffset),
+		priorityClass: downloadPriorityClassUserInteractive,
+
+		staticMemoryManager:    s.staticRenter.staticUserDownloadMemoryManager, // user initiated download
+		staticSpendingCategory: categoryDownload,
+	})
+	if err != nil {
+		return nil, errors.Compose(err, ddw.Close())
+	}
+	// Register some cleanup for when the download is done.
+	d.OnComplete(func(_ error) error {
+		// close the destination buffer to avoid deadlocks.
+		return ddw.Close()
+	})
+	// Start the download.
+	if err := d.Start(); err != nil {
+		return nil, errors.AddContext(err, "failed to start download")
+	}
+	// Block until the download has completed.
+	select {
+	case <-d.completeChan:
+		if err := d.Err(); err != nil {
+			return nil, errors.AddContext(err, "download failed")
+		}
+	case <-s.staticRenter.tg.StopChan():
+		return nil, errStreamerInterrupted
+	}
+	return buffer.Bytes(), nil
+}
+
 // threadedFillCache is a background thread that keeps the cache full as data is
 // read out of the cache. The Read and Seek functions have access to a channel
 // that they can use to signal that the cache should be refilled. To ensure that
@@ -314,8 +356,52 @@ func (s *streamer) threadedFillCache() {
 	}
 }
 
-// Close closes the streamer.
+// growTargetCacheSize grows the target cache size of the streamer to the
+// given size, capped at maxStreamerCacheSize. Growing the cache beyond its
+// initial size requires memory from the renter's user download memory manager,
+// which is shared with all user-initiated downloads. The streamers' caches may
+// use at most half of that memory, so that they can't starve the downloads. If
+// there is not enough memory, the cache doesn't grow.
+func (s *streamer) growTargetCacheSize(size int64) {
+	if size > maxStreamerCacheSize {
+		size = maxStreamerCacheSize
+	}
+	if size <= s.targetCacheSize || s.closed {
+		return
+	}
+	needed := size - initialStreamerCacheSize - s.cacheMemory
+	if needed > 0 {
+		mm := s.staticRenter.staticUserDownloadMemoryManager
+		cacheMemory := atomic.LoadUint64(&s.staticRenter.atomicStreamerCacheMemory)
+		if cacheMemory+uint64(needed) > mm.callStatus().PriorityBase/2 {
+			return
+		}
+		if !mm.TryRequest(uint64(needed), memoryPriorityLow) {
+			return
+		}
+		atomic.AddUint64(&s.staticRenter.atomicStreamerCacheMemory, uint64(needed))
+		s.cacheMemory += needed
+	}
+	s.targetCacheSize = size
+}
+
+// releaseCacheMemory returns the memory the streamer reserved to grow its
+// cache beyond the initial size.
+func (s *streamer) releaseCacheMemory() {
+	if s.cacheMemory <= 0 {
+		return
+	}
+	s.staticRenter.staticUserDownloadMemoryManager.Return(uint64(s.cacheMemory))
+	atomic.AddUint64(&s.staticRenter.atomicStreamerCacheMemory, ^uint64(s.cacheMemory-1))
+	s.cacheMemory = 0
+}
+
+// Close closes the streamer and returns the memory held by its cache.
 func (s *streamer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.releaseCacheMemory()
 	return nil
 }
 
@@ -356,13 +442,7 @@ func (s *streamer) Read(p []byte) (int, error) {
 		// size, to ensure that data is being fetched sufficiently far in
 		// advance.
 		twiceReadLen := int64(len(p) * 2)
-		if s.targetCacheSize < twiceReadLen {
-			if twiceReadLen > maxStreamerCacheSize {
-				s.targetCacheSize = maxStreamerCacheSize
-			} else {
-				s.targetCacheSize = twiceReadLen
-			}
-		}
+		s.growTargetCacheSize(twiceReadLen)
 
 		// Check if the cache contains data that we are interested in. If so,
 		// break out of the cache-fetch loop while still holding the lock.
@@ -456,6 +536,7 @@ func (s *streamer) Seek(offset int64, whence int) (int64, error) {
 	// so that we could set a target cache size according to that, but at the
 	// moment we don't have an easy way to get that information.
 	s.targetCacheSize = initialStreamerCacheSize
+	s.releaseCacheMemory()
 
 	// Update the offset of the stream and immediately send a thread to update
 	// the cache.
@@ -472,7 +553,13 @@ func (s *streamer) Seek(offset int64, whence int) (int64, error) {
 
 // Streamer creates a skymodules.Streamer that can be used to stream downloads from
 // the sia network.
-func (r *Renter) Streamer(siaPath skymodules.SiaPath, disableLocalFetch bool) (_ string, _ skymodules.Streamer, err error) {
+func (r *Renter) Streamer(siaPath skymodules.SiaPath, disableLocalFetch bool) (string, skymodules.Streamer, error) {
+	return r.StreamerWithOptions(siaPath, defaultStreamerOptions(disableLocalFetch))
+}
+
+// StreamerWithOptions creates a skymodules.Streamer that can be used to stream
+// downloads from the sia network using the provided options.
+func (r *Renter) StreamerWithOptions(siaPath skymodules.SiaPath, opts StreamerOptions) (_ string, _ skymodules.Streamer, err error) {
 	if err := r.tg.Add(); err != nil {
 		return "", nil, err
 	}
@@ -492,7 +579,7 @@ func (r *Renter) Streamer(siaPath skymodules.SiaPath, disableLocalFetch bool) (_
 	if err != nil {
 		return "", nil, err
 	}
-	s := r.managedStreamer(snap, disableLocalFetch)
+	s := r.managedStreamer(snap, opts)
 	return siaPath.String(), s, nil
 }
 
@@ -511,20 +598,31 @@ func (r *Renter) StreamerByNode(node *filesystem.FileNode, disableLocalFetch boo
 	if err != nil {
 		return nil, err
 	}
-	s := r.managedStreamer(snap, disableLocalFetch)
+	s := r.managedStreamer(snap, defaultStreamerOptions(disableLocalFetch))
 	return s, nil
 }
 
+// defaultStreamerOptions returns the default options for creating a streamer.
+func defaultStreamerOptions(disableLocalFetch bool) StreamerOptions {
+	return StreamerOptions{
+		DisableLocalFetch:     disableLocalFetch,
+		FillCacheRetries:      defaultStreamerFillCacheRetries,
+		FillCacheRetryBackoff: defaultStreamerFillCacheRetryBackoff,
+	}
+}
+
 // managedStreamer creates a streamer from a siafile snapshot and starts filling
 // its cache.
-func (r *Renter) managedStreamer(snapshot *siafile.Snapshot, disableLocalFetch bool) skymodules.Streamer {
+func (r *Renter) managedStreamer(snapshot *siafile.Snapshot, opts StreamerOptions) skymodules.Streamer {
 	s := &streamer{
 		staticFile:   snapshot,
 		staticRenter: r,
 
 		activateCache:           make(chan struct{}),
 		cacheReady:              make(chan struct{}),
-		staticDisableLocalFetch: disableLocalFetch,
+		staticDisableLocalFetch: opts.DisableLocalFetch,
+		staticRetries:           opts.FillCacheRetries,
+		staticRetryBackoff:      opts.FillCacheRetryBackoff,
 		targetCacheSize:         initialStreamerCacheSize,
 	}
 	go s.threadedFillCache()
@@ -0,0 +1,86 @@
+package renter
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/persist"
+)
+
+// TestStreamerFetchWithRetries is a unit test for managedFetchWithRetries.
+func TestStreamerFetchWithRetries(t *testing.T) {
+	t.Parallel()
+
+	r := new(Renter)
+	var err error
+	r.staticLog, err = persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &streamer{
+		staticRenter:       r,
+		staticRetries:      2,
+		staticRetryBackoff: 10 * time.Millisecond,
+	}
+
+	// fetch returns a helper that fails the given number of times before
+	// succeeding. The number of calls is tracked in calls.
+	var calls int
+	errFetch := errors.New("fetch failed")
+	fetch := func(failures int) func() ([]byte, error) {
+		calls = 0
+		return func() ([]byte, error) {
+			calls++
+			if calls <= failures {
+				return nil, errFetch
+			}
+			return []byte{1, 2, 3}, nil
+		}
+	}
+
+	// A fetch that fails twice should succeed on the last retry after backing
+	// off for 10ms and 20ms.
+	start := time.Now()
+	data, err := s.managedFetchWithRetries(fetch(2))
+	if err != nil || len(data) != 3 || calls != 3 {
+		t.Fatal("unexpected", err, data, calls)
+	}
+	if time.Since(start) < 30*time.Millisecond {
+		t.Fatal("retries didn't back off", time.Since(start))
+	}
+
+	// A fetch that fails three times should return the error.
+	_, err = s.managedFetchWithRetries(fetch(3))
+	if !errors.Contains(err, errFetch) || calls != 3 {
+		t.Fatal("unexpected", err, calls)
+	}
+
+	// An interrupted fetch shouldn't be retried.
+	calls = 0
+	_, err = s.managedFetchWithRetries(func() ([]byte, error) {
+		calls++
+		return nil, errStreamerInterrupted
+	})
+	if !errors.Contains(err, errStreamerInterrupted) || calls != 1 {
+		t.Fatal("unexpected", err, calls)
+	}
+
+	// Without retries, a failed fetch is returned right away.
+	s.staticRetries = 0
+	_, err = s.managedFetchWithRetries(fetch(1))
+	if !errors.Contains(err, errFetch) || calls != 1 {
+		t.Fatal("unexpected", err, calls)
+	}
+
+	// Once the renter is stopped, the streamer stops retrying.
+	s.staticRetries = 2
+	if err := r.tg.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.managedFetchWithRetries(fetch(1))
+	if !errors.Contains(err, errFetch) || !errors.Contains(err, errStreamerInterrupted) || calls != 1 {
+		t.Fatal("unexpected", err, calls)
+	}
+}
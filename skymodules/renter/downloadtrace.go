@@ -0,0 +1,68 @@
+package renter
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/SkynetLabs/skyd/build"
+)
+
+type (
+	// downloadID identifies a download across all of the chunks and workers
+	// that are involved in it. It is attached to the download's context, so
+	// that the log lines and spans of one download can be correlated.
+	downloadID [8]byte
+
+	// downloadIDKey is the context key of the download id.
+	downloadIDKey struct{}
+)
+
+// String implements the fmt.Stringer interface.
+func (id downloadID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// contextWithDownloadID returns a context that carries a download id. If the
+// given context already carries one, it is reused so that nested downloads,
+// e.g. the chunks of a stream, share the id of the outer download.
+func contextWithDownloadID(ctx context.Context) (context.Context, downloadID) {
+	if id, ok := ctx.Value(downloadIDKey{}).(downloadID); ok {
+		return ctx, id
+	}
+	var id downloadID
+	fastrand.Read(id[:])
+	return context.WithValue(ctx, downloadIDKey{}, id), id
+}
+
+// debugf logs a debug message that is tagged with the download id and the
+// chunk id of the pdc. The message is also logged to the pdc's span. It is a
+// no-op if debugging is off, to avoid formatting messages on the download path
+// that are never logged.
+func (pdc *projectDownloadChunk) debugf(format string, args ...interface{}) {
+	if !build.DEBUG {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if span := opentracing.SpanFromContext(pdc.ctx); span != nil {
+		span.LogKV("event", msg)
+	}
+	pdc.workerSet.staticRenter.staticLog.Debugf("%v %v", pdc.traceID(), msg)
+}
+
+// printf logs a message that is tagged with the download id and the chunk id
+// of the pdc. The message is also logged to the pdc's span.
+func (pdc *projectDownloadChunk) printf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if span := opentracing.SpanFromContext(pdc.ctx); span != nil {
+		span.LogKV("event", msg)
+	}
+	pdc.workerSet.staticRenter.staticLog.Printf("%v %v", pdc.traceID(), msg)
+}
+
+// traceID returns the prefix that identifies the pdc in log lines.
+func (pdc *projectDownloadChunk) traceID() string {
+	return fmt.Sprintf("[download %v chunk %v]", pdc.staticDownloadID, hex.EncodeToString(pdc.uid[:]))
+}
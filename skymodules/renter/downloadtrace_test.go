@@ -0,0 +1,35 @@
+package renter
+
+import (
+	"context"
+	"testing"
+)
+
+// TestContextWithDownloadID is a unit test for contextWithDownloadID.
+func TestContextWithDownloadID(t *testing.T) {
+	t.Parallel()
+
+	// A context without a download id should get a new one.
+	ctx, id := contextWithDownloadID(context.Background())
+	if id == (downloadID{}) {
+		t.Fatal("download id wasn't initialized")
+	}
+
+	// A context with a download id should keep it.
+	ctx2, id2 := contextWithDownloadID(ctx)
+	if id2 != id || ctx2 != ctx {
+		t.Fatal("download id wasn't reused", id, id2)
+	}
+
+	// Derived contexts should keep the id as well.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if _, id3 := contextWithDownloadID(child); id3 != id {
+		t.Fatal("download id wasn't inherited", id, id3)
+	}
+
+	// Independent contexts should get different ids.
+	if _, id4 := contextWithDownloadID(context.Background()); id4 == id {
+		t.Fatal("download ids should be unique")
+	}
+}
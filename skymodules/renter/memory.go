@@ -255,6 +255,18 @@ func (mm *memoryManager) Request(ctx context.Context, amount uint64, priority bo
 	}
 }
 
+// TryRequest is a non-blocking request for memory. It returns false if the
+// memory is not available right away or if other requests are already waiting
+// for memory.
+func (mm *memoryManager) TryRequest(amount uint64, priority bool) bool {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	if mm.priorityFifo.Len() != 0 || (!priority && mm.fifo.Len() != 0) {
+		return false
+	}
+	return mm.try(amount, priority)
+}
+
 // Return will return memory to the manager, waking any blocking threads which
 // now have enough memory to proceed.
 func (mm *memoryManager) Return(amount uint64) {
@@ -381,3 +393,13 @@ func newMemoryManager(baseMemory uint64, priorityMemory uint64, stopChan <-chan
 		staticStop:     stopChan,
 	}
 }
+
+// userDownloadMemory returns the configured amount of memory for user-initiated
+// downloads.
+func userDownloadMemory() uint64 {
+	mem, ok := build.DownloadMemory()
+	if !ok {
+		return userDownloadMemoryDefault
+	}
+	return mem
+}
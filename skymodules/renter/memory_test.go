@@ -3,8 +3,10 @@ package renter
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -746,3 +748,86 @@ func TestAddMemoryStatus(t *testing.T) {
 		t.Fatal("invalid")
 	}
 }
+
+// TestStreamerCacheMemory tests that the streamers' caches draw from the user
+// download memory budget.
+func TestStreamerCacheMemory(t *testing.T) {
+	t.Parallel()
+
+	// Create a renter with a budget that fits two initial caches.
+	budget := uint64(4 * initialStreamerCacheSize)
+	r := &Renter{
+		staticUserDownloadMemoryManager: newMemoryManager(budget, 0, make(chan struct{})),
+	}
+	newStreamer := func() *streamer {
+		return &streamer{
+			staticRenter:    r,
+			targetCacheSize: initialStreamerCacheSize,
+		}
+	}
+	status := func() skymodules.DownloadMemoryStatus {
+		s := r.staticUserDownloadMemoryManager.callStatus()
+		return skymodules.DownloadMemoryStatus{
+			Used:           s.PriorityBase - s.PriorityAvailable,
+			StreamerCaches: atomic.LoadUint64(&r.atomicStreamerCacheMemory),
+		}
+	}
+
+	// Grow a cache to twice its initial size. This should reserve the
+	// difference.
+	s1 := newStreamer()
+	s1.growTargetCacheSize(2 * initialStreamerCacheSize)
+	if s1.targetCacheSize != 2*initialStreamerCacheSize {
+		t.Fatal("cache didn't grow", s1.targetCacheSize)
+	}
+	if st := status(); st.Used != uint64(initialStreamerCacheSize) || st.StreamerCaches != uint64(initialStreamerCacheSize) {
+		t.Fatal("wrong status", st)
+	}
+
+	// Growing another cache by the same amount exceeds the half of the
+	// budget that the caches may use.
+	s2 := newStreamer()
+	s2.growTargetCacheSize(3 * initialStreamerCacheSize)
+	if s2.targetCacheSize != initialStreamerCacheSize {
+		t.Fatal("cache shouldn't grow", s2.targetCacheSize)
+	}
+
+	// Once the first streamer is closed, the second cache may grow.
+	if err := s1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if st := status(); st.Used != 0 || st.StreamerCaches != 0 {
+		t.Fatal("memory wasn't returned", st)
+	}
+	s2.growTargetCacheSize(2 * initialStreamerCacheSize)
+	if s2.targetCacheSize != 2*initialStreamerCacheSize {
+		t.Fatal("cache didn't grow", s2.targetCacheSize)
+	}
+
+	// Seeking resets the cache and returns its memory.
+	if _, err := s2.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if s2.targetCacheSize != initialStreamerCacheSize {
+		t.Fatal("cache wasn't reset", s2.targetCacheSize)
+	}
+	if st := status(); st.Used != 0 || st.StreamerCaches != 0 {
+		t.Fatal("memory wasn't returned", st)
+	}
+	s2.growTargetCacheSize(2 * initialStreamerCacheSize)
+	if s2.targetCacheSize != 2*initialStreamerCacheSize {
+		t.Fatal("cache didn't grow", s2.targetCacheSize)
+	}
+
+	// Closed streamers don't grow.
+	if err := s2.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s2.growTargetCacheSize(4 * initialStreamerCacheSize)
+	if s2.targetCacheSize != 2*initialStreamerCacheSize {
+		t.Fatal("closed cache shouldn't grow", s2.targetCacheSize)
+	}
+	if st := status(); st.Used != 0 || st.StreamerCaches != 0 {
+		t.Fatal("memory wasn't returned", st)
+	}
+}
@@ -340,17 +340,43 @@ func (ws *pcwsWorkerState) WaitForResults(ctx context.Context) []*pcwsWorkerResp
 	}
 }
 
-// managedLaunchWorker will launch a job to determine which sectors of a chunk
-// are available through that worker. The resulting unresolved worker is
-// returned so it can be added to the pending worker state.
-func (pcws *projectChunkWorkerSet) managedLaunchWorker(w *worker, responseChan chan *jobHasSectorResponse, ws *pcwsWorkerState) error {
+// managedHasSectorReason returns a human-readable reason why the worker with
+// the given host key can't download any pieces according to its HasSector
+// response. If there is no such reason, an empty string is returned together
+// with a boolean that indicates whether the worker is resolved.
+func (ws *pcwsWorkerState) managedHasSectorReason(hostKey string) (string, bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if _, exists := ws.unresolvedWorkers[hostKey]; exists {
+		return "", false
+	}
+	for _, resp := range ws.resolvedWorkers {
+		if resp.worker.staticHostPubKeyStr != hostKey {
+			continue
+		}
+		if resp.err != nil {
+			return fmt.Sprintf("HasSector query failed: %v", resp.err), true
+		}
+		if len(resp.pieceIndices) == 0 {
+			return "host doesn't have any of the pieces", true
+		}
+		return "", true
+	}
+	return "host wasn't queried for the pieces, it is either price gouging or its HasSector queue is unavailable", false
+}
+
+// managedCheckWorker checks whether the worker can be queried for the pieces of
+// the pcws. If it can, the penalty that should be added to the worker's
+// expected resolve time is returned.
+func (pcws *projectChunkWorkerSet) managedCheckWorker(w *worker) (time.Duration, error) {
 	// Check for gouging.
 	cache := w.staticCache()
 	pt := w.staticPriceTable().staticPriceTable
 	numWorkers := pcws.staticRenter.staticWorkerPool.callNumWorkers()
 	err := checkPCWSGouging(pt, cache.staticRenterAllowance, numWorkers, len(pcws.staticPieceRoots))
 	if err != nil {
-		return errors.AddContext(err, fmt.Sprintf("price gouging for chunk worker set detected in worker %v", w.staticHostPubKeyStr))
+		return 0, errors.AddContext(err, fmt.Sprintf("price gouging for chunk worker set detected in worker %v", w.staticHostPubKeyStr))
 	}
 
 	// Check whether the worker is on a cooldown. Because the PCWS is cached, we
@@ -364,6 +390,45 @@ func (pcws *projectChunkWorkerSet) managedLaunchWorker(w *worker, responseChan c
 		coolDownPenalty = time.Until(wms.cooldownUntil)
 		wms.mu.Unlock()
 	}
+	return coolDownPenalty, nil
+}
+
+// managedEstimateWorker adds the worker to the worker state as an unresolved
+// worker without launching a HasSector job. The expected resolve time is the
+// time a HasSector job would take if it was launched now.
+func (pcws *projectChunkWorkerSet) managedEstimateWorker(w *worker, ws *pcwsWorkerState) error {
+	coolDownPenalty, err := pcws.managedCheckWorker(w)
+	if err != nil {
+		return err
+	}
+
+	// Only consider workers that would accept the job.
+	jq := w.staticJobHasSectorQueue
+	if jq.callOnCooldown() {
+		return fmt.Errorf("has sector queue of %v is on a cooldown", w.staticHostPubKeyStr)
+	}
+	expectedJobTime := jq.callExpectedJobTime()
+	if expectedJobTime > pcwsHasSectorTimeout {
+		return errEstimateAboveMax
+	}
+
+	ws.mu.Lock()
+	ws.unresolvedWorkers[w.staticHostPubKeyStr] = &pcwsUnresolvedWorker{
+		staticWorker:               w,
+		staticExpectedResolvedTime: time.Now().Add(expectedJobTime + coolDownPenalty),
+	}
+	ws.mu.Unlock()
+	return nil
+}
+
+// managedLaunchWorker will launch a job to determine which sectors of a chunk
+// are available through that worker. The resulting unresolved worker is
+// returned so it can be added to the pending worker state.
+func (pcws *projectChunkWorkerSet) managedLaunchWorker(w *worker, responseChan chan *jobHasSectorResponse, ws *pcwsWorkerState) error {
+	coolDownPenalty, err := pcws.managedCheckWorker(w)
+	if err != nil {
+		return err
+	}
 
 	// Create and launch the job.
 	ctx, cancel := context.WithTimeout(pcws.staticCtx, pcwsHasSectorTimeout)
@@ -497,6 +562,39 @@ func (pcws *projectChunkWorkerSet) managedDownload(ctx context.Context, pricePer
 		return nil, errors.Compose(ErrProjectTimedOut, ErrRootNotFound)
 	}
 
+	// Make sure the download can be traced and start a span for the PDC.
+	ctx, id := contextWithDownloadID(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "managedDownload")
+	span.SetTag("downloadID", id.String())
+
+	// Build the full pdc.
+	pdc, err := pcws.managedNewProjectDownloadChunk(ctx, pricePerMS, offset, length, skipRecovery, lowPrio)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set debug variables on the pdc
+	pdc.staticDownloadID = id
+	fastrand.Read(pdc.uid[:])
+	pdc.launchTime = time.Now()
+
+	// Launch the initial set of workers for the pdc.
+	err = pdc.launchInitialWorkers()
+	if err != nil {
+		return nil, errors.Compose(err, ErrRootNotFound)
+	}
+
+	// All initial workers have been launched. The function can return now,
+	// unblocking the caller. A background thread will be launched to collect
+	// the responses and launch overdrive workers when necessary.
+	go pdc.threadedCollectAndOverdrivePieces()
+	return pdc.downloadResponseChan, nil
+}
+
+// managedNewProjectDownloadChunk creates a projectDownloadChunk for
+// downloading the given range from the chunk. The pcws' worker state is
+// refreshed if necessary before creating the pdc.
+func (pcws *projectChunkWorkerSet) managedNewProjectDownloadChunk(ctx context.Context, pricePerMS types.Currency, offset, length uint64, skipRecovery, lowPrio bool) (*projectDownloadChunk, error) {
 	// Convenience variables.
 	ec := pcws.staticErasureCoder
 
@@ -555,11 +653,7 @@ func (pcws *projectChunkWorkerSet) managedDownload(ctx context.Context, pricePer
 	// extra goroutines to be spawned.
 	workerResponseChan := make(chan *jobReadResponse, ec.NumPieces()*5)
 
-	// Start a span for the PDC.
-	_, ctx = opentracing.StartSpanFromContext(ctx, "managedDownload")
-
-	// Build the full pdc.
-	pdc := &projectDownloadChunk{
+	return &projectDownloadChunk{
 		offsetInChunk: offset,
 		lengthInChunk: length,
 
@@ -568,7 +662,8 @@ func (pcws *projectChunkWorkerSet) managedDownload(ctx context.Context, pricePer
 
 		staticIsLowPrio: lowPrio,
 
-		pricePerMS: pricePerMS,
+		pricePerMS:            pricePerMS,
+		staticVariancePenalty: pcws.staticRenter.staticDownloadVariancePenalty,
 
 		availablePieces:         make([][]*pieceDownload, ec.NumPieces()),
 		availablePiecesByWorker: make(map[string][]uint64),
@@ -581,23 +676,7 @@ func (pcws *projectChunkWorkerSet) managedDownload(ctx context.Context, pricePer
 		downloadResponseChan: make(chan *downloadResponse, 1),
 		workerSet:            pcws,
 		workerState:          ws,
-	}
-
-	// Set debug variables on the pdc
-	fastrand.Read(pdc.uid[:])
-	pdc.launchTime = time.Now()
-
-	// Launch the initial set of workers for the pdc.
-	err = pdc.launchInitialWorkers()
-	if err != nil {
-		return nil, errors.Compose(err, ErrRootNotFound)
-	}
-
-	// All initial workers have been launched. The function can return now,
-	// unblocking the caller. A background thread will be launched to collect
-	// the responses and launch overdrive workers when necessary.
-	go pdc.threadedCollectAndOverdrivePieces()
-	return pdc.downloadResponseChan, nil
+	}, nil
 }
 
 // newPCWSByRoots will create a worker set to download a chunk given just the
@@ -608,6 +687,57 @@ func (pcws *projectChunkWorkerSet) managedDownload(ctx context.Context, pricePer
 // expected to have, it can be provided as a seedWorker. A seedWorker is
 // considered to be resolved right away.
 func (r *Renter) newPCWSByRoots(ctx context.Context, roots []crypto.Hash, ec skymodules.ErasureCoder, masterKey crypto.CipherKey, chunkIndex uint64) (*projectChunkWorkerSet, error) {
+	pcws, err := r.newBlankPCWS(ctx, roots, ec, masterKey, chunkIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// The worker state is blank, ensure that everything can get started.
+	err = pcws.managedTryUpdateWorkerState()
+	if err != nil {
+		return nil, errors.AddContext(err, "cannot create a new PCWS")
+	}
+
+	// Return the worker set.
+	return pcws, nil
+}
+
+// newEstimatePCWSByRoots creates a worker set for the given roots that can be
+// used to estimate downloads. Contrary to newPCWSByRoots, no HasSector jobs are
+// launched. Instead, every worker that would be queried is added as an
+// unresolved worker. When estimating, unresolved workers are weighted by the
+// availability rate of their past HasSector results, see
+// estimateInitialWorkerSet.
+func (r *Renter) newEstimatePCWSByRoots(ctx context.Context, roots []crypto.Hash, ec skymodules.ErasureCoder, masterKey crypto.CipherKey, chunkIndex uint64) (*projectChunkWorkerSet, error) {
+	pcws, err := r.newBlankPCWS(ctx, roots, ec, masterKey, chunkIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &pcwsWorkerState{
+		unresolvedWorkers: make(map[string]*pcwsUnresolvedWorker),
+
+		staticRenter: r,
+	}
+	for _, w := range r.staticWorkerPool.callWorkers() {
+		err := pcws.managedEstimateWorker(w, ws)
+		if err != nil && !errors.Contains(err, errEstimateAboveMax) {
+			r.staticLog.Debugf("failed to estimate worker: %v", err)
+		}
+	}
+
+	// Set the worker state as if it was just launched to prevent the pcws
+	// from launching the HasSector jobs on the first download.
+	pcws.workerState = ws
+	pcws.workerStateLaunchTime = time.Now()
+	pcws.updateFinishedChan = make(chan struct{})
+	close(pcws.updateFinishedChan)
+	return pcws, nil
+}
+
+// newBlankPCWS creates a worker set for the given roots without a worker
+// state.
+func (r *Renter) newBlankPCWS(ctx context.Context, roots []crypto.Hash, ec skymodules.ErasureCoder, masterKey crypto.CipherKey, chunkIndex uint64) (*projectChunkWorkerSet, error) {
 	// Check that the number of roots provided is consistent with the erasure
 	// coder provided.
 	//
@@ -623,7 +753,7 @@ func (r *Renter) newPCWSByRoots(ctx context.Context, roots []crypto.Hash, ec sky
 	}
 
 	// Create the worker set.
-	pcws := &projectChunkWorkerSet{
+	return &projectChunkWorkerSet{
 		staticChunkIndex:   chunkIndex,
 		staticErasureCoder: ec,
 		staticMasterKey:    masterKey,
@@ -631,14 +761,5 @@ func (r *Renter) newPCWSByRoots(ctx context.Context, roots []crypto.Hash, ec sky
 
 		staticCtx:    ctx,
 		staticRenter: r,
-	}
-
-	// The worker state is blank, ensure that everything can get started.
-	err := pcws.managedTryUpdateWorkerState()
-	if err != nil {
-		return nil, errors.AddContext(err, "cannot create a new PCWS")
-	}
-
-	// Return the worker set.
-	return pcws, nil
+	}, nil
 }
@@ -567,6 +567,84 @@ func TestProjectChunkWorsetSet_managedLaunchWorker(t *testing.T) {
 	}
 }
 
+// TestProjectChunkWorsetSet_managedEstimateWorker is a unit test for
+// managedEstimateWorker. It verifies that the worker is added as an unresolved
+// worker without launching a HasSector job.
+func TestProjectChunkWorsetSet_managedEstimateWorker(t *testing.T) {
+	t.Parallel()
+
+	// create EC + key
+	ec := skymodules.NewPassthroughErasureCoder()
+	ck, err := crypto.NewSiaKey(crypto.TypePlain, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create renter
+	renter := new(Renter)
+	renter.staticWorkerPool = new(workerPool)
+
+	// create PCWS
+	pcws := &projectChunkWorkerSet{
+		staticChunkIndex:   0,
+		staticErasureCoder: ec,
+		staticMasterKey:    ck,
+		staticPieceRoots:   []crypto.Hash{},
+
+		staticCtx:    context.Background(),
+		staticRenter: renter,
+	}
+
+	// create PCWS worker state
+	ws := &pcwsWorkerState{
+		unresolvedWorkers: make(map[string]*pcwsUnresolvedWorker),
+		staticRenter:      pcws.staticRenter,
+	}
+
+	// mock the worker
+	w := new(worker)
+	w.newCache()
+	w.newPriceTable()
+	w.newMaintenanceState()
+	w.initJobHasSectorQueue()
+	w.staticHostPubKeyStr = "myworker"
+	w.staticPriceTable().staticExpiryTime = time.Now().Add(time.Hour)
+
+	// estimate the worker with an estimate above the max - shouldn't work
+	w.staticJobHasSectorQueue.weightedJobTime = float64(123 * time.Second)
+	err = pcws.managedEstimateWorker(w, ws)
+	if !errors.Contains(err, errEstimateAboveMax) {
+		t.Fatal(err)
+	}
+	if _, exists := ws.unresolvedWorkers["myworker"]; exists {
+		t.Fatal("unexpected")
+	}
+
+	// put the worker on a cooldown and estimate it again
+	w.staticJobHasSectorQueue.weightedJobTime = float64(pcwsHasSectorTimeout)
+	w.staticMaintenanceState.cooldownUntil = time.Now().Add(time.Minute)
+	err = pcws.managedEstimateWorker(w, ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the estimate includes the cooldown
+	uw, exists := ws.unresolvedWorkers["myworker"]
+	if !exists {
+		t.Fatal("unexpected")
+	}
+	expectedDurInS := math.Round(time.Until(uw.staticExpectedResolvedTime).Seconds())
+	if expectedDurInS != pcwsHasSectorTimeout.Seconds()+60 {
+		t.Log(expectedDurInS)
+		t.Fatal("unexpected")
+	}
+
+	// verify no job was added to the queue
+	if w.staticJobHasSectorQueue.callLen() != 0 {
+		t.Fatal("unexpected")
+	}
+}
+
 // TestWaitForResult is a unit test for the worker state's WaitForResults
 // method.
 func TestWaitForResult(t *testing.T) {
@@ -646,3 +724,50 @@ func TestWaitForResult(t *testing.T) {
 		t.Fatal("unexpected", len(result))
 	}
 }
+
+// TestManagedHasSectorReason is a unit test for the worker state's
+// managedHasSectorReason method.
+func TestManagedHasSectorReason(t *testing.T) {
+	t.Parallel()
+
+	// Create a plain worker state.
+	ws := &pcwsWorkerState{
+		unresolvedWorkers: make(map[string]*pcwsUnresolvedWorker),
+	}
+
+	// Create a few workers.
+	newWorker := func(name string) *worker {
+		w := mockWorker(0)
+		w.staticHostPubKeyStr = name
+		return w
+	}
+	unresolved := newWorker("unresolved")
+	failed := newWorker("failed")
+	noPieces := newWorker("nopieces")
+	hasPieces := newWorker("haspieces")
+
+	ws.unresolvedWorkers[unresolved.staticHostPubKeyStr] = &pcwsUnresolvedWorker{staticWorker: unresolved}
+	ws.resolvedWorkers = []*pcwsWorkerResponse{
+		{worker: failed, err: errors.New("test")},
+		{worker: noPieces},
+		{worker: hasPieces, pieceIndices: []uint64{0}},
+	}
+
+	tests := []struct {
+		name     string
+		reason   string
+		resolved bool
+	}{
+		{"unresolved", "", false},
+		{"failed", "HasSector query failed: test", true},
+		{"nopieces", "host doesn't have any of the pieces", true},
+		{"haspieces", "", true},
+		{"unknown", "host wasn't queried for the pieces, it is either price gouging or its HasSector queue is unavailable", false},
+	}
+	for _, test := range tests {
+		reason, resolved := ws.managedHasSectorReason(test.name)
+		if reason != test.reason || resolved != test.resolved {
+			t.Errorf("%v: unexpected result %q %v", test.name, reason, resolved)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"container/heap"
 	"context"
-	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -79,6 +78,11 @@ type (
 		// favor the faster and more expensive worker set.
 		pricePerMS types.Currency
 
+		// staticVariancePenalty is the factor with which the standard
+		// deviation of a worker's read durations is penalized when building
+		// the initial worker set. Zero disables the penalty.
+		staticVariancePenalty float64
+
 		// availablePieces are pieces that resolved workers think they can
 		// fetch.
 		//
@@ -110,10 +114,12 @@ type (
 		workerSet            *projectChunkWorkerSet
 		workerState          *pcwsWorkerState
 
-		// Debug helpers
-		uid             [8]byte
-		launchTime      time.Time
-		launchedWorkers []*launchedWorkerInfo
+		// Debug helpers. The download id is shared by all chunks of a
+		// download while the uid identifies the chunk.
+		staticDownloadID downloadID
+		uid              [8]byte
+		launchTime       time.Time
+		launchedWorkers  []*launchedWorkerInfo
 	}
 
 	// launchedWorkerInfo tracks information about the worker that has been
@@ -181,7 +187,7 @@ type (
 
 // String implements the String interface.
 func (lwi *launchedWorkerInfo) String() string {
-	pdcId := hex.EncodeToString(lwi.staticPDC.uid[:])
+	pdcId := lwi.staticPDC.traceID()
 	hostKey := lwi.staticWorker.staticHostPubKey.ShortString()
 	estimate := lwi.staticExpectedDuration.Milliseconds()
 
@@ -238,7 +244,7 @@ func (pdc *projectDownloadChunk) updateWorkerHeap(h *pdcWorkerHeap) {
 		// Check if the worker is resolved.
 		_, unresolved := ws.unresolvedWorkers[w.worker.staticHostPubKeyStr]
 		jrq := w.worker.callReadQueue(pdc.staticIsLowPrio)
-		cost := jrq.callExpectedJobCost(pdc.pieceLength)
+		cost := w.expectedCost(pdc.pieceLength)
 		readDuration := jrq.staticStats.callExpectedJobTime(pdc.pieceLength)
 
 		if unresolved {
@@ -280,6 +286,8 @@ func (pdc *projectDownloadChunk) updateWorkerHeap(h *pdcWorkerHeap) {
 		w.readDuration = readDuration
 		w.unresolved = unresolved
 		w.cost = cost
+		w.variancePenalty = pdc.variancePenalty(jrq)
+		w.failurePenalty = pdc.failurePenalty(jrq, readDuration)
 	}
 
 	// Reestablish heap invariants.
@@ -339,7 +347,7 @@ func (pdc *projectDownloadChunk) managedUnresolvedWorkers() ([]*pcwsUnresolvedWo
 func (pdc *projectDownloadChunk) handleJobReadResponse(jrr *jobReadResponse) {
 	// Prevent a production panic.
 	if jrr == nil {
-		pdc.workerSet.staticRenter.staticLog.Critical("received nil job read response in handleJobReadResponse")
+		pdc.workerSet.staticRenter.staticLog.Critical(pdc.traceID(), "received nil job read response in handleJobReadResponse")
 		return
 	}
 
@@ -358,6 +366,8 @@ func (pdc *projectDownloadChunk) handleJobReadResponse(jrr *jobReadResponse) {
 
 	// Check whether the job failed.
 	if jrr.staticErr != nil {
+		pdc.debugf("piece %v download from worker %v failed after %v: %v", pieceIndex, worker.staticHostPubKey.ShortString(), launchedWorker.totalDuration, jrr.staticErr)
+
 		// The download failed, update the pdc available pieces to reflect the
 		// failure.
 		pieceFound := false
@@ -378,9 +388,10 @@ func (pdc *projectDownloadChunk) handleJobReadResponse(jrr *jobReadResponse) {
 	key := pdc.workerSet.staticMasterKey.Derive(pdc.workerSet.staticChunkIndex, uint64(pieceIndex))
 	_, err := key.DecryptBytesInPlace(jrr.staticData, pdc.pieceOffset/crypto.SegmentSize)
 	if err != nil {
-		pdc.workerSet.staticRenter.staticLog.Println("decryption of a piece failed")
+		pdc.printf("decryption of piece %v from worker %v failed: %v", pieceIndex, worker.staticHostPubKey.ShortString(), err)
 		return
 	}
+	pdc.debugf("piece %v downloaded from worker %v after %v", pieceIndex, worker.staticHostPubKey.ShortString(), launchedWorker.totalDuration)
 
 	// The download succeeded, add the piece to the appropriate index.
 	pdc.dataPieces[pieceIndex] = jrr.staticData
@@ -400,6 +411,8 @@ func (pdc *projectDownloadChunk) handleJobReadResponse(jrr *jobReadResponse) {
 
 // fail will send an error down the download response channel.
 func (pdc *projectDownloadChunk) fail(err error) {
+	pdc.debugf("download failed after %v: %v", time.Since(pdc.launchTime), err)
+
 	// Log info and finish span.
 	if span := opentracing.SpanFromContext(pdc.ctx); span != nil {
 		span.LogKV("error", err)
@@ -552,6 +565,7 @@ func (pdc *projectDownloadChunk) launchWorker(w *worker, pieceIndex uint64, isOv
 			"overdriveWorker", isOverdrive,
 		)
 	}
+	pdc.debugf("launching worker %v for piece %v, overdrive %v", w.staticHostPubKey.ShortString(), pieceIndex, isOverdrive)
 
 	// Create the read job metadata.
 	launchedWorkerIndex := uint64(len(pdc.launchedWorkers))
@@ -49,6 +49,10 @@ func TestProjectDownloadChunk_finalize(t *testing.T) {
 	renter := new(Renter)
 	renter.staticBaseSectorDownloadStats = skymodules.NewSectorDownloadStats()
 	renter.staticFanoutSectorDownloadStats = skymodules.NewSectorDownloadStats()
+	renter.staticLog, err = persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// create PCWS manually
 	pcws := &projectChunkWorkerSet{
@@ -150,6 +154,10 @@ func TestProjectDownloadChunk_finished(t *testing.T) {
 	renter := new(Renter)
 	renter.staticBaseSectorDownloadStats = skymodules.NewSectorDownloadStats()
 	renter.staticFanoutSectorDownloadStats = skymodules.NewSectorDownloadStats()
+	renter.staticLog, err = persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// create PCWS manually
 	pcws := &projectChunkWorkerSet{
@@ -276,6 +284,7 @@ func TestProjectDownloadChunk_handleJobResponse(t *testing.T) {
 	pcws.staticRenter = renter
 
 	pdc := new(projectDownloadChunk)
+	pdc.ctx = context.Background()
 	pdc.workerSet = pcws
 	pdc.workerSet.staticChunkIndex = 0
 	pdc.dataPieces = make([][]byte, ec.NumPieces())
@@ -388,6 +397,11 @@ func TestProjectDownloadChunk_launchWorker(t *testing.T) {
 	// mock a pcws
 	pcws := new(projectChunkWorkerSet)
 	pcws.staticPieceRoots = make([]crypto.Hash, ec.NumPieces())
+	logger, err := persist.NewLogger(ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcws.staticRenter = &Renter{staticLog: logger}
 
 	// mock a pdc, ensure available pieces is not nil
 	pdc := new(projectDownloadChunk)
@@ -118,6 +118,27 @@ type pdcInitialWorker struct {
 	readDuration              time.Duration
 	staticExpectedResolveTime time.Time
 
+	// costPriceTable is the price table the cost was computed with. The cost
+	// only depends on the price table and the piece length, which is fixed for
+	// the pdc, so it is only recomputed once the worker's price table changes.
+	costPriceTable *workerPriceTable
+
+	// variancePenalty is added to the cost when comparing workers to prefer
+	// workers with predictable read durations. See
+	// pdc.variancePenalty.
+	variancePenalty types.Currency
+
+	// failurePenalty is added to the cost when comparing workers to prefer
+	// workers that rarely fail their read jobs. See pdc.failurePenalty.
+	failurePenalty types.Currency
+
+	// availabilityRate is the chance that an unresolved worker has the sector
+	// according to its past HasSector jobs. It is only set when estimating a
+	// download, since the unresolved workers of an estimate never resolve, and
+	// the worker's adjusted cost is weighted by it. Zero means the worker isn't
+	// weighted.
+	availabilityRate float64
+
 	// The list of pieces indicates which pieces the worker is capable of
 	// fetching. If 'unresolved' is set to true, the worker will be treated as
 	// though it can fetch the first 'MinPieces' pieces.
@@ -126,6 +147,36 @@ type pdcInitialWorker struct {
 	worker     *worker
 }
 
+// pdcWorkerForecast is used to forecast when a worker of the initial set of
+// workers completes its read job. The worker's read distribution is a clone,
+// so it can be used without holding any locks.
+type pdcWorkerForecast struct {
+	staticDistribution *skymodules.Distribution
+	staticReadDuration time.Duration
+	staticWait         time.Duration
+}
+
+// expectedCost returns the cost of fetching a piece of the given length from
+// the worker. The result is cached until the worker's price table is updated.
+func (iw *pdcInitialWorker) expectedCost(length uint64) types.Currency {
+	wpt := iw.worker.staticPriceTable()
+	if iw.costPriceTable != wpt {
+		iw.cost = expectedReadJobCost(&wpt.staticPriceTable, length)
+		iw.costPriceTable = wpt
+	}
+	return iw.cost
+}
+
+// adjustedCost returns the cost of the worker plus its variance and failure
+// penalties, divided by its availability rate if it has one.
+func (iw *pdcInitialWorker) adjustedCost() types.Currency {
+	cost := iw.cost.Add(iw.variancePenalty).Add(iw.failurePenalty)
+	if iw.availabilityRate > 0 && iw.availabilityRate < 1 {
+		cost = cost.MulFloat(1 / iw.availabilityRate)
+	}
+	return cost
+}
+
 // A heap of pdcInitialWorkers that is sorted by 'completeTime'. Workers that
 // have a sooner/earlier complete time will be popped off of the heap first.
 type pdcWorkerHeap []*pdcInitialWorker
@@ -155,31 +206,9 @@ func (pdc *projectDownloadChunk) initialWorkerHeap(unresolvedWorkers []*pcwsUnre
 	// Add all of the unresolved workers to the heap.
 	var workerHeap pdcWorkerHeap
 	for _, uw := range unresolvedWorkers {
-		// Ignore workers that are on a maintenance cooldown. Good performing
-		// workers are generally never on maintenance cooldown, so by skipping
-		// them here we avoid ever waiting for them to resolve.
+		// Ignore workers that can't be used for the download.
 		w := uw.staticWorker
-		if w.managedOnMaintenanceCooldown() {
-			continue
-		}
-		// Ignore workers that are considered to be price gouging.
-		pt := w.staticPriceTable().staticPriceTable
-		allowance := w.staticCache().staticRenterAllowance
-		err := checkProjectDownloadGouging(pt, allowance)
-		if err != nil {
-			continue
-		}
-
-		// Verify whether the read queue is on a cooldown, if so skip this
-		// worker. Also skip if the worker is not async ready.
-		jrq := w.callReadQueue(pdc.staticIsLowPrio)
-		if !w.managedAsyncReady() || jrq.callOnCooldown() {
-			continue
-		}
-
-		// Ignore worker with 0 read duration.
-		readDuration := jrq.staticStats.callExpectedJobTime(pdc.pieceLength)
-		if readDuration == 0 {
+		if pdc.checkInitialWorker(w, false) != nil {
 			continue
 		}
 
@@ -199,8 +228,6 @@ func (pdc *projectDownloadChunk) initialWorkerHeap(unresolvedWorkers []*pcwsUnre
 	for _, piece := range pdc.availablePieces {
 		for _, pieceDownload := range piece {
 			w := pieceDownload.worker
-			pt := w.staticPriceTable().staticPriceTable
-			allowance := w.staticCache().staticRenterAllowance
 
 			// Add each worker only once.
 			_, exists := resolvedWorkersMap[w.staticHostPubKeyStr]
@@ -209,16 +236,8 @@ func (pdc *projectDownloadChunk) initialWorkerHeap(unresolvedWorkers []*pcwsUnre
 			}
 			resolvedWorkersMap[w.staticHostPubKeyStr] = struct{}{}
 
-			// Ignore this worker if its host is considered to be price gouging.
-			err := checkProjectDownloadGouging(pt, allowance)
-			if err != nil {
-				continue
-			}
-
-			// Ignore this worker if the worker is not currently equipped to
-			// perform async work, or if the read queue is on a cooldown.
-			jrq := w.callReadQueue(pdc.staticIsLowPrio)
-			if !w.managedAsyncReady() || jrq.callOnCooldown() {
+			// Ignore workers that can't be used for the download.
+			if pdc.checkInitialWorker(w, true) != nil {
 				continue
 			}
 
@@ -236,6 +255,146 @@ func (pdc *projectDownloadChunk) initialWorkerHeap(unresolvedWorkers []*pcwsUnre
 	return workerHeap
 }
 
+// checkInitialWorker returns an error describing why the given worker can't be
+// added to the initial worker heap, or nil if it can be added. Unresolved
+// workers are also excluded when they are on a maintenance cooldown or don't
+// have a read duration yet. Good performing workers are generally never on
+// maintenance cooldown, so by skipping them we avoid ever waiting for them to
+// resolve.
+func (pdc *projectDownloadChunk) checkInitialWorker(w *worker, resolved bool) error {
+	if !resolved && w.managedOnMaintenanceCooldown() {
+		return errors.New("worker is on a maintenance cooldown")
+	}
+
+	// Ignore workers that are considered to be price gouging.
+	pt := w.staticPriceTable().staticPriceTable
+	allowance := w.staticCache().staticRenterAllowance
+	err := checkProjectDownloadGouging(pt, allowance)
+	if err != nil {
+		return errors.AddContext(err, "host is price gouging")
+	}
+
+	// Ignore workers that are not currently equipped to perform async work,
+	// or whose read queue is on a cooldown.
+	if !w.managedAsyncReady() {
+		return errors.New("worker is not ready to perform async jobs")
+	}
+	jrq := w.callReadQueue(pdc.staticIsLowPrio)
+	if jrq.callOnCooldown() {
+		return errors.New("worker's read queue is on a cooldown")
+	}
+
+	// Ignore unresolved workers with 0 read duration.
+	if !resolved && jrq.staticStats.callExpectedJobTime(pdc.pieceLength) == 0 {
+		return errors.New("worker has no expected read duration")
+	}
+	return nil
+}
+
+// variancePenalty returns the cost that is added to a worker's cost to penalize
+// unpredictable read durations. It is the standard deviation of the worker's
+// read durations in milliseconds, multiplied by the configured penalty and
+// converted to a cost using the pdc's price per millisecond. Without a
+// configured penalty it is zero.
+func (pdc *projectDownloadChunk) variancePenalty(jrq *jobReadQueue) types.Currency {
+	if pdc.staticVariancePenalty <= 0 {
+		return types.ZeroCurrency
+	}
+	stdDevMS := float64(jrq.staticStats.callStandardDeviation()) / float64(time.Millisecond)
+	return pdc.pricePerMS.MulFloat(pdc.staticVariancePenalty * stdDevMS)
+}
+
+// failurePenalty returns the cost that is added to a worker's cost to penalize
+// failing read jobs. It is the read duration in milliseconds that is expected
+// to be lost on a failed read, converted to a cost using the pdc's price per
+// millisecond. Failing workers are therefore only picked if there are no
+// better workers, which still gives them a chance to recover their success
+// rate.
+func (pdc *projectDownloadChunk) failurePenalty(jrq *jobReadQueue, readDuration time.Duration) types.Currency {
+	failureRate := 1 - jrq.staticStats.callSuccessRate()
+	if failureRate <= 0 {
+		return types.ZeroCurrency
+	}
+	readDurationMS := float64(readDuration) / float64(time.Millisecond)
+	return pdc.pricePerMS.MulFloat(failureRate * readDurationMS)
+}
+
+// downloadVariancePenalty returns the configured variance penalty for
+// downloads. By default there is no penalty.
+func downloadVariancePenalty() float64 {
+	penalty, ok := build.DownloadVariancePenalty()
+	if !ok || penalty < 0 {
+		return 0
+	}
+	return penalty
+}
+
+// whyNotSelected returns a human-readable reason why the given worker isn't
+// part of the best initial worker set of the pdc. If it is part of the set, an
+// empty string is returned.
+func (pdc *projectDownloadChunk) whyNotSelected(w *worker) string {
+	unresolvedWorkers, _ := pdc.managedUnresolvedWorkers()
+
+	// Check whether the worker can download any pieces.
+	reason, resolved := pdc.workerState.managedHasSectorReason(w.staticHostPubKeyStr)
+	if reason != "" {
+		return reason
+	}
+
+	// Check whether the worker would be added to the worker heap.
+	if err := pdc.checkInitialWorker(w, resolved); err != nil {
+		return err.Error()
+	}
+
+	// Check whether the worker is part of the best set.
+	workerHeap := pdc.initialWorkerHeap(unresolvedWorkers)
+	bestSet, err := pdc.bestInitialWorkerSet(workerHeap)
+	if err != nil {
+		return fmt.Sprintf("unable to build a set of workers for the download: %v", err)
+	}
+	for _, iw := range bestSet {
+		if iw != nil && iw.worker == w {
+			return ""
+		}
+	}
+	return "other workers are expected to complete the download faster or at a lower cost"
+}
+
+// recoverablePieces returns the set of pieces that the resolved workers in the
+// heap can download and the number of unresolved workers in the heap.
+func (wh pdcWorkerHeap) recoverablePieces() (map[uint64]struct{}, int) {
+	pieces := make(map[uint64]struct{})
+	var unresolved int
+	for _, w := range wh {
+		if w.unresolved {
+			unresolved++
+			continue
+		}
+		for _, piece := range w.pieces {
+			pieces[piece] = struct{}{}
+		}
+	}
+	return pieces, unresolved
+}
+
+// checkRecoverablePieces returns an error if the workers in the heap can't
+// recover enough pieces to complete the download. Every unresolved worker is
+// expected to contribute at most one piece, since uploads never store more
+// than one piece of a chunk on the same host. This allows for failing the
+// download as soon as it is clear that not enough pieces are available instead
+// of waiting for the remaining workers to resolve.
+func (pdc *projectDownloadChunk) checkRecoverablePieces(workerHeap pdcWorkerHeap) error {
+	minPieces := pdc.workerSet.staticErasureCoder.MinPieces()
+	if len(workerHeap) == 0 {
+		return errors.AddContext(errNotEnoughWorkers, fmt.Sprintf("no usable workers < %v", minPieces))
+	}
+	pieces, unresolved := workerHeap.recoverablePieces()
+	if len(pieces)+unresolved < minPieces {
+		return errors.AddContext(errNotEnoughPieces, fmt.Sprintf("%v recoverable pieces and %v unresolved workers < %v", len(pieces), unresolved, minPieces))
+	}
+	return nil
+}
+
 // createInitialWorkerSet will go through the current set of workers and
 // determine the best set of workers to use when attempting to download a piece.
 // Note that we only return this best set if all workers from the worker set are
@@ -252,6 +411,24 @@ func (pdc *projectDownloadChunk) initialWorkerHeap(unresolvedWorkers []*pcwsUnre
 // applied which will eventually break those workers and revert to preferring
 // the already resolved workers.
 func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap) ([]*pdcInitialWorker, error) {
+	bestSet, err := pdc.bestInitialWorkerSet(workerHeap)
+	if err != nil {
+		return nil, err
+	}
+
+	// If some of the workers in the best set are yet unresolved, return nil.
+	for _, piece := range bestSet {
+		if piece != nil && piece.unresolved {
+			return nil, nil
+		}
+	}
+	return bestSet, nil
+}
+
+// bestInitialWorkerSet builds the best set of workers to use when attempting
+// to download a piece. Contrary to createInitialWorkerSet, the set is returned
+// even if it contains unresolved workers.
+func (pdc *projectDownloadChunk) bestInitialWorkerSet(workerHeap pdcWorkerHeap) ([]*pdcInitialWorker, error) {
 	// Convenience variable.
 	ec := pdc.workerSet.staticErasureCoder
 	gs := types.NewCurrency(new(big.Int).Exp(big.NewInt(10), big.NewInt(33), nil)) // 1GS
@@ -261,7 +438,8 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 	// the amount of time it will take the current working set to return.
 	//
 	// The total adjusted cost of a set is the cost of launching each of its
-	// individual workers, plus a single adjustment for the duration of the set.
+	// individual workers, including their variance penalties, plus a single
+	// adjustment for the duration of the set.
 	// The duration of the set is the longest of any duration of its individual
 	// workers.
 	//
@@ -306,8 +484,8 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 			if workingSet[i] == nil {
 				continue
 			}
-			if workingSet[i].cost.Cmp(highestCost) > 0 {
-				highestCost = workingSet[i].cost
+			if workingSet[i].adjustedCost().Cmp(highestCost) > 0 {
+				highestCost = workingSet[i].adjustedCost()
 				highestCostIndex = i
 			}
 			totalWorkers++
@@ -316,7 +494,7 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 		// Consistency check: we should never have more than MinPieces workers
 		// assigned.
 		if totalWorkers > ec.MinPieces() {
-			pdc.workerSet.staticRenter.staticLog.Critical("total workers mistake in download code", totalWorkers, ec.MinPieces())
+			pdc.workerSet.staticRenter.staticLog.Critical(pdc.traceID(), "total workers mistake in download code", totalWorkers, ec.MinPieces())
 		}
 		enoughWorkers := totalWorkers == ec.MinPieces()
 
@@ -330,7 +508,7 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 
 		// If all workers in the working set are already cheaper than this
 		// worker, skip this worker.
-		if highestCost.Cmp(nextWorker.cost) <= 0 && enoughWorkers {
+		if highestCost.Cmp(nextWorker.adjustedCost()) <= 0 && enoughWorkers {
 			continue
 		}
 
@@ -342,7 +520,7 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 		// expensive worker in the whole working set.
 		workerUseful := false
 		bestSpotEmpty := false
-		bestSpotCost := nextWorker.cost // this will cause the loop to ignore workers that are already better than nextWorker
+		bestSpotCost := nextWorker.adjustedCost() // this will cause the loop to ignore workers that are already better than nextWorker
 		bestSpotIndex := uint64(0)
 		bestSpotPiecePos := 0
 		for i, index := range nextWorker.pieces {
@@ -352,9 +530,9 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 				bestSpotPiecePos = i
 				break
 			}
-			if workingSet[index].cost.Cmp(bestSpotCost) > 0 {
+			if workingSet[index].adjustedCost().Cmp(bestSpotCost) > 0 {
 				workerUseful = true
-				bestSpotCost = workingSet[index].cost
+				bestSpotCost = workingSet[index].adjustedCost()
 				bestSpotIndex = index
 				bestSpotPiecePos = i
 			}
@@ -384,7 +562,7 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 		// is another more suitable slot for the evicted worker.
 		newWorker := false // helps determine whether the best set should be made.
 		if bestSpotEmpty {
-			workingSetCost = workingSetCost.Add(nextWorker.cost)
+			workingSetCost = workingSetCost.Add(nextWorker.adjustedCost())
 			workingSet[bestSpotIndex] = nextWorker
 
 			// Only do the eviction if we already have enough workers.
@@ -396,8 +574,8 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 				newWorker = true
 			}
 		} else {
-			workingSetCost = workingSetCost.Add(nextWorker.cost)
-			workingSetCost = workingSetCost.Sub(workingSet[bestSpotIndex].cost)
+			workingSetCost = workingSetCost.Add(nextWorker.adjustedCost())
+			workingSetCost = workingSetCost.Sub(workingSet[bestSpotIndex].adjustedCost())
 			heap.Push(&workerHeap, workingSet[bestSpotIndex])
 			workingSet[bestSpotIndex] = nextWorker
 		}
@@ -435,32 +613,148 @@ func (pdc *projectDownloadChunk) createInitialWorkerSet(workerHeap pdcWorkerHeap
 	}
 
 	// We now have the best set. If the best set does not have enough workers to
-	// complete the download, return an error. If the best set has enough
-	// workers to complete the download but some of the workers in the best set
-	// are yet unresolved, return the updateChan and everything else is nil, if
-	// the best set is done and all of the workers in the best set are resolved,
-	// return the best set and everything else is nil.
+	// complete the download, return an error.
 	totalPieces := 0
-	isUnresolved := false
 	for _, piece := range bestSet {
-		if piece == nil {
-			continue
+		if piece != nil {
+			totalPieces++
 		}
-		totalPieces++
-		isUnresolved = isUnresolved || piece.unresolved
 	}
-
 	if totalPieces < ec.MinPieces() {
 		return nil, errors.AddContext(errNotEnoughWorkers, fmt.Sprintf("%v < %v", totalPieces, ec.MinPieces()))
 	}
+	return bestSet, nil
+}
+
+// EstimateCost returns the expected cost and duration of the download without
+// launching any jobs. The estimate is based on the same set of workers that
+// launchInitialWorkers would pick given the pdc's pricePerMS. Unresolved
+// workers are included in the estimate using their expected resolve time and
+// weighted by their availability rate.
+func (pdc *projectDownloadChunk) EstimateCost() (types.Currency, time.Duration, error) {
+	bestSet, err := pdc.estimateInitialWorkerSet()
+	if err != nil {
+		return types.ZeroCurrency, 0, err
+	}
+	cost, duration := estimateWorkerSetCost(bestSet, time.Now())
+	return cost, duration, nil
+}
 
-	if isUnresolved {
-		return nil, nil
+// Forecast returns the expected completion times of the download without
+// launching any jobs. The forecast combines the read distributions of the same
+// set of workers that launchInitialWorkers would pick.
+func (pdc *projectDownloadChunk) Forecast() (skymodules.DownloadForecast, error) {
+	bestSet, err := pdc.estimateInitialWorkerSet()
+	if err != nil {
+		return skymodules.DownloadForecast{}, err
 	}
+	return newDownloadForecast(pdc.workerForecasts(bestSet, time.Now())), nil
+}
 
+// estimateInitialWorkerSet returns the best set of workers for the download,
+// including workers that are still unresolved. The heap treats unresolved
+// workers as though they have every piece, so they are weighted by the rate at
+// which their past HasSector jobs found the sector. That way, workers that are
+// unlikely to have the sector are only picked if there are no better ones.
+func (pdc *projectDownloadChunk) estimateInitialWorkerSet() ([]*pdcInitialWorker, error) {
+	unresolvedWorkers, _ := pdc.managedUnresolvedWorkers()
+	workerHeap := pdc.initialWorkerHeap(unresolvedWorkers)
+	numPieces := pdc.workerSet.staticErasureCoder.NumPieces()
+	for _, w := range workerHeap {
+		if w.unresolved {
+			w.availabilityRate = w.worker.staticJobHasSectorQueue.callAvailabilityRate(numPieces)
+		}
+	}
+	bestSet, err := pdc.bestInitialWorkerSet(workerHeap)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to build initial set of workers")
+	}
 	return bestSet, nil
 }
 
+// workerForecasts returns the forecasts of the workers in the given set. A
+// worker's read distribution is delayed by the time the worker is expected to
+// wait before its read job starts, relative to the given time.
+func (pdc *projectDownloadChunk) workerForecasts(set []*pdcInitialWorker, now time.Time) []pdcWorkerForecast {
+	var forecasts []pdcWorkerForecast
+	for _, w := range set {
+		if w == nil {
+			continue
+		}
+		wait := w.completeTime.Sub(now) - w.readDuration
+		if wait < 0 {
+			wait = 0
+		}
+		jrq := w.worker.callReadQueue(pdc.staticIsLowPrio)
+		forecasts = append(forecasts, pdcWorkerForecast{
+			staticDistribution: jrq.staticStats.callDistribution(),
+			staticReadDuration: w.readDuration,
+			staticWait:         wait,
+		})
+	}
+	return forecasts
+}
+
+// chanceCompleted returns the chance that the worker completed its read job
+// within the given duration. Workers without any read history are expected to
+// complete exactly after their expected read duration.
+func (wf pdcWorkerForecast) chanceCompleted(dur time.Duration) float64 {
+	if dur < wf.staticWait {
+		return 0
+	}
+	dur -= wf.staticWait
+	if wf.staticDistribution == nil || wf.staticDistribution.DataPoints() == 0 {
+		if dur < wf.staticReadDuration {
+			return 0
+		}
+		return 1
+	}
+	return wf.staticDistribution.ChanceAfter(dur)
+}
+
+// newDownloadForecast returns the percentiles of the completion time of a
+// download that requires all of the given workers to complete their read
+// jobs. The percentiles are rounded up to the buckets of a distribution.
+func newDownloadForecast(forecasts []pdcWorkerForecast) skymodules.DownloadForecast {
+	percentile := func(p float64) time.Duration {
+		var dur time.Duration
+		for i := 0; i < skymodules.DistributionTrackerTotalBuckets; i++ {
+			dur = skymodules.DistributionDurationForBucketIndex(i)
+			chance := 1.0
+			for _, wf := range forecasts {
+				chance *= wf.chanceCompleted(dur)
+			}
+			if chance >= p {
+				break
+			}
+		}
+		return dur
+	}
+	return skymodules.DownloadForecast{
+		P50: percentile(.5),
+		P90: percentile(.9),
+		P99: percentile(.99),
+	}
+}
+
+// estimateWorkerSetCost returns the total cost of the given worker set and the
+// expected duration until the slowest worker in the set completes, relative to
+// the given time.
+func estimateWorkerSetCost(set []*pdcInitialWorker, now time.Time) (types.Currency, time.Duration) {
+	var cost types.Currency
+	var duration time.Duration
+	for _, w := range set {
+		if w == nil {
+			continue
+		}
+		cost = cost.Add(w.cost)
+		if d := w.completeTime.Sub(now); d > duration {
+			duration = d
+		}
+	}
+	return cost, duration
+}
+
 // launchInitialWorkers will pick the initial set of workers that needs to be
 // launched and then launch them. This is a non-blocking function that returns
 // once jobs have been scheduled for MinPieces workers.
@@ -475,6 +769,11 @@ func (pdc *projectDownloadChunk) launchInitialWorkers() error {
 	workerHeap := pdc.initialWorkerHeap(unresolvedWorkers)
 
 	for {
+		// Make sure that enough pieces can be recovered.
+		if err := pdc.checkRecoverablePieces(workerHeap); err != nil {
+			return err
+		}
+
 		// Create an initial worker set
 		workerHeapCopy := append([]*pdcInitialWorker{}, workerHeap...)
 		finalWorkers, err := pdc.createInitialWorkerSet(workerHeapCopy)
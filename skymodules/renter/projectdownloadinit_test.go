@@ -336,6 +336,439 @@ func TestProjectDownloadChunk_createInitialWorkerSet(t *testing.T) {
 	}
 }
 
+// TestProjectDownloadChunk_bestInitialWorkerSet verifies the best set is
+// returned even if it contains unresolved workers, and that its estimated cost
+// and duration are computed correctly.
+func TestProjectDownloadChunk_bestInitialWorkerSet(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pS := types.SiacoinPrecision.MulFloat(1e-12)
+
+	// create two workers, one of which is unresolved
+	w1 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w1"},
+		completeTime: now.Add(50 * time.Millisecond),
+		readDuration: 50 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(10),
+	}
+	w2 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w2"},
+		completeTime: now.Add(100 * time.Millisecond),
+		readDuration: 100 * time.Millisecond,
+		pieces:       []uint64{1},
+		cost:         pS.Mul64(20),
+		unresolved:   true,
+	}
+	workersToHeap := func() pdcWorkerHeap {
+		wh := new(pdcWorkerHeap)
+		heap.Push(wh, w1)
+		heap.Push(wh, w2)
+		return *wh
+	}
+
+	// create an erasure coder that requires both workers
+	ec, err := skymodules.NewRSSubCode(2, 4, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mock a pdc
+	pdc := new(projectDownloadChunk)
+	pdc.workerSet = &projectChunkWorkerSet{staticErasureCoder: ec}
+	pdc.pricePerMS = pS
+
+	// the initial worker set can't be launched yet
+	iws, err := pdc.createInitialWorkerSet(workersToHeap())
+	if err != nil || iws != nil {
+		t.Fatal("unexpected", iws, err)
+	}
+
+	// the best set should be returned regardless
+	bestSet, err := pdc.bestInitialWorkerSet(workersToHeap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, duration := estimateWorkerSetCost(bestSet, now)
+	if !cost.Equals(pS.Mul64(30)) {
+		t.Fatal("unexpected cost", cost)
+	}
+	if duration != 100*time.Millisecond {
+		t.Fatal("unexpected duration", duration)
+	}
+
+	// with a worker missing, there are not enough workers
+	wh := new(pdcWorkerHeap)
+	heap.Push(wh, w1)
+	_, err = pdc.bestInitialWorkerSet(*wh)
+	if !errors.Contains(err, errNotEnoughWorkers) {
+		t.Fatal("unexpected", err)
+	}
+}
+
+// TestProjectDownloadChunk_variancePenalty verifies that the variance penalty
+// is computed from the worker's read durations and that it is taken into
+// account when building the best initial worker set.
+func TestProjectDownloadChunk_variancePenalty(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pS := types.SiacoinPrecision.MulFloat(1e-12)
+
+	// mock a pdc
+	ec, err := skymodules.NewRSSubCode(1, 2, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdc := new(projectDownloadChunk)
+	pdc.workerSet = &projectChunkWorkerSet{staticErasureCoder: ec}
+	pdc.pricePerMS = pS
+
+	// create a read queue with a standard deviation of 4ms
+	w := new(worker)
+	w.initJobReadQueue(&jobReadStats{})
+	jrq := w.staticJobReadQueue
+	jrq.staticStats.callUpdateJobTimeMetrics(1<<16, 8*time.Millisecond)
+	jrq.staticStats.callUpdateJobTimeMetrics(1<<16, 16*time.Millisecond)
+
+	// without a penalty there is no cost
+	if !pdc.variancePenalty(jrq).IsZero() {
+		t.Fatal("unexpected", pdc.variancePenalty(jrq))
+	}
+	pdc.staticVariancePenalty = 2
+	if penalty := pdc.variancePenalty(jrq); !penalty.Equals(pS.Mul64(8)) {
+		t.Fatal("unexpected", penalty)
+	}
+
+	// create a fast and cheap but unpredictable worker and a slower, more
+	// expensive but predictable one
+	w1 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w1"},
+		completeTime: now.Add(50 * time.Millisecond),
+		readDuration: 50 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(10),
+	}
+	w2 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w2"},
+		completeTime: now.Add(60 * time.Millisecond),
+		readDuration: 60 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(20),
+	}
+	bestWorker := func() string {
+		t.Helper()
+		wh := new(pdcWorkerHeap)
+		heap.Push(wh, w1)
+		heap.Push(wh, w2)
+		bestSet, err := pdc.bestInitialWorkerSet(*wh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bestSet[0].worker.staticHostPubKeyStr
+	}
+
+	// without a penalty, the fast worker is picked
+	if best := bestWorker(); best != "w1" {
+		t.Fatal("unexpected", best)
+	}
+
+	// with a penalty, the predictable worker is picked
+	w1.variancePenalty = pS.Mul64(100)
+	if best := bestWorker(); best != "w2" {
+		t.Fatal("unexpected", best)
+	}
+}
+
+// TestProjectDownloadChunk_availabilityRate verifies that unresolved workers
+// with an availability rate are weighted by it when building the best initial
+// worker set.
+func TestProjectDownloadChunk_availabilityRate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pS := types.SiacoinPrecision.MulFloat(1e-12)
+
+	// mock a pdc
+	ec, err := skymodules.NewRSSubCode(1, 2, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdc := new(projectDownloadChunk)
+	pdc.workerSet = &projectChunkWorkerSet{staticErasureCoder: ec}
+	pdc.pricePerMS = pS
+
+	// create a fast and cheap unresolved worker and a slower, more expensive
+	// one
+	w1 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w1"},
+		completeTime: now.Add(50 * time.Millisecond),
+		readDuration: 50 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(10),
+		unresolved:   true,
+	}
+	w2 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w2"},
+		completeTime: now.Add(60 * time.Millisecond),
+		readDuration: 60 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(20),
+	}
+	bestWorker := func() string {
+		t.Helper()
+		wh := new(pdcWorkerHeap)
+		heap.Push(wh, w1)
+		heap.Push(wh, w2)
+		bestSet, err := pdc.bestInitialWorkerSet(*wh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bestSet[0].worker.staticHostPubKeyStr
+	}
+
+	// without an availability rate, the fast worker is picked
+	if best := bestWorker(); best != "w1" {
+		t.Fatal("unexpected", best)
+	}
+
+	// a worker that always had the sector isn't weighted
+	w1.availabilityRate = 1
+	if cost := w1.adjustedCost(); !cost.Equals(pS.Mul64(10)) {
+		t.Fatal("unexpected", cost)
+	}
+
+	// a worker that rarely had the sector is only picked if there is no
+	// better worker
+	w1.availabilityRate = 0.01
+	if cost := w1.adjustedCost(); !cost.Equals(pS.Mul64(1000)) {
+		t.Fatal("unexpected", cost)
+	}
+	if best := bestWorker(); best != "w2" {
+		t.Fatal("unexpected", best)
+	}
+}
+
+// TestProjectDownloadChunk_failurePenalty verifies that the failure penalty is
+// computed from the worker's read success rate and that failing workers are
+// only picked if no better worker is available.
+func TestProjectDownloadChunk_failurePenalty(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pS := types.SiacoinPrecision.MulFloat(1e-12)
+
+	// mock a pdc
+	ec, err := skymodules.NewRSSubCode(1, 2, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdc := new(projectDownloadChunk)
+	pdc.workerSet = &projectChunkWorkerSet{staticErasureCoder: ec}
+	pdc.pricePerMS = pS
+
+	// a worker without failures has no penalty
+	w := new(worker)
+	w.initJobReadQueue(&jobReadStats{})
+	jrq := w.staticJobReadQueue
+	if penalty := pdc.failurePenalty(jrq, 100*time.Millisecond); !penalty.IsZero() {
+		t.Fatal("unexpected", penalty)
+	}
+
+	// a worker that fails half of its jobs loses half of its read duration
+	for i := 0; i < 1000; i++ {
+		jrq.staticStats.callUpdateSuccessRate(i%2 == 0)
+	}
+	penalty := pdc.failurePenalty(jrq, 100*time.Millisecond)
+	if penalty.Cmp(pS.Mul64(45)) < 0 || penalty.Cmp(pS.Mul64(55)) > 0 {
+		t.Fatal("unexpected", penalty)
+	}
+
+	// create a fast but failing worker and a slower, reliable one
+	w1 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w1"},
+		completeTime: now.Add(50 * time.Millisecond),
+		readDuration: 50 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(10),
+	}
+	w2 := &pdcInitialWorker{
+		worker:       &worker{staticHostPubKeyStr: "w2"},
+		completeTime: now.Add(60 * time.Millisecond),
+		readDuration: 60 * time.Millisecond,
+		pieces:       []uint64{0},
+		cost:         pS.Mul64(20),
+	}
+	bestWorker := func(workers ...*pdcInitialWorker) string {
+		t.Helper()
+		wh := new(pdcWorkerHeap)
+		for _, w := range workers {
+			heap.Push(wh, w)
+		}
+		bestSet, err := pdc.bestInitialWorkerSet(*wh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bestSet[0].worker.staticHostPubKeyStr
+	}
+
+	// with a penalty, the reliable worker is picked
+	w1.failurePenalty = pS.Mul64(100)
+	if best := bestWorker(w1, w2); best != "w2" {
+		t.Fatal("unexpected", best)
+	}
+
+	// without another worker, the failing worker is still used
+	if best := bestWorker(w1); best != "w1" {
+		t.Fatal("unexpected", best)
+	}
+}
+
+// TestNewDownloadForecast is a unit test for newDownloadForecast.
+func TestNewDownloadForecast(t *testing.T) {
+	t.Parallel()
+
+	// A worker without variance should complete in about 100ms at all
+	// percentiles.
+	d := skymodules.NewDistribution(time.Minute)
+	for i := 0; i < 100; i++ {
+		d.AddDataPoint(100 * time.Millisecond)
+	}
+	fast := pdcWorkerForecast{staticDistribution: d}
+	f := newDownloadForecast([]pdcWorkerForecast{fast})
+	for _, p := range []time.Duration{f.P50, f.P90, f.P99} {
+		if p < 100*time.Millisecond || p > 110*time.Millisecond {
+			t.Fatal("unexpected forecast", f)
+		}
+	}
+
+	// Delay the worker. The forecast should be delayed as well.
+	delayed := fast
+	delayed.staticWait = time.Second
+	f = newDownloadForecast([]pdcWorkerForecast{delayed})
+	if f.P50 < 1100*time.Millisecond || f.P50 > 1200*time.Millisecond {
+		t.Fatal("unexpected forecast", f)
+	}
+
+	// Add a worker without read history. It is expected to complete after
+	// its read duration.
+	unknown := pdcWorkerForecast{staticReadDuration: 500 * time.Millisecond}
+	f = newDownloadForecast([]pdcWorkerForecast{fast, unknown})
+	if f.P50 < 500*time.Millisecond || f.P50 > 550*time.Millisecond {
+		t.Fatal("unexpected forecast", f)
+	}
+
+	// Add a worker that is slow 10% of the time. The higher percentiles
+	// should reflect the slow worker.
+	slow := skymodules.NewDistribution(time.Minute)
+	for i := 0; i < 90; i++ {
+		slow.AddDataPoint(100 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		slow.AddDataPoint(time.Second)
+	}
+	f = newDownloadForecast([]pdcWorkerForecast{fast, {staticDistribution: slow}})
+	if f.P50 > 110*time.Millisecond {
+		t.Fatal("unexpected p50", f)
+	}
+	if f.P99 < time.Second {
+		t.Fatal("unexpected p99", f)
+	}
+	if f.P50 > f.P90 || f.P90 > f.P99 {
+		t.Fatal("percentiles should be increasing", f)
+	}
+}
+
+// TestProjectDownloadChunk_checkRecoverablePieces is a unit test for
+// checkRecoverablePieces.
+func TestProjectDownloadChunk_checkRecoverablePieces(t *testing.T) {
+	t.Parallel()
+
+	// create an erasure coder that requires 3 pieces
+	ec, err := skymodules.NewRSSubCode(3, 6, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdc := new(projectDownloadChunk)
+	pdc.workerSet = &projectChunkWorkerSet{staticErasureCoder: ec}
+
+	resolved := func(pieces ...uint64) *pdcInitialWorker {
+		return &pdcInitialWorker{pieces: pieces}
+	}
+	unresolved := &pdcInitialWorker{
+		pieces:     []uint64{0, 1, 2, 3, 4, 5},
+		unresolved: true,
+	}
+
+	// without any workers, there are not enough workers
+	if err := pdc.checkRecoverablePieces(pdcWorkerHeap{}); !errors.Contains(err, errNotEnoughWorkers) {
+		t.Fatal("unexpected", err)
+	}
+
+	// two workers with the same piece and one with two other pieces
+	wh := pdcWorkerHeap{resolved(0), resolved(0), resolved(1, 2)}
+	pieces, numUnresolved := wh.recoverablePieces()
+	if len(pieces) != 3 || numUnresolved != 0 {
+		t.Fatal("unexpected", pieces, numUnresolved)
+	}
+	if err := pdc.checkRecoverablePieces(wh); err != nil {
+		t.Fatal(err)
+	}
+
+	// duplicate pieces don't count
+	wh = pdcWorkerHeap{resolved(0), resolved(0), resolved(1)}
+	if err := pdc.checkRecoverablePieces(wh); !errors.Contains(err, errNotEnoughPieces) {
+		t.Fatal("unexpected", err)
+	}
+
+	// an unresolved worker might provide the missing piece
+	wh = append(wh, unresolved)
+	if err := pdc.checkRecoverablePieces(wh); err != nil {
+		t.Fatal(err)
+	}
+
+	// but it only counts once
+	wh = pdcWorkerHeap{resolved(0), unresolved}
+	if err := pdc.checkRecoverablePieces(wh); !errors.Contains(err, errNotEnoughPieces) {
+		t.Fatal("unexpected", err)
+	}
+}
+
+// TestPDCInitialWorkerExpectedCost verifies that the expected cost of a worker
+// is cached until its price table changes.
+func TestPDCInitialWorkerExpectedCost(t *testing.T) {
+	t.Parallel()
+
+	w := mockWorker(100 * time.Millisecond)
+	iw := &pdcInitialWorker{worker: w}
+
+	// the cost should match the read queue's estimate
+	length := uint64(1 << 16)
+	cost := iw.expectedCost(length)
+	if !cost.Equals(w.staticJobReadQueue.callExpectedJobCost(length)) {
+		t.Fatal("unexpected", cost)
+	}
+
+	// as long as the price table stays the same, the cached cost is returned
+	iw.cost = types.ZeroCurrency
+	if !iw.expectedCost(length).IsZero() {
+		t.Fatal("cost wasn't cached")
+	}
+
+	// update the price table, the cost should be recomputed
+	pt := newDefaultPriceTable()
+	pt.ReadBaseCost = pt.ReadBaseCost.Mul64(2)
+	w.staticSetPriceTable(&workerPriceTable{staticPriceTable: pt})
+	newCost := iw.expectedCost(length)
+	if !newCost.Equals(w.staticJobReadQueue.callExpectedJobCost(length)) {
+		t.Fatal("unexpected", newCost)
+	}
+	if newCost.Cmp(cost) <= 0 {
+		t.Fatal("cost wasn't recomputed", cost, newCost)
+	}
+}
+
 // TestProjectDownloadGouging checks that `checkProjectDownloadGouging` is
 // correctly detecting price gouging from a host.
 func TestProjectDownloadGouging(t *testing.T) {
@@ -444,3 +877,23 @@ func TestProjectDownloadGouging(t *testing.T) {
 		t.Fatalf("expected PDBR price gouging error, instead error was '%v'", err)
 	}
 }
+
+// BenchmarkPDCInitialWorkerExpectedCost compares computing the expected cost of
+// a worker on every update of the worker heap to using the cached cost.
+func BenchmarkPDCInitialWorkerExpectedCost(b *testing.B) {
+	w := mockWorker(100 * time.Millisecond)
+	length := uint64(1 << 16)
+
+	b.Run("Uncached", func(b *testing.B) {
+		jrq := w.staticJobReadQueue
+		for i := 0; i < b.N; i++ {
+			_ = jrq.callExpectedJobCost(length)
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		iw := &pdcInitialWorker{worker: w}
+		for i := 0; i < b.N; i++ {
+			_ = iw.expectedCost(length)
+		}
+	})
+}
@@ -208,27 +208,11 @@ func newDownloadRevision(current types.FileContractRevision, downloadCost types.
 // newUploadRevision revises the current revision to cover the cost of
 // uploading a sector.
 func newUploadRevision(current types.FileContractRevision, merkleRoot crypto.Hash, price, collateral types.Currency) (types.FileContractRevision, error) {
-	rev, err := current.PaymentRevision(price)
+	rev, err := skymodules.PaymentRevisionWithCollateral(current, price, collateral)
 	if err != nil {
 		return types.FileContractRevision{}, err
 	}
 
-	// Check that there is enough collateral to cover the cost.
-	if rev.MissedHostOutput().Value.Cmp(collateral) < 0 {
-		return types.FileContractRevision{}, types.ErrRevisionCollateralTooLow
-	}
-
-	// move collateral from host to void
-	rev.SetMissedHostPayout(rev.MissedHostOutput().Value.Sub(collateral))
-	voidOutput, err := rev.MissedVoidOutput()
-	if err != nil {
-		return types.FileContractRevision{}, errors.AddContext(err, "failed to get void output")
-	}
-	err = rev.SetMissedVoidPayout(voidOutput.Value.Add(collateral))
-	if err != nil {
-		return types.FileContractRevision{}, errors.AddContext(err, "failed to set void output")
-	}
-
 	// set new filesize and Merkle root
 	rev.NewFileSize += modules.SectorSize
 	rev.NewFileMerkleRoot = merkleRoot
@@ -293,14 +293,10 @@ func (s *Session) write(sc *SafeContract, actions []modules.LoopWriteAction) (_
 	}
 
 	// create the revision; we will update the Merkle root later
-	rev, err := contract.LastRevision().PaymentRevision(cost)
+	rev, err := skymodules.PaymentRevisionWithCollateral(contract.LastRevision(), cost, collateral)
 	if err != nil {
 		return skymodules.RenterContract{}, errors.AddContext(err, "Error creating new write revision")
 	}
-
-	rev.SetMissedHostPayout(rev.MissedHostOutput().Value.Sub(collateral))
-	voidOutput, err := rev.MissedVoidOutput()
-	rev.SetMissedVoidPayout(voidOutput.Value.Add(collateral))
 	rev.NewFileSize = newFileSize
 
 	// create the request
@@ -309,14 +305,7 @@ func (s *Session) write(sc *SafeContract, actions []modules.LoopWriteAction) (_
 		MerkleProof:       true,
 		NewRevisionNumber: rev.NewRevisionNumber,
 	}
-	req.NewValidProofValues = make([]types.Currency, len(rev.NewValidProofOutputs))
-	for i, o := range rev.NewValidProofOutputs {
-		req.NewValidProofValues[i] = o.Value
-	}
-	req.NewMissedProofValues = make([]types.Currency, len(rev.NewMissedProofOutputs))
-	for i, o := range rev.NewMissedProofOutputs {
-		req.NewMissedProofValues[i] = o.Value
-	}
+	req.NewValidProofValues, req.NewMissedProofValues = skymodules.ProofValues(rev)
 
 	defer func() {
 		// Increase Successful/Failed interactions accordingly
@@ -529,14 +518,7 @@ func (s *Session) Read(w io.Writer, req modules.LoopReadRequest, cancel <-chan s
 	txn.TransactionSignatures[0].Signature = sig[:]
 
 	req.NewRevisionNumber = rev.NewRevisionNumber
-	req.NewValidProofValues = make([]types.Currency, len(rev.NewValidProofOutputs))
-	for i, o := range rev.NewValidProofOutputs {
-		req.NewValidProofValues[i] = o.Value
-	}
-	req.NewMissedProofValues = make([]types.Currency, len(rev.NewMissedProofOutputs))
-	for i, o := range rev.NewMissedProofOutputs {
-		req.NewMissedProofValues[i] = o.Value
-	}
+	req.NewValidProofValues, req.NewMissedProofValues = skymodules.ProofValues(rev)
 	req.Signature = sig[:]
 
 	// record the change we are about to make to the contract. If we lose power
@@ -713,14 +695,7 @@ func (s *Session) SectorRoots(req modules.LoopSectorRootsRequest) (_ skymodules.
 
 	// fill in the missing request fields
 	req.NewRevisionNumber = rev.NewRevisionNumber
-	req.NewValidProofValues = make([]types.Currency, len(rev.NewValidProofOutputs))
-	for i, o := range rev.NewValidProofOutputs {
-		req.NewValidProofValues[i] = o.Value
-	}
-	req.NewMissedProofValues = make([]types.Currency, len(rev.NewMissedProofOutputs))
-	for i, o := range rev.NewMissedProofOutputs {
-		req.NewMissedProofValues[i] = o.Value
-	}
+	req.NewValidProofValues, req.NewMissedProofValues = skymodules.ProofValues(rev)
 	req.Signature = sig[:]
 
 	// record the change we are about to make to the contract. If we lose power
@@ -826,14 +801,7 @@ func (s *Session) RecoverSectorRoots(lastRev types.FileContractRevision, sk cryp
 
 	// fill in the missing request fields
 	req.NewRevisionNumber = rev.NewRevisionNumber
-	req.NewValidProofValues = make([]types.Currency, len(rev.NewValidProofOutputs))
-	for i, o := range rev.NewValidProofOutputs {
-		req.NewValidProofValues[i] = o.Value
-	}
-	req.NewMissedProofValues = make([]types.Currency, len(rev.NewMissedProofOutputs))
-	for i, o := range rev.NewMissedProofOutputs {
-		req.NewMissedProofValues[i] = o.Value
-	}
+	req.NewValidProofValues, req.NewMissedProofValues = skymodules.ProofValues(rev)
 	req.Signature = sig[:]
 
 	// Increase Successful/Failed interactions accordingly
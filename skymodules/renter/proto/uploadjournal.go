@@ -0,0 +1,257 @@
+package proto
+
+import (
+	"os"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// uploadJournalMeta is the metadata of the upload journal's persist file.
+	uploadJournalMeta = persist.Metadata{
+		Header:  "Upload Journal",
+		Version: "1.0",
+	}
+
+	// errUploadJournalMismatch is returned when an existing upload journal
+	// belongs to a different upload.
+	errUploadJournalMismatch = errors.New("upload journal belongs to a different upload")
+)
+
+type (
+	// UploadJournal records the progress of a multi-sector upload to a single
+	// contract. The sectors are appended in batches, one revision per batch,
+	// and the journal keeps track of which sectors the host confirmed. If the
+	// upload is interrupted, it can be resumed from the last confirmed sector
+	// instead of appending all of the sectors again.
+	UploadJournal struct {
+		staticPath string
+		persist    uploadJournalPersist
+		mu         sync.Mutex
+	}
+
+	// uploadJournalPersist is the persisted state of an UploadJournal.
+	uploadJournalPersist struct {
+		ContractID types.FileContractID `json:"contractid"`
+		Roots      []crypto.Hash        `json:"roots"`
+
+		// Confirmed is the number of sectors, starting at the first one, that
+		// were confirmed by the host. RevisionNumber is the number of the
+		// revision that confirmed the last of them.
+		Confirmed      uint64 `json:"confirmed"`
+		RevisionNumber uint64 `json:"revisionnumber"`
+
+		// Pending is the batch that was sent to the host but not confirmed
+		// yet.
+		Pending *uploadJournalBatch `json:"pending,omitempty"`
+	}
+
+	// uploadJournalBatch is a batch of sectors that is appended to the
+	// contract with a single revision.
+	uploadJournalBatch struct {
+		// NumSectors is the number of sectors in the batch.
+		NumSectors uint64 `json:"numsectors"`
+
+		// RevisionNumber is the number of the revision that appends the
+		// batch.
+		RevisionNumber uint64 `json:"revisionnumber"`
+
+		// SectorIndex is the index within the contract of the first sector
+		// of the batch.
+		SectorIndex uint64 `json:"sectorindex"`
+	}
+)
+
+// NewUploadJournal opens the upload journal at the given path for an upload of
+// the sectors with the given roots to a contract. If the journal doesn't exist
+// yet, a new one is created.
+func NewUploadJournal(path string, id types.FileContractID, roots []crypto.Hash) (*UploadJournal, error) {
+	j := &UploadJournal{
+		staticPath: path,
+	}
+	err := persist.LoadJSON(uploadJournalMeta, &j.persist, path)
+	if os.IsNotExist(err) {
+		j.persist = uploadJournalPersist{
+			ContractID: id,
+			Roots:      roots,
+		}
+		return j, j.save()
+	} else if err != nil {
+		return nil, errors.AddContext(err, "failed to load upload journal")
+	}
+
+	// Make sure the journal belongs to this upload.
+	if j.persist.ContractID != id || len(j.persist.Roots) != len(roots) {
+		return nil, errUploadJournalMismatch
+	}
+	for i := range roots {
+		if j.persist.Roots[i] != roots[i] {
+			return nil, errUploadJournalMismatch
+		}
+	}
+	return j, nil
+}
+
+// Confirmed returns the number of sectors that were confirmed by the host.
+func (j *UploadJournal) Confirmed() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.persist.Confirmed
+}
+
+// Done returns whether all sectors of the upload were confirmed.
+func (j *UploadJournal) Done() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.persist.Confirmed == uint64(len(j.persist.Roots))
+}
+
+// Remove deletes the journal from disk. It should be called once the upload is
+// done.
+func (j *UploadJournal) Remove() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return os.Remove(j.staticPath)
+}
+
+// managedCheckUpload checks that the journal belongs to an upload of the
+// sectors to the contract with the given id.
+func (j *UploadJournal) managedCheckUpload(id types.FileContractID, sectors [][]byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.persist.ContractID != id || len(sectors) != len(j.persist.Roots) {
+		return errUploadJournalMismatch
+	}
+	for i, sector := range sectors {
+		if crypto.MerkleRoot(sector) != j.persist.Roots[i] {
+			return errUploadJournalMismatch
+		}
+	}
+	return nil
+}
+
+// managedSetPending records the batch that is about to be sent to the host.
+func (j *UploadJournal) managedSetPending(batch uploadJournalBatch) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.persist.Pending = &batch
+	return j.save()
+}
+
+// managedConfirmPending marks the pending batch as confirmed by the host.
+func (j *UploadJournal) managedConfirmPending() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.confirmPending()
+	return j.save()
+}
+
+// managedReconcile reconciles the journal with the contract after a new session
+// synced the contract's revision with the host's. If the host applied the
+// pending batch before the upload was interrupted, the batch is marked as
+// confirmed. Otherwise it is dropped and will be sent again.
+func (j *UploadJournal) managedReconcile(revisionNumber, numSectors uint64, rootAt func(uint64) (crypto.Hash, error)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	pending := j.persist.Pending
+	if pending == nil {
+		return nil
+	}
+	applied := revisionNumber >= pending.RevisionNumber && numSectors >= pending.SectorIndex+pending.NumSectors
+	for i := uint64(0); applied && i < pending.NumSectors; i++ {
+		root, err := rootAt(pending.SectorIndex + i)
+		if err != nil {
+			return errors.AddContext(err, "failed to fetch contract root")
+		}
+		applied = root == j.persist.Roots[j.persist.Confirmed+i]
+	}
+	if applied {
+		j.confirmPending()
+	} else {
+		j.persist.Pending = nil
+	}
+	return j.save()
+}
+
+// confirmPending marks the pending batch as confirmed.
+func (j *UploadJournal) confirmPending() {
+	pending := j.persist.Pending
+	if pending == nil {
+		return
+	}
+	j.persist.Confirmed += pending.NumSectors
+	j.persist.RevisionNumber = pending.RevisionNumber
+	j.persist.Pending = nil
+}
+
+// save persists the journal.
+func (j *UploadJournal) save() error {
+	return persist.SaveJSON(uploadJournalMeta, j.persist, j.staticPath)
+}
+
+// AppendJournaled appends the sectors to the session's contract in batches of
+// at most batchSize sectors, recording every confirmed batch in the journal. If
+// a previous call was interrupted, the upload resumes from the last sector the
+// host confirmed. The sectors need to match the roots the journal was created
+// with.
+func (s *Session) AppendJournaled(j *UploadJournal, sectors [][]byte, batchSize uint64) (_ skymodules.RenterContract, err error) {
+	if batchSize == 0 {
+		return skymodules.RenterContract{}, errors.New("batch size must be at least 1")
+	}
+	sc, haveContract := s.contractSet.Acquire(s.contractID)
+	if !haveContract {
+		return skymodules.RenterContract{}, errors.New("contract not present in contract set")
+	}
+	defer s.contractSet.Return(sc)
+	if err := j.managedCheckUpload(sc.header.ID(), sectors); err != nil {
+		return skymodules.RenterContract{}, err
+	}
+
+	// Reconcile the journal with the revision that was synced with the host
+	// when the session was created.
+	rev := sc.header.LastRevision()
+	err = j.managedReconcile(rev.NewRevisionNumber, rev.NewFileSize/modules.SectorSize, func(i uint64) (crypto.Hash, error) {
+		return sc.merkleRoots.merkleRoot(int(i))
+	})
+	if err != nil {
+		return skymodules.RenterContract{}, errors.AddContext(err, "failed to reconcile upload journal")
+	}
+
+	rc := sc.Metadata()
+	for confirmed := j.Confirmed(); confirmed < uint64(len(sectors)); confirmed = j.Confirmed() {
+		end := confirmed + batchSize
+		if end > uint64(len(sectors)) {
+			end = uint64(len(sectors))
+		}
+		actions := make([]modules.LoopWriteAction, 0, end-confirmed)
+		for _, sector := range sectors[confirmed:end] {
+			actions = append(actions, modules.LoopWriteAction{Type: modules.WriteActionAppend, Data: sector})
+		}
+
+		// Record the batch before sending it.
+		rev := sc.header.LastRevision()
+		err = j.managedSetPending(uploadJournalBatch{
+			NumSectors:     end - confirmed,
+			RevisionNumber: rev.NewRevisionNumber + 1,
+			SectorIndex:    rev.NewFileSize / modules.SectorSize,
+		})
+		if err != nil {
+			return skymodules.RenterContract{}, errors.AddContext(err, "failed to update upload journal")
+		}
+		rc, err = s.write(sc, actions)
+		if err != nil {
+			return skymodules.RenterContract{}, errors.AddContext(err, "failed to append batch")
+		}
+		err = j.managedConfirmPending()
+		if err != nil {
+			return skymodules.RenterContract{}, errors.AddContext(err, "failed to update upload journal")
+		}
+	}
+	return rc, nil
+}
@@ -0,0 +1,191 @@
+package proto
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/NebulousLabs/fastrand"
+	"gitlab.com/SkynetLabs/skyd/build"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/persist"
+	"go.sia.tech/siad/types"
+)
+
+// newTestUploadJournal creates an upload journal for a test with the given
+// number of random roots.
+func newTestUploadJournal(t *testing.T, numRoots int) (*UploadJournal, string, types.FileContractID, []crypto.Hash) {
+	dir := build.TempDir(filepath.Join("proto", t.Name()))
+	if err := os.MkdirAll(dir, persist.DefaultDiskPermissionsTest); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "upload.journal")
+	var id types.FileContractID
+	fastrand.Read(id[:])
+	roots := make([]crypto.Hash, numRoots)
+	for i := range roots {
+		fastrand.Read(roots[i][:])
+	}
+	j, err := NewUploadJournal(path, id, roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j, path, id, roots
+}
+
+// TestUploadJournalPersist tests that the upload journal is persisted and only
+// loaded for the same upload.
+func TestUploadJournalPersist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	j, path, id, roots := newTestUploadJournal(t, 4)
+
+	// Confirm a batch.
+	err := j.managedSetPending(uploadJournalBatch{NumSectors: 2, RevisionNumber: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = j.managedConfirmPending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload the journal.
+	j, err = NewUploadJournal(path, id, roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Confirmed() != 2 || j.persist.RevisionNumber != 5 || j.Done() {
+		t.Fatal("wrong journal state", j.persist)
+	}
+
+	// Loading the journal for a different upload fails.
+	_, err = NewUploadJournal(path, types.FileContractID{}, roots)
+	if !errors.Contains(err, errUploadJournalMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+	_, err = NewUploadJournal(path, id, roots[:3])
+	if !errors.Contains(err, errUploadJournalMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Remove the journal.
+	err = j.Remove()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("journal wasn't removed", err)
+	}
+}
+
+// TestUploadJournalCheckUpload tests that the journal only accepts the sectors
+// and contract it was created for.
+func TestUploadJournalCheckUpload(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a journal for the roots of random sectors.
+	dir := build.TempDir(filepath.Join("proto", t.Name()))
+	if err := os.MkdirAll(dir, persist.DefaultDiskPermissionsTest); err != nil {
+		t.Fatal(err)
+	}
+	var id types.FileContractID
+	fastrand.Read(id[:])
+	sectors := make([][]byte, 3)
+	roots := make([]crypto.Hash, len(sectors))
+	for i := range sectors {
+		sectors[i] = fastrand.Bytes(int(modules.SectorSize))
+		roots[i] = crypto.MerkleRoot(sectors[i])
+	}
+	j, err := NewUploadJournal(filepath.Join(dir, "upload.journal"), id, roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The right sectors for the right contract are accepted.
+	if err := j.managedCheckUpload(id, sectors); err != nil {
+		t.Fatal(err)
+	}
+	// A different contract is rejected.
+	if err := j.managedCheckUpload(types.FileContractID{}, sectors); !errors.Contains(err, errUploadJournalMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+	// A different number of sectors is rejected.
+	if err := j.managedCheckUpload(id, sectors[:2]); !errors.Contains(err, errUploadJournalMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+	// Different sectors are rejected.
+	sectors[0], sectors[1] = sectors[1], sectors[0]
+	if err := j.managedCheckUpload(id, sectors); !errors.Contains(err, errUploadJournalMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+// TestUploadJournalReconcile tests reconciling the journal with the contract
+// after an interrupted upload.
+func TestUploadJournalReconcile(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	j, _, _, roots := newTestUploadJournal(t, 4)
+
+	// The contract already contains 10 sectors before the upload.
+	contractRoots := make([]crypto.Hash, 10)
+	rootAt := func(i uint64) (crypto.Hash, error) {
+		if i >= uint64(len(contractRoots)) {
+			return crypto.Hash{}, errors.New("out of bounds")
+		}
+		return contractRoots[i], nil
+	}
+
+	// The first batch is sent but the upload is interrupted before the host
+	// signed the revision. The contract is unchanged so the batch is dropped.
+	batch := uploadJournalBatch{NumSectors: 2, RevisionNumber: 8, SectorIndex: 10}
+	if err := j.managedSetPending(batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.managedReconcile(7, 10, rootAt); err != nil {
+		t.Fatal(err)
+	}
+	if j.Confirmed() != 0 || j.persist.Pending != nil {
+		t.Fatal("batch should have been dropped", j.persist)
+	}
+
+	// The batch is sent again and the upload is interrupted after the host
+	// signed the revision. Syncing the revision with the host added the
+	// sectors to the contract, so the batch is confirmed.
+	if err := j.managedSetPending(batch); err != nil {
+		t.Fatal(err)
+	}
+	contractRoots = append(contractRoots, roots[:2]...)
+	if err := j.managedReconcile(8, 12, rootAt); err != nil {
+		t.Fatal(err)
+	}
+	if j.Confirmed() != 2 || j.persist.RevisionNumber != 8 || j.persist.Pending != nil {
+		t.Fatal("batch should have been confirmed", j.persist)
+	}
+
+	// A revision with the right number but the wrong roots doesn't confirm
+	// the batch.
+	batch = uploadJournalBatch{NumSectors: 2, RevisionNumber: 9, SectorIndex: 12}
+	if err := j.managedSetPending(batch); err != nil {
+		t.Fatal(err)
+	}
+	contractRoots = append(contractRoots, roots[3], roots[2])
+	if err := j.managedReconcile(9, 14, rootAt); err != nil {
+		t.Fatal(err)
+	}
+	if j.Confirmed() != 2 || j.persist.Pending != nil {
+		t.Fatal("batch should have been dropped", j.persist)
+	}
+}
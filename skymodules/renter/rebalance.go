@@ -0,0 +1,128 @@
+package renter
+
+import (
+	"fmt"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"gitlab.com/SkynetLabs/skyd/skymodules/renter/filesystem"
+	"go.sia.tech/siad/types"
+)
+
+// RebalanceHost moves data away from a host by pushing every chunk that has a
+// piece stored on that host onto the upload heap. The host's pieces don't count
+// towards the redundancy of those chunks and the host is excluded from the set
+// of hosts the repair may upload to, so the repair uploads the pieces to other
+// hosts. This is useful for hosts that are running out of storage.
+//
+// NOTE: the pieces on the host are not removed from the siafiles since
+// siafiles don't support removing pieces. Once the chunks have been repaired,
+// the pieces on the host are simply redundant.
+func (r *Renter) RebalanceHost(pk types.SiaPublicKey) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+
+	// Build the set of hosts to upload to, excluding the host that is being
+	// rebalanced.
+	hpk := pk.String()
+	hosts := r.managedRefreshHostsAndWorkers()
+	delete(hosts, hpk)
+
+	// Treat the host as not good for renew so that its pieces don't count
+	// towards the health of the chunks.
+	offline, goodForRenew, _, _ := r.callRenterContractsAndUtilities()
+	gfr := make(map[string]bool, len(goodForRenew))
+	for k, v := range goodForRenew {
+		gfr[k] = v
+	}
+	gfr[hpk] = false
+
+	// Grab all the files of the renter.
+	var siaPaths []skymodules.SiaPath
+	var mu sync.Mutex
+	err := r.staticFileSystem.CachedList(skymodules.RootSiaPath(), true, func(fi skymodules.FileInfo) {
+		mu.Lock()
+		siaPaths = append(siaPaths, fi.SiaPath)
+		mu.Unlock()
+	}, func(skymodules.DirectoryInfo) {})
+	if err != nil {
+		return errors.AddContext(err, "failed to list files")
+	}
+
+	// Push the chunks of every file that has pieces on the host.
+	var allErrors error
+	var chunksAdded int
+	for _, siaPath := range siaPaths {
+		n, err := r.managedRebalanceFile(siaPath, hpk, hosts, offline, gfr)
+		if err != nil {
+			allErrors = errors.Compose(allErrors, errors.AddContext(err, fmt.Sprintf("failed to rebalance %v", siaPath)))
+		}
+		chunksAdded += n
+	}
+	r.staticRepairLog.Printf("Added %v chunks to the repair heap to rebalance host %v", chunksAdded, hpk)
+	return allErrors
+}
+
+// managedRebalanceFile pushes all chunks of the file at siaPath that have a
+// piece on the host with the given key onto the upload heap. It returns the
+// number of chunks that were pushed.
+func (r *Renter) managedRebalanceFile(siaPath skymodules.SiaPath, hpk string, hosts map[string]struct{}, offline, goodForRenew map[string]bool) (_ int, err error) {
+	sf, err := r.staticFileSystem.OpenSiaFile(siaPath)
+	if err != nil {
+		return 0, errors.AddContext(err, "unable to open siafile")
+	}
+	defer func() {
+		err = errors.Compose(err, sf.Close())
+	}()
+
+	chunksAdded := 0
+	for chunkIndex := uint64(0); chunkIndex < sf.NumChunks(); chunkIndex++ {
+		onHost, err := chunkHasPieceOnHost(sf, chunkIndex, hpk)
+		if err != nil {
+			return chunksAdded, err
+		}
+		if !onHost {
+			continue
+		}
+		chunk, repairing, err := r.managedBuildUnfinishedChunk(r.tg.StopCtx(), sf, chunkIndex, hosts, memoryPriorityLow, offline, goodForRenew, r.staticRepairMemoryManager)
+		if err != nil {
+			return chunksAdded, errors.AddContext(err, "unable to build chunk")
+		}
+		if repairing {
+			continue // already being repaired
+		}
+		chunk.fileRecentlySuccessful = true
+		_, pushed, err := r.managedPushChunkForRepair(chunk, chunkTypeLocalChunk)
+		if err != nil {
+			return chunksAdded, errors.Compose(err, chunk.Close())
+		}
+		if !pushed {
+			if err := chunk.Close(); err != nil {
+				return chunksAdded, err
+			}
+			continue
+		}
+		chunksAdded++
+	}
+	return chunksAdded, nil
+}
+
+// chunkHasPieceOnHost returns whether the chunk at the given index has a piece
+// stored on the host with the given key.
+func chunkHasPieceOnHost(sf *filesystem.FileNode, chunkIndex uint64, hpk string) (bool, error) {
+	pieces, err := sf.Pieces(chunkIndex)
+	if err != nil {
+		return false, errors.AddContext(err, "unable to get pieces")
+	}
+	for _, pieceSet := range pieces {
+		for _, piece := range pieceSet {
+			if piece.HostPubKey.String() == hpk {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
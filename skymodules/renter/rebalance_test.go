@@ -0,0 +1,60 @@
+package renter
+
+import (
+	"testing"
+
+	"gitlab.com/SkynetLabs/skyd/siatest/dependencies"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// TestRenterRebalanceHost verifies that RebalanceHost pushes the chunks with
+// pieces on the host onto the upload heap.
+func TestRenterRebalanceHost(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rt, err := newRenterTesterWithDependency(t.Name(), &dependencies.DependencyDisableRepairAndHealthLoops{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create a file with a piece of the first chunk on the host.
+	entry, err := rt.renter.newRenterTestFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.NumChunks() < 2 {
+		if err := entry.GrowNumChunks(2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pk := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{1}}
+	if err := entry.AddPiece(pk, 0, 0, crypto.Hash{}); err != nil {
+		t.Fatal(err)
+	}
+	uid := entry.UID()
+	if err := entry.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebalance the host.
+	if err := rt.renter.RebalanceHost(pk); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first chunk should be in the upload heap.
+	if !rt.renter.staticUploadHeap.managedExists(uploadChunkID{uid, 0}) {
+		t.Fatal("chunk with piece on the host should be in the heap")
+	}
+	if rt.renter.staticUploadHeap.managedExists(uploadChunkID{uid, 1}) {
+		t.Fatal("chunk without piece on the host shouldn't be in the heap")
+	}
+}
@@ -150,6 +150,15 @@ type hostContractor interface {
 	// Session creates a Session from the specified contract ID.
 	Session(types.SiaPublicKey, <-chan struct{}) (contractor.Session, error)
 
+	// OrphanedContracts returns the active contracts whose hosts are no
+	// longer in the hostdb.
+	OrphanedContracts() ([]skymodules.RenterContract, error)
+
+	// ContractsByHostRemainingStorage returns the GoodForUpload contracts
+	// paired with the remaining storage of their hosts, sorted by the
+	// remaining storage in descending order.
+	ContractsByHostRemainingStorage() ([]skymodules.ContractCapacity, error)
+
 	// RecoverableContracts returns the contracts that the contractor deems
 	// recoverable. That means they are not expired yet and also not part of the
 	// active contracts. Usually this should return an empty slice unless the host
@@ -211,6 +220,10 @@ type Renter struct {
 	// friendly to the atomic package, but actually it's a time.Duration.
 	atomicSystemHealthScanDuration uint64
 
+	// atomicStreamerCacheMemory is the amount of memory the streamers' caches
+	// currently hold from the user download memory manager.
+	atomicStreamerCacheMemory uint64
+
 	// Skynet Management
 	staticSkylinkManager    *skylinkManager
 	staticSkynetBlocklist   *skynetblocklist.SkynetBlocklist
@@ -228,6 +241,10 @@ type Renter struct {
 	// downloads, and instead only contains user-initiated downloads.
 	staticDownloadHistory *downloadHistory
 
+	// staticStickyWorkers remembers the workers that served the previous
+	// download of a chunk for downloads that prefer data locality.
+	staticStickyWorkers *stickyWorkerTracker
+
 	// Upload and repair management.
 	staticDirectoryHeap directoryHeap
 	staticStuckStack    stuckStack
@@ -287,6 +304,10 @@ type Renter struct {
 	staticUserDownloadMemoryManager *memoryManager
 	staticUserUploadMemoryManager   *memoryManager
 
+	// staticDownloadVariancePenalty is the penalty for unpredictable workers
+	// when picking the workers of a download.
+	staticDownloadVariancePenalty float64
+
 	// Modules and subsystems
 	staticAccountManager               *accountManager
 	staticAlerter                      *modules.GenericAlerter
@@ -326,6 +347,24 @@ func (r *Renter) Close() error {
 	return errors.Compose(r.tg.Stop(), r.staticHostDB.Close(), r.staticHostContractor.Close(), r.staticSkynetBlocklist.Close(), r.staticSkynetPortals.Close())
 }
 
+// DownloadMemoryStatus returns the current usage of the memory budget that is
+// shared by all user-initiated downloads and streamer caches.
+func (r *Renter) DownloadMemoryStatus() (skymodules.DownloadMemoryStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return skymodules.DownloadMemoryStatus{}, err
+	}
+	defer r.tg.Done()
+
+	status := r.staticUserDownloadMemoryManager.callStatus()
+	return skymodules.DownloadMemoryStatus{
+		Budget:         status.PriorityBase,
+		Used:           status.PriorityBase - status.PriorityAvailable,
+		Requested:      status.Requested + status.PriorityRequested,
+		StreamerCaches: atomic.LoadUint64(&r.atomicStreamerCacheMemory),
+		StreamBuffers:  atomic.LoadUint64(&r.staticStreamBufferSet.atomicMemory),
+	}, nil
+}
+
 // MemoryStatus returns the current status of the memory manager
 func (r *Renter) MemoryStatus() (skymodules.MemoryStatus, error) {
 	if err := r.tg.Add(); err != nil {
@@ -823,6 +862,18 @@ func (r *Renter) PeriodSpending() (skymodules.ContractorSpending, error) {
 	return r.staticHostContractor.PeriodSpending()
 }
 
+// OrphanedContracts returns the host contractor's active contracts whose
+// hosts are no longer in the hostdb.
+func (r *Renter) OrphanedContracts() ([]skymodules.RenterContract, error) {
+	return r.staticHostContractor.OrphanedContracts()
+}
+
+// ContractsByHostRemainingStorage returns the host contractor's GoodForUpload
+// contracts sorted by the remaining storage of their hosts.
+func (r *Renter) ContractsByHostRemainingStorage() ([]skymodules.ContractCapacity, error) {
+	return r.staticHostContractor.ContractsByHostRemainingStorage()
+}
+
 // RecoverableContracts returns the host contractor's recoverable contracts.
 func (r *Renter) RecoverableContracts() []skymodules.RecoverableContract {
 	return r.staticHostContractor.RecoverableContracts()
@@ -1100,6 +1151,7 @@ func renterBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 		},
 
 		staticDownloadHistory: newDownloadHistory(),
+		staticStickyWorkers:   newStickyWorkerTracker(),
 
 		ongoingRegistryRepairs: make(map[modules.RegistryEntryID]struct{}),
 
@@ -1166,8 +1218,9 @@ func renterBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 
 	r.staticRegistryMemoryManager = newMemoryManager(registryMemoryDefault, registryMemoryPriorityDefault, r.tg.StopChan())
 	r.staticUserUploadMemoryManager = newMemoryManager(userUploadMemoryDefault, userUploadMemoryPriorityDefault, r.tg.StopChan())
-	r.staticUserDownloadMemoryManager = newMemoryManager(userDownloadMemoryDefault, userDownloadMemoryPriorityDefault, r.tg.StopChan())
+	r.staticUserDownloadMemoryManager = newMemoryManager(userDownloadMemory(), userDownloadMemoryPriorityDefault, r.tg.StopChan())
 	r.staticRepairMemoryManager = newMemoryManager(repairMemoryDefault, repairMemoryPriorityDefault, r.tg.StopChan())
+	r.staticDownloadVariancePenalty = downloadVariancePenalty()
 
 	r.staticFuseManager = newFuseManager(r)
 	r.staticStuckStack = callNewStuckStack()
@@ -1193,7 +1246,7 @@ func renterBlockingStartup(g modules.Gateway, cs modules.ConsensusSet, tpool mod
 	}
 
 	// Init stream buffer now that the stats are initialised.
-	r.staticStreamBufferSet = newStreamBufferSet(r.staticStreamBufferStats, &r.tg)
+	r.staticStreamBufferSet = newStreamBufferSet(r.staticStreamBufferStats, r.staticUserDownloadMemoryManager, &r.tg)
 
 	// After persist is initialized, create the worker pool.
 	r.staticWorkerPool = r.newWorkerPool()
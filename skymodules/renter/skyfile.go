@@ -101,6 +101,10 @@ var (
 	// ErrInvalidSkylinkVersion is returned when an operation fails due to the
 	// skylink having the wrong version.
 	ErrInvalidSkylinkVersion = errors.New("skylink had unexpected version")
+
+	// ErrSkylinkMismatch is returned by VerifyDownload if the downloaded data
+	// doesn't match the skylink it was downloaded from.
+	ErrSkylinkMismatch = errors.New("downloaded data doesn't match skylink")
 )
 
 // skyfileEstablishDefaults will set any zero values in the lup to be equal to
@@ -300,6 +304,12 @@ func (r *Renter) managedCreateSkylinkRawMD(ctx context.Context, sup skymodules.S
 	// Create the base sector.
 	baseSector, fetchSize := skymodules.BuildBaseSector(sl.Encode(), fanoutBytes, metadataBytes, nil)
 
+	// Make sure the base sector can be parsed again when the skyfile is
+	// downloaded.
+	if ok, reason := skymodules.CanParseSkyfile(baseSector); !ok {
+		return skymodules.Skylink{}, errors.New("skyfile base sector can't be parsed: " + reason)
+	}
+
 	// Encrypt the base sector if necessary.
 	if encryptionEnabled(&sup) {
 		err := encryptBaseSectorWithSkykey(baseSector, sl, sup.FileSpecificSkykey)
@@ -587,6 +597,12 @@ func (r *Renter) managedUploadSkyfileSmallFile(ctx context.Context, sup skymodul
 	// errors are caught before a large block of memory is allocated.
 	baseSector, fetchSize := skymodules.BuildBaseSector(sl.Encode(), nil, metadataBytes, fileBytes) // 'nil' because there is no fanout
 
+	// Make sure the base sector can be parsed again when the skyfile is
+	// downloaded.
+	if ok, reason := skymodules.CanParseSkyfile(baseSector); !ok {
+		return skymodules.Skylink{}, errors.New("skyfile base sector can't be parsed: " + reason)
+	}
+
 	if encryptionEnabled(&sup) {
 		err = encryptBaseSectorWithSkykey(baseSector, sl, sup.FileSpecificSkykey)
 		if err != nil {
@@ -762,6 +778,143 @@ func (r *Renter) DownloadByRoot(root crypto.Hash, offset, length uint64, timeout
 	return data, err
 }
 
+// EstimateDownloadByRootCost returns the expected cost and duration of fetching
+// data using the merkle root of that data. The workers are selected the same
+// way as for DownloadByRoot, but no jobs are launched. Instead of looking the
+// root up on the network, the estimate relies on the workers' caches, price
+// tables and past performance. Every worker is assumed to have the root with
+// the rate at which its past HasSector jobs found a sector.
+func (r *Renter) EstimateDownloadByRootCost(root crypto.Hash, offset, length uint64, timeout time.Duration, pricePerMS types.Currency) (types.Currency, time.Duration, error) {
+	if err := r.tg.Add(); err != nil {
+		return types.ZeroCurrency, 0, err
+	}
+	defer r.tg.Done()
+
+	pdc, err := r.managedNewEstimatePDC(root, offset, length, timeout, pricePerMS)
+	if err != nil {
+		return types.ZeroCurrency, 0, err
+	}
+	return pdc.EstimateCost()
+}
+
+// DownloadCompletionForecast returns the expected completion times of fetching
+// the base sector of the given V1 skylink, without launching any jobs. The
+// workers are selected the same way as for EstimateDownloadByRootCost and the
+// forecast is derived from the combined read distributions of the selected
+// workers.
+func (r *Renter) DownloadCompletionForecast(link skymodules.Skylink, timeout time.Duration, pricePerMS types.Currency) (skymodules.DownloadForecast, error) {
+	if err := r.tg.Add(); err != nil {
+		return skymodules.DownloadForecast{}, err
+	}
+	defer r.tg.Done()
+
+	if !link.IsSkylinkV1() {
+		return skymodules.DownloadForecast{}, errors.AddContext(ErrInvalidSkylinkVersion, "only V1 skylinks can be forecast")
+	}
+	offset, fetchSize, err := link.OffsetAndFetchSize()
+	if err != nil {
+		return skymodules.DownloadForecast{}, errors.AddContext(err, "unable to parse skylink")
+	}
+	pdc, err := r.managedNewEstimatePDC(link.MerkleRoot(), offset, fetchSize, timeout, pricePerMS)
+	if err != nil {
+		return skymodules.DownloadForecast{}, err
+	}
+	return pdc.Forecast()
+}
+
+// managedNewEstimatePDC creates a pdc for fetching data using the merkle root
+// of that data. The pdc can only be used for estimates since its worker set
+// doesn't launch any HasSector jobs.
+func (r *Renter) managedNewEstimatePDC(root crypto.Hash, offset, length uint64, timeout time.Duration, pricePerMS types.Currency) (*projectDownloadChunk, error) {
+	// Check if the merkleroot is blocked
+	if r.staticSkynetBlocklist.IsHashBlocked(crypto.HashObject(root)) {
+		return nil, ErrSkylinkBlocked
+	}
+
+	// Create the context
+	ctx := r.tg.StopCtx()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(r.tg.StopCtx(), timeout)
+		defer cancel()
+	}
+
+	// Create the pcws the same way managedDownloadByRoot does, but without
+	// launching the HasSector jobs.
+	ptec := skymodules.NewPassthroughErasureCoder()
+	tpsk, err := crypto.NewSiaKey(crypto.TypePlain, nil)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create plain skykey")
+	}
+	pcws, err := r.newEstimatePCWSByRoots(ctx, []crypto.Hash{root}, ptec, tpsk, 0)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create the worker set for this root")
+	}
+	return pcws.managedNewProjectDownloadChunk(ctx, pricePerMS, offset, length, false, false)
+}
+
+// WhyNotSelected returns a human-readable reason why the given host isn't
+// selected for downloading the base sector of the given skylink. It waits for
+// the HasSector queries of the workers and then runs the same checks and
+// worker selection as a download, without launching the download.
+func (r *Renter) WhyNotSelected(skylink string, pk types.SiaPublicKey) (string, error) {
+	if err := r.tg.Add(); err != nil {
+		return "", err
+	}
+	defer r.tg.Done()
+
+	// Parse the skylink.
+	var link skymodules.Skylink
+	if err := link.LoadString(skylink); err != nil {
+		return "", errors.AddContext(err, "unable to parse skylink")
+	}
+
+	// Create the context.
+	ctx, cancel := context.WithTimeout(r.tg.StopCtx(), pcwsHasSectorTimeout)
+	defer cancel()
+
+	// Resolve the skylink if necessary.
+	link, _, err := r.managedTryResolveSkylinkV2(ctx, link, true)
+	if err != nil {
+		return "", errors.AddContext(err, "failed to resolve skylink")
+	}
+	offset, fetchSize, err := link.OffsetAndFetchSize()
+	if err != nil {
+		return "", errors.AddContext(err, "unable to parse skylink")
+	}
+
+	// Without a worker the host can't be used at all.
+	w, err := r.staticWorkerPool.callWorker(pk)
+	if err != nil {
+		return "renter has no worker for the host, it doesn't have a contract with it", nil
+	}
+
+	// Create the pcws the same way managedDownloadByRoot does and wait for
+	// the workers to resolve.
+	ptec := skymodules.NewPassthroughErasureCoder()
+	tpsk, err := crypto.NewSiaKey(crypto.TypePlain, nil)
+	if err != nil {
+		return "", errors.AddContext(err, "unable to create plain skykey")
+	}
+	pcws, err := r.newPCWSByRoots(ctx, []crypto.Hash{link.MerkleRoot()}, ptec, tpsk, 0)
+	if err != nil {
+		return "", errors.AddContext(err, "unable to create the worker set for this skylink")
+	}
+	// The worker selection uses the default price per millisecond of skynet
+	// downloads through the API.
+	pdc, err := pcws.managedNewProjectDownloadChunk(ctx, skymodules.DefaultSkynetPricePerMS, offset, fetchSize, false, false)
+	if err != nil {
+		return "", err
+	}
+	pdc.workerState.WaitForResults(ctx)
+
+	reason := pdc.whyNotSelected(w)
+	if reason == "" {
+		return "host is selected for the download", nil
+	}
+	return reason, nil
+}
+
 // DownloadSkylink will take a link and turn it into the metadata and data of a
 // download.
 func (r *Renter) DownloadSkylink(link skymodules.Skylink, timeout time.Duration, pricePerMS types.Currency) (skymodules.SkyfileStreamer, []skymodules.RegistryEntry, error) {
@@ -843,6 +996,59 @@ func (r *Renter) DownloadSkylinkBaseSector(link skymodules.Skylink, timeout time
 	return StreamerFromSlice(baseSector), srvs, link, err
 }
 
+// VerifyDownload verifies that the downloaded base sector of a skyfile
+// matches the skylink it was downloaded from. The base sector is rebuilt from
+// its parsed layout, fanout, metadata and payload and the Merkle root of the
+// result is compared against the root embedded in the skylink. That way the
+// data is verified end-to-end without relying on any intermediate proofs.
+//
+// The skylink needs to be a V1 skylink which points to the start of a base
+// sector, so V2 skylinks need to be resolved first. Encrypted base sectors
+// can't be parsed without the skykey, so their raw data is verified instead.
+func (r *Renter) VerifyDownload(link skymodules.Skylink, data []byte) error {
+	if !link.IsSkylinkV1() {
+		return errors.AddContext(ErrInvalidSkylinkVersion, "only V1 skylinks can be verified")
+	}
+	offset, fetchSize, err := link.OffsetAndFetchSize()
+	if err != nil {
+		return errors.AddContext(err, "unable to get offset and fetch size")
+	}
+	if offset != 0 {
+		return fmt.Errorf("skylink has offset %v, only skylinks that point to the start of a sector can be verified", offset)
+	}
+	if uint64(len(data)) > modules.SectorSize {
+		return errors.AddContext(ErrSkylinkMismatch, fmt.Sprintf("data has length %v which exceeds the sector size %v", len(data), modules.SectorSize))
+	}
+	if uint64(len(data)) < fetchSize {
+		return errors.AddContext(ErrSkylinkMismatch, fmt.Sprintf("data has length %v but skylink has fetch size %v", len(data), fetchSize))
+	}
+
+	// Pad the data to a full sector.
+	downloaded := make([]byte, modules.SectorSize)
+	copy(downloaded, data)
+
+	// Rebuild the base sector from its parsed components. Encrypted base
+	// sectors are used as they are.
+	baseSector := downloaded
+	if !skymodules.IsEncryptedBaseSector(downloaded) {
+		sl, fanoutBytes, _, rawSM, payload, err := skymodules.ParseSkyfileMetadata(downloaded)
+		if err != nil {
+			return errors.Compose(ErrSkylinkMismatch, errors.AddContext(err, "unable to parse base sector"))
+		}
+		baseSector, _ = skymodules.BuildBaseSector(sl.Encode(), fanoutBytes, rawSM, payload)
+		if !bytes.Equal(baseSector, downloaded) {
+			return errors.AddContext(ErrSkylinkMismatch, "rebuilt base sector differs from the downloaded data")
+		}
+	}
+
+	// Compare the roots.
+	root := crypto.MerkleRoot(baseSector)
+	if root != link.MerkleRoot() {
+		return errors.AddContext(ErrSkylinkMismatch, fmt.Sprintf("base sector has merkle root %v but skylink has merkle root %v", root, link.MerkleRoot()))
+	}
+	return nil
+}
+
 // managedDownloadSkylink will take a link and turn it into the metadata and
 // data of a download.
 func (r *Renter) managedDownloadSkylink(ctx context.Context, link skymodules.Skylink, streamReadTimeout time.Duration, pricePerMS types.Currency) (skymodules.SkyfileStreamer, error) {
@@ -874,7 +1080,11 @@ func (r *Renter) managedDownloadSkylink(ctx context.Context, link skymodules.Sky
 	// cached.
 	id := link.DataSourceID()
 	var stream *stream
-	stream, exists = r.staticStreamBufferSet.callNewStreamFromID(ctx, id, 0, streamReadTimeout)
+	var err error
+	stream, exists, err = r.staticStreamBufferSet.callNewStreamFromID(ctx, id, 0, streamReadTimeout)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create stream for skylink")
+	}
 	if exists {
 		return stream, nil
 	}
@@ -884,7 +1094,10 @@ func (r *Renter) managedDownloadSkylink(ctx context.Context, link skymodules.Sky
 	if err != nil {
 		return nil, errors.AddContext(err, "unable to create data source for skylink")
 	}
-	stream = r.staticStreamBufferSet.callNewStream(ctx, dataSource, 0, streamReadTimeout, pricePerMS)
+	stream, err = r.staticStreamBufferSet.callNewStream(ctx, dataSource, 0, streamReadTimeout, pricePerMS)
+	if err != nil {
+		return nil, errors.AddContext(err, "unable to create stream for skylink")
+	}
 	return stream, nil
 }
 
@@ -1012,7 +1225,16 @@ func (r *Renter) PinSkylink(skylink skymodules.Skylink, lup skymodules.SkyfileUp
 	if err != nil {
 		return errors.AddContext(err, "unable to create data source for skylink")
 	}
-	stream := r.staticStreamBufferSet.callNewStream(ctx, dataSource, 0, timeout, pricePerMS)
+	stream, err := r.staticStreamBufferSet.callNewStream(ctx, dataSource, 0, timeout, pricePerMS)
+	if err != nil {
+		return errors.AddContext(err, "unable to create stream for skylink")
+	}
+	defer func() {
+		// Close the stream to release its memory once the upload is done.
+		if err := stream.Close(); err != nil {
+			r.staticLog.Println("failed to close pinning stream:", err)
+		}
+	}()
 
 	// Upload directly from the stream.
 	fileNode, err := r.callUploadStreamFromReader(ctx, fup, stream)
@@ -1085,8 +1307,10 @@ func (r *Renter) RestoreSkyfile(reader io.Reader) (skymodules.Skylink, error) {
 		DefaultPath:        sm.DefaultPath,
 		DisableDefaultPath: sm.DisableDefaultPath,
 
-		TryFiles:   sm.TryFiles,
-		ErrorPages: sm.ErrorPages,
+		TryFiles:    sm.TryFiles,
+		ErrorPages:  sm.ErrorPages,
+		Immutable:   sm.Immutable,
+		ContentType: sm.DefaultContentType,
 	}
 	skyfileEstablishDefaults(&sup)
 
@@ -1310,6 +1534,85 @@ func (r *Renter) ResolveSkylinkV2(ctx context.Context, sl skymodules.Skylink) (s
 	return slResolved, srvs, nil
 }
 
+// PinnedSkyfiles returns the health of all the skyfiles pinned by the renter.
+// The health is based on the cached health and redundancy of the skyfiles'
+// siafiles which avoids having to download the skyfiles.
+func (r *Renter) PinnedSkyfiles() ([]skymodules.PinnedSkyfileHealth, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+
+	// Collect the infos of all files with skylinks.
+	var infos []skymodules.FileInfo
+	var mu sync.Mutex
+	err := r.staticFileSystem.CachedList(skymodules.SkynetFolder, true, func(fi skymodules.FileInfo) {
+		if len(fi.Skylinks) == 0 {
+			return
+		}
+		mu.Lock()
+		infos = append(infos, fi)
+		mu.Unlock()
+	}, func(skymodules.DirectoryInfo) {})
+	if err != nil {
+		return nil, errors.AddContext(err, "failed to list skynet folder")
+	}
+
+	offline, goodForRenew, _, _ := r.callRenterContractsAndUtilities()
+	pinned := make([]skymodules.PinnedSkyfileHealth, 0, len(infos))
+	for _, fi := range infos {
+		entry, err := r.staticFileSystem.OpenSiaFile(fi.SiaPath)
+		if errors.Contains(err, filesystem.ErrNotExist) {
+			continue // file was deleted in the meantime
+		}
+		if err != nil {
+			return nil, errors.AddContext(err, "failed to open siafile")
+		}
+		minPieces := uint64(entry.ErasureCode().MinPieces())
+
+		reachablePieces := worstChunkReachablePieces(entry, offline, goodForRenew)
+		err = entry.Close()
+		if err != nil {
+			return nil, errors.AddContext(err, "failed to close siafile")
+		}
+
+		pinned = append(pinned, skymodules.PinnedSkyfileHealth{
+			SiaPath:            fi.SiaPath,
+			Skylinks:           fi.Skylinks,
+			Health:             fi.Health,
+			Redundancy:         fi.Redundancy,
+			MinPieces:          minPieces,
+			ReachablePieces:    reachablePieces,
+			MeetsMinRedundancy: !skymodules.NeedsRepair(fi.Health),
+		})
+	}
+	return pinned, nil
+}
+
+// worstChunkReachablePieces returns the number of pieces of the file's worst
+// chunk which are stored on online hosts that the renter has a contract with.
+func worstChunkReachablePieces(entry *filesystem.FileNode, offline, goodForRenew map[string]bool) uint64 {
+	var reachable uint64
+	for chunkIndex := uint64(0); chunkIndex < entry.NumChunks(); chunkIndex++ {
+		_, online := entry.GoodPieces(int(chunkIndex), offline, goodForRenew)
+		if chunkIndex == 0 || online < reachable {
+			reachable = online
+		}
+	}
+	return reachable
+}
+
+// SkyfileAvailability probes the network for the pieces of a skyfile and
+// reports how many of the pieces needed to recover it are currently reachable
+// and from how many distinct hosts.
+func (r *Renter) SkyfileAvailability(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skymodules.AvailabilityReport, error) {
+	if err := r.tg.Add(); err != nil {
+		return skymodules.AvailabilityReport{}, err
+	}
+	defer r.tg.Done()
+	return r.managedSkyfileAvailability(ctx, sl, ppms)
+}
+
 // SkylinkHealth returns the health of a skylink on the network.
 func (r *Renter) SkylinkHealth(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skymodules.SkylinkHealth, error) {
 	if err := r.tg.Add(); err != nil {
@@ -1413,24 +1716,66 @@ func (r *Renter) managedTryResolveSkylinkV2(ctx context.Context, link skymodules
 	return link, srvs, nil
 }
 
-// managedSkylinkHealth returns the health of a skylink on the network.
-func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skymodules.SkylinkHealth, error) {
+// skylinkAvailability contains the results of querying the network for all the
+// roots of a skylink.
+type skylinkAvailability struct {
+	layout skymodules.SkyfileLayout
+
+	// baseSectorHosts are the hosts which have the base sector.
+	baseSectorHosts map[string]struct{}
+
+	// rootHosts are the hosts which have the root at the corresponding index
+	// in the fanout. rootIndexToChunkIndex maps these indices to the index of
+	// the chunk the root belongs to.
+	numChunks             int
+	rootHosts             []map[string]struct{}
+	rootIndexToChunkIndex map[int]int
+}
+
+// chunkGoodPieces returns a slice of good pieces for each chunk. A chunk has a
+// good piece if a root belonging to the chunk exists >0 times on the network.
+func (sa skylinkAvailability) chunkGoodPieces() []int {
+	numPieces := int(sa.layout.FanoutDataPieces + sa.layout.FanoutParityPieces)
+	chunkGoodPieces := make([]int, sa.numChunks)
+	onlyOnePiecePerChunk := sa.layout.FanoutDataPieces == 1 && sa.layout.CipherType == crypto.TypePlain
+	for i := 0; i < len(sa.rootHosts); i++ {
+		chunkIndex := sa.rootIndexToChunkIndex[i]
+		if onlyOnePiecePerChunk {
+			// Special Case: If we only need one piece per chunk, we
+			// count all occurrences of that piece up until
+			// numPieces.
+			chunkGoodPieces[chunkIndex] += len(sa.rootHosts[i])
+			if chunkGoodPieces[chunkIndex] > numPieces {
+				chunkGoodPieces[chunkIndex] = numPieces
+			}
+		} else if len(sa.rootHosts[i]) > 0 {
+			// Otherwise every piece only counts as 1 good piece.
+			chunkGoodPieces[chunkIndex]++
+		}
+	}
+	return chunkGoodPieces
+}
+
+// managedSkylinkAvailability downloads the base sector of a skylink and then
+// dispatches HasSector jobs for all of the roots in its fanout to find out
+// which hosts have which pieces of the skyfile.
+func (r *Renter) managedSkylinkAvailability(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skylinkAvailability, error) {
 	// Resolve the skylink if necessary.
 	sl, _, err := r.managedTryResolveSkylinkV2(ctx, sl, true)
 	if err != nil {
-		return skymodules.SkylinkHealth{}, errors.AddContext(err, "failed to resolve skylink")
+		return skylinkAvailability{}, errors.AddContext(err, "failed to resolve skylink")
 	}
 
 	// Get the offset and fetchsize from the skylink
 	offset, fetchSize, err := sl.OffsetAndFetchSize()
 	if err != nil {
-		return skymodules.SkylinkHealth{}, errors.AddContext(err, "unable to parse offset and fetchsize from skylink")
+		return skylinkAvailability{}, errors.AddContext(err, "unable to parse offset and fetchsize from skylink")
 	}
 
 	// Get base sector.
 	baseSector, ws, err := r.managedDownloadByRoot(ctx, sl.MerkleRoot(), offset, fetchSize, ppms)
 	if err != nil {
-		return skymodules.SkylinkHealth{}, errors.AddContext(err, "unable to download base sector")
+		return skylinkAvailability{}, errors.AddContext(err, "unable to download base sector")
 	}
 
 	// Check if the base sector is encrypted, and attempt to decrypt it.
@@ -1438,14 +1783,14 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 	if encrypted {
 		_, err = r.managedDecryptBaseSector(baseSector)
 		if err != nil {
-			return skymodules.SkylinkHealth{}, errors.AddContext(err, "failed to decrypt base sector")
+			return skylinkAvailability{}, errors.AddContext(err, "failed to decrypt base sector")
 		}
 	}
 
 	// Parse out the metadata of the skyfile.
 	layout, fanoutBytes, _, _, _, err := skymodules.ParseSkyfileMetadata(baseSector)
 	if err != nil {
-		return skymodules.SkylinkHealth{}, errors.AddContext(err, "error parsing skyfile metadata")
+		return skylinkAvailability{}, errors.AddContext(err, "error parsing skyfile metadata")
 	}
 	numPieces := int(layout.FanoutDataPieces + layout.FanoutParityPieces)
 
@@ -1461,7 +1806,7 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 		// don't compress the fanout.
 		fanoutChunks, err := layout.DecodeFanoutIntoChunks(fanoutBytes)
 		if err != nil {
-			return skymodules.SkylinkHealth{}, errors.AddContext(err, "error parsing skyfile fanout")
+			return skylinkAvailability{}, errors.AddContext(err, "error parsing skyfile fanout")
 		}
 
 		for chunkIndex, chunk := range fanoutChunks {
@@ -1510,15 +1855,19 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 
 		// If a batch has 0 launched workers we are done.
 		if launchedWorkers == 0 {
-			return skymodules.SkylinkHealth{}, errors.New("no workers were launched successfully")
+			return skylinkAvailability{}, errors.New("no workers were launched successfully")
 		}
 	}
 
-	// Each batch has its own waiting goroutine.
+	// Each batch has its own waiting goroutine. Every root belongs to
+	// exactly one batch, so the goroutines never access the same map.
 	// TODO: Once Sia has upgraded to support larger MDM programs we don't
 	// need the batching here anymore.
 	var wg sync.WaitGroup
-	rootTotals := make([]uint64, len(roots))
+	rootHosts := make([]map[string]struct{}, len(roots))
+	for i := range rootHosts {
+		rootHosts[i] = make(map[string]struct{})
+	}
 	for batchIndex, responseChan := range responseChans {
 		wg.Add(1)
 		go func(batchIndex uint64, responseChan chan *jobHasSectorResponse) {
@@ -1539,7 +1888,7 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 				for i, available := range resp.staticAvailables {
 					if available {
 						batchOffset := uint64(maxHasSectorBatchSize) * batchIndex
-						rootTotals[batchOffset+uint64(i)]++
+						rootHosts[batchOffset+uint64(i)][resp.staticWorker.staticHostPubKeyStr] = struct{}{}
 					}
 				}
 			}
@@ -1549,39 +1898,36 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 
 	// Wait for the worker state results for the base sector.
 	resps := ws.WaitForResults(ctx)
-	var baseSectorRedundancy uint64
+	baseSectorHosts := make(map[string]struct{})
 	for _, resp := range resps {
 		if resp.err != nil {
 			continue
 		}
 		// Check > 0 because base sector only has 1 piece.
 		if len(resp.pieceIndices) > 0 {
-			baseSectorRedundancy++
+			baseSectorHosts[resp.worker.staticHostPubKeyStr] = struct{}{}
 		}
 	}
+	return skylinkAvailability{
+		layout:                layout,
+		baseSectorHosts:       baseSectorHosts,
+		numChunks:             numChunks,
+		rootHosts:             rootHosts,
+		rootIndexToChunkIndex: rootIndexToChunkIndex,
+	}, nil
+}
 
-	// Create a slice of good pieces for each chunk. A chunk has a good
-	// piece if a root belonging to the chunk exists >0 times on the
-	// network.
-	chunkGoodPieces := make([]int, numChunks)
-	onlyOnePiecePerChunk := layout.FanoutDataPieces == 1 && layout.CipherType == crypto.TypePlain
-	for i := 0; i < len(rootTotals); i++ {
-		chunkIndex := rootIndexToChunkIndex[i]
-		if onlyOnePiecePerChunk {
-			// Special Case: If we only need one piece per chunk, we
-			// count all occurrences of that piece up until
-			// numPieces.
-			chunkGoodPieces[chunkIndex] += int(rootTotals[i])
-			if chunkGoodPieces[chunkIndex] > numPieces {
-				chunkGoodPieces[chunkIndex] = numPieces
-			}
-		} else if rootTotals[i] > 0 {
-			// Otherwise every piece only counts as 1 good piece.
-			chunkGoodPieces[chunkIndex]++
-		}
+// managedSkylinkHealth returns the health of a skylink on the network.
+func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skymodules.SkylinkHealth, error) {
+	sa, err := r.managedSkylinkAvailability(ctx, sl, ppms)
+	if err != nil {
+		return skymodules.SkylinkHealth{}, err
 	}
+	layout := sa.layout
+	numPieces := int(layout.FanoutDataPieces + layout.FanoutParityPieces)
 
 	// Set the base sector redundancy.
+	baseSectorRedundancy := uint64(len(sa.baseSectorHosts))
 	health := skymodules.SkylinkHealth{
 		BaseSectorRedundancy: baseSectorRedundancy,
 	}
@@ -1593,8 +1939,9 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 
 	// Compute the health of all chunks and remember the worst one. That's
 	// the overall fanout health.
+	chunkGoodPieces := sa.chunkGoodPieces()
 	worstHealth := float64(numPieces / int(layout.FanoutDataPieces))
-	fanoutHealth := make([]float64, 0, numChunks)
+	fanoutHealth := make([]float64, 0, len(chunkGoodPieces))
 	for _, goodPieces := range chunkGoodPieces {
 		chunkHealth := float64(goodPieces) / float64(layout.FanoutDataPieces)
 		if chunkHealth < worstHealth {
@@ -1610,3 +1957,57 @@ func (r *Renter) managedSkylinkHealth(ctx context.Context, sl skymodules.Skylink
 		FanoutParityPieces:        layout.FanoutParityPieces,
 	}, nil
 }
+
+// managedSkyfileAvailability returns a report of how many of the pieces
+// needed to recover a skyfile are currently reachable on the network.
+func (r *Renter) managedSkyfileAvailability(ctx context.Context, sl skymodules.Skylink, ppms types.Currency) (skymodules.AvailabilityReport, error) {
+	sa, err := r.managedSkylinkAvailability(ctx, sl, ppms)
+	if err != nil {
+		return skymodules.AvailabilityReport{}, err
+	}
+	return sa.availabilityReport(), nil
+}
+
+// availabilityReport turns the availability into an AvailabilityReport.
+func (sa skylinkAvailability) availabilityReport() skymodules.AvailabilityReport {
+	// Count the distinct hosts that hold at least one piece of the file.
+	hosts := make(map[string]struct{})
+	for hpk := range sa.baseSectorHosts {
+		hosts[hpk] = struct{}{}
+	}
+	for _, rh := range sa.rootHosts {
+		for hpk := range rh {
+			hosts[hpk] = struct{}{}
+		}
+	}
+
+	// The base sector is needed in any case.
+	report := skymodules.AvailabilityReport{
+		BaseSectorHosts: uint64(len(sa.baseSectorHosts)),
+		NumHosts:        uint64(len(hosts)),
+		PiecesNeeded:    1,
+	}
+	if len(sa.baseSectorHosts) > 0 {
+		report.PiecesReachable++
+	}
+	report.Unrecoverable = len(sa.baseSectorHosts) == 0
+	report.AtRisk = len(sa.baseSectorHosts) <= 1
+
+	// Every chunk of the fanout needs at least FanoutDataPieces pieces to be
+	// recoverable. If a chunk has no additional pieces available, losing a
+	// single host might render it unrecoverable.
+	minPieces := int(sa.layout.FanoutDataPieces)
+	for _, goodPieces := range sa.chunkGoodPieces() {
+		report.PiecesNeeded += uint64(minPieces)
+		if goodPieces < minPieces {
+			report.PiecesReachable += uint64(goodPieces)
+			report.Unrecoverable = true
+		} else {
+			report.PiecesReachable += uint64(minPieces)
+		}
+		if goodPieces <= minPieces {
+			report.AtRisk = true
+		}
+	}
+	return report
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
@@ -201,3 +202,264 @@ func TestShortFanoutPanic(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestSkylinkAvailabilityReport is a unit test for turning the availability
+// of a skylink into an AvailabilityReport.
+func TestSkylinkAvailabilityReport(t *testing.T) {
+	t.Parallel()
+
+	// hostSet is a helper to create a set of hosts.
+	hostSet := func(hosts ...string) map[string]struct{} {
+		set := make(map[string]struct{})
+		for _, host := range hosts {
+			set[host] = struct{}{}
+		}
+		return set
+	}
+
+	// Small file without a fanout on a single host.
+	sa := skylinkAvailability{
+		baseSectorHosts: hostSet("h1"),
+	}
+	report := sa.availabilityReport()
+	expected := skymodules.AvailabilityReport{
+		BaseSectorHosts: 1,
+		NumHosts:        1,
+		PiecesNeeded:    1,
+		PiecesReachable: 1,
+		AtRisk:          true,
+	}
+	if report != expected {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// Large file with a 2-of-4 fanout and 2 chunks. The first chunk has 3
+	// pieces available, the second one only 1.
+	sa = skylinkAvailability{
+		layout: skymodules.SkyfileLayout{
+			FanoutDataPieces:   2,
+			FanoutParityPieces: 2,
+			CipherType:         crypto.TypePlain,
+		},
+		baseSectorHosts: hostSet("h1", "h2"),
+		numChunks:       2,
+		rootHosts: []map[string]struct{}{
+			hostSet("h1"), hostSet("h2"), hostSet("h3"), hostSet(),
+			hostSet("h4", "h1"), hostSet(), hostSet(), hostSet(),
+		},
+		rootIndexToChunkIndex: map[int]int{0: 0, 1: 0, 2: 0, 3: 0, 4: 1, 5: 1, 6: 1, 7: 1},
+	}
+	report = sa.availabilityReport()
+	expected = skymodules.AvailabilityReport{
+		BaseSectorHosts: 2,
+		NumHosts:        4,
+		PiecesNeeded:    5,
+		PiecesReachable: 4,
+		AtRisk:          true,
+		Unrecoverable:   true,
+	}
+	if report != expected {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// Make the second chunk fully available.
+	sa.rootHosts[5] = hostSet("h5")
+	sa.rootHosts[6] = hostSet("h6")
+	report = sa.availabilityReport()
+	expected = skymodules.AvailabilityReport{
+		BaseSectorHosts: 2,
+		NumHosts:        6,
+		PiecesNeeded:    5,
+		PiecesReachable: 5,
+	}
+	if report != expected {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+// TestRenterPinnedSkyfiles verifies that PinnedSkyfiles only returns the
+// skyfiles within the skynet folder.
+func TestRenterPinnedSkyfiles(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rt.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Create a skyfile, a file without a skylink in the skynet folder and a
+	// file with a skylink outside of the skynet folder.
+	rsc, err := skymodules.NewRSSubCode(2, 4, crypto.SegmentSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skylink, err := skymodules.NewSkylinkV1(crypto.Hash{1}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skyfilePath, err := skymodules.SkynetFolder.Join(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	noSkylinkPath, err := skymodules.SkynetFolder.Join(t.Name() + "-noskylink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sp := range []skymodules.SiaPath{skyfilePath, noSkylinkPath, skymodules.RandomSiaPath()} {
+		entry, err := rt.renter.createRenterTestFileWithParams(sp, rsc, crypto.TypePlain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sp.Equals(noSkylinkPath) {
+			if err := entry.AddSkylink(skylink); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := entry.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pinned, err := rt.renter.PinnedSkyfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pinned) != 1 {
+		t.Fatal("expected 1 pinned skyfile, got", len(pinned))
+	}
+	p := pinned[0]
+	if !p.SiaPath.Equals(skyfilePath) {
+		t.Fatal("wrong siapath", p.SiaPath)
+	}
+	if len(p.Skylinks) != 1 || p.Skylinks[0] != skylink.String() {
+		t.Fatal("wrong skylinks", p.Skylinks)
+	}
+	if p.MinPieces != 2 {
+		t.Fatal("wrong min pieces", p.MinPieces)
+	}
+	if p.MeetsMinRedundancy != !skymodules.NeedsRepair(p.Health) {
+		t.Fatal("wrong min redundancy flag", p)
+	}
+	if p.ReachablePieces != 0 {
+		t.Fatal("pieces without contracts shouldn't be reachable", p.ReachablePieces)
+	}
+
+	// Upload pieces of the skyfile to an online host, an offline host and a
+	// host without a contract.
+	entry, err := rt.renter.staticFileSystem.OpenSiaFile(skyfilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := entry.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	onlineHost := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{1}}
+	offlineHost := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{2}}
+	unknownHost := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{3}}
+	offline := map[string]bool{
+		onlineHost.String():  false,
+		offlineHost.String(): true,
+	}
+	goodForRenew := map[string]bool{
+		onlineHost.String():  false,
+		offlineHost.String(): true,
+	}
+	pieces := []types.SiaPublicKey{onlineHost, offlineHost, unknownHost, onlineHost}
+	for pieceIndex, pk := range pieces {
+		if err := entry.AddPiece(pk, 0, uint64(pieceIndex), crypto.Hash{byte(pieceIndex)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reachable := worstChunkReachablePieces(entry, offline, goodForRenew); reachable != 2 {
+		t.Fatal("wrong number of reachable pieces", reachable)
+	}
+
+	// A second chunk without pieces is the worst chunk.
+	if err := entry.GrowNumChunks(2); err != nil {
+		t.Fatal(err)
+	}
+	if reachable := worstChunkReachablePieces(entry, offline, goodForRenew); reachable != 0 {
+		t.Fatal("wrong number of reachable pieces", reachable)
+	}
+}
+
+// TestVerifyDownload is a unit test for VerifyDownload.
+func TestVerifyDownload(t *testing.T) {
+	t.Parallel()
+
+	// Create a small skyfile.
+	fileBytes := fastrand.Bytes(100)
+	metadataBytes, err := skymodules.SkyfileMetadataBytes(skymodules.SkyfileMetadata{
+		Filename: "file",
+		Length:   uint64(len(fileBytes)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl := skymodules.SkyfileLayout{
+		Version:      skymodules.SkyfileVersion,
+		Filesize:     uint64(len(fileBytes)),
+		MetadataSize: uint64(len(metadataBytes)),
+		CipherType:   crypto.TypePlain,
+	}
+	baseSector, fetchSize := skymodules.BuildBaseSector(sl.Encode(), nil, metadataBytes, fileBytes)
+	skylink, err := skymodules.NewSkylinkV1(crypto.MerkleRoot(baseSector), 0, fetchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fetchSize, err = skylink.OffsetAndFetchSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r Renter
+
+	// Both the full sector and the fetched prefix should be valid.
+	if err := r.VerifyDownload(skylink, baseSector); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.VerifyDownload(skylink, baseSector[:fetchSize]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Less data than the fetch size is invalid.
+	if err := r.VerifyDownload(skylink, baseSector[:fetchSize-1]); !errors.Contains(err, ErrSkylinkMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Modified payload.
+	modified := append([]byte{}, baseSector...)
+	modified[skymodules.SkyfileLayoutSize+len(metadataBytes)]++
+	if err := r.VerifyDownload(skylink, modified); !errors.Contains(err, ErrSkylinkMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Data trailing the payload.
+	modified = append([]byte{}, baseSector...)
+	modified[len(modified)-1]++
+	if err := r.VerifyDownload(skylink, modified); !errors.Contains(err, ErrSkylinkMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Garbage.
+	if err := r.VerifyDownload(skylink, fastrand.Bytes(int(fetchSize))); !errors.Contains(err, ErrSkylinkMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// V2 skylinks are rejected.
+	var spk types.SiaPublicKey
+	v2 := skymodules.NewSkylinkV2(spk, crypto.Hash{})
+	if err := r.VerifyDownload(v2, baseSector); !errors.Contains(err, ErrInvalidSkylinkVersion) {
+		t.Fatal("unexpected error", err)
+	}
+}
@@ -103,6 +103,9 @@ func (sds *skylinkDataSource) SilentClose() {
 
 // ReadStream implements streamBufferDataSource
 func (sds *skylinkDataSource) ReadStream(ctx context.Context, off, fetchSize uint64, pricePerMS types.Currency) chan *readResponse {
+	// All chunk downloads of the read share a download id.
+	ctx, _ = contextWithDownloadID(ctx)
+
 	// Prepare the response channel
 	responseChan := make(chan *readResponse, 1)
 	if off+fetchSize > sds.staticLayout.Filesize {
@@ -225,8 +228,10 @@ func (r *Renter) managedDownloadByRoot(ctx context.Context, root crypto.Hash, of
 	defer cancel()
 
 	// Capture the base sector download in a new span.
+	ctx, id := contextWithDownloadID(ctx)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "managedDownloadByRoot")
 	span.SetTag("root", root)
+	span.SetTag("downloadID", id.String())
 	defer span.Finish()
 
 	// Create the pcws for the first chunk. We use a passthrough cipher and
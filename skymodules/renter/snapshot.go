@@ -249,7 +249,7 @@ func (r *Renter) DownloadBackup(dst string, name string) (err error) {
 	if err != nil {
 		return err
 	}
-	s := r.managedStreamer(snap, false)
+	s := r.managedStreamer(snap, defaultStreamerOptions(false))
 	_, err = io.Copy(dstFile, s)
 	return errors.Compose(err, s.Close())
 }
@@ -15,6 +15,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -51,6 +52,10 @@ var (
 	// available.
 	errTimeout = errors.New("could not get data from data section, context timed out")
 
+	// errStreamMemory is returned when the memory for a new stream couldn't be
+	// reserved before the context was canceled.
+	errStreamMemory = errors.New("failed to reserve memory for stream")
+
 	// bytesBufferedPerStream is the total amount of data that gets allocated
 	// per stream. If the RequestSize of a stream buffer is less than three
 	// times the bytesBufferedPerStream, that much data will be allocated
@@ -193,6 +198,10 @@ type stream struct {
 	mu                 sync.Mutex
 	staticStreamBuffer *streamBuffer
 
+	// staticMemory is the memory the stream reserved from the download memory
+	// budget for the data sections of its lru.
+	staticMemory uint64
+
 	staticContext     context.Context
 	staticSpan        opentracing.Span
 	staticReadTimeout time.Duration
@@ -228,16 +237,23 @@ type streamBuffer struct {
 type streamBufferSet struct {
 	streams map[skymodules.DataSourceID]*streamBuffer
 
+	// atomicMemory is the amount of memory that the open streams currently
+	// hold from the memory manager.
+	atomicMemory uint64
+
+	staticMemoryManager  *memoryManager
 	staticStatsCollector *skymodules.DistributionTracker
 	staticTG             *threadgroup.ThreadGroup
 	mu                   sync.Mutex
 }
 
-// newStreamBufferSet initializes and returns a stream buffer set.
-func newStreamBufferSet(statsCollector *skymodules.DistributionTracker, tg *threadgroup.ThreadGroup) *streamBufferSet {
+// newStreamBufferSet initializes and returns a stream buffer set. Every stream
+// reserves the memory for its buffered data from the given memory manager.
+func newStreamBufferSet(statsCollector *skymodules.DistributionTracker, mm *memoryManager, tg *threadgroup.ThreadGroup) *streamBufferSet {
 	return &streamBufferSet{
 		streams: make(map[skymodules.DataSourceID]*streamBuffer),
 
+		staticMemoryManager:  mm,
 		staticStatsCollector: statsCollector,
 		staticTG:             tg,
 	}
@@ -257,7 +273,10 @@ func newStreamBufferSet(statsCollector *skymodules.DistributionTracker, tg *thre
 // Each stream has a separate LRU for determining what data to buffer. Because
 // the LRU is distinct to the stream, the shared cache feature will not result
 // in one stream evicting data from another stream's LRU.
-func (sbs *streamBufferSet) callNewStream(ctx context.Context, dataSource streamBufferDataSource, initialOffset uint64, timeout time.Duration, pricePerMS types.Currency) *stream {
+//
+// The call blocks until the memory for the stream's LRU was reserved from the
+// memory manager or until the context is canceled.
+func (sbs *streamBufferSet) callNewStream(ctx context.Context, dataSource streamBufferDataSource, initialOffset uint64, timeout time.Duration, pricePerMS types.Currency) (*stream, error) {
 	// Grab the streamBuffer for the provided sourceID. If no streamBuffer for
 	// the sourceID exists, create a new one.
 	sourceID := dataSource.ID()
@@ -290,16 +309,17 @@ func (sbs *streamBufferSet) callNewStream(ctx context.Context, dataSource stream
 // buffer exists for the given data source id. If so, a new stream will be
 // created using the data source, and the bool will be set to 'true'. Otherwise,
 // the stream returned will be nil and the bool will be set to 'false'.
-func (sbs *streamBufferSet) callNewStreamFromID(ctx context.Context, id skymodules.DataSourceID, initialOffset uint64, timeout time.Duration) (*stream, bool) {
+func (sbs *streamBufferSet) callNewStreamFromID(ctx context.Context, id skymodules.DataSourceID, initialOffset uint64, timeout time.Duration) (*stream, bool, error) {
 	sbs.mu.Lock()
 	streamBuf, exists := sbs.streams[id]
 	if !exists {
 		sbs.mu.Unlock()
-		return nil, false
+		return nil, false, nil
 	}
 	streamBuf.externRefCount++
 	sbs.mu.Unlock()
-	return streamBuf.managedPrepareNewStream(ctx, initialOffset, timeout), true
+	stream, err := streamBuf.managedPrepareNewStream(ctx, initialOffset, timeout)
+	return stream, true, err
 }
 
 // managedData will block until the data for a data section is available, and
@@ -361,6 +381,10 @@ func (s *stream) Close() error {
 
 		// Remove the stream from the streamBuffer.
 		sbs.managedRemoveStream(sb)
+
+		// Return the stream's memory.
+		sbs.staticMemoryManager.Return(s.staticMemory)
+		atomic.AddUint64(&sbs.atomicMemory, ^uint64(s.staticMemory-1))
 	})
 	return nil
 }
@@ -574,25 +598,46 @@ func (sb *streamBuffer) callRemoveDataSection(index uint64) {
 // managedPrepareNewStream creates a new stream from an existing stream buffer.
 // The ref count for the buffer needs to be incremented under the
 // streamBufferSet lock, before this method is called.
-func (sb *streamBuffer) managedPrepareNewStream(ctx context.Context, initialOffset uint64, timeout time.Duration) *stream {
+//
+// The stream reserves enough memory for all of the data sections its lru may
+// hold upfront. Reserving the memory for each data section separately could
+// deadlock once the budget is exhausted by the data sections that the streams
+// already read but still hold in their lrus. The lru never holds more than the
+// data of the stream, so streams of small files only reserve the size of the
+// file.
+func (sb *streamBuffer) managedPrepareNewStream(ctx context.Context, initialOffset uint64, timeout time.Duration) (*stream, error) {
+	sbs := sb.staticStreamBufferSet
+
 	// Determine how many data sections the stream should cache.
 	dataSectionsToCache := bytesBufferedPerStream / sb.staticDataSectionSize
 	if dataSectionsToCache < minimumDataSections {
 		dataSectionsToCache = minimumDataSections
 	}
 
+	// Reserve the memory for the data sections.
+	memory := dataSectionsToCache * sb.staticDataSectionSize
+	if memory > sb.staticDataSize {
+		memory = sb.staticDataSize
+	}
+	if !sbs.staticMemoryManager.Request(ctx, memory, memoryPriorityLow) {
+		sbs.managedRemoveStream(sb)
+		return nil, errStreamMemory
+	}
+	atomic.AddUint64(&sbs.atomicMemory, memory)
+
 	// Create a stream that points to the stream buffer.
 	stream := &stream{
 		lru:    newLeastRecentlyUsedCache(dataSectionsToCache, sb),
 		offset: initialOffset,
 
+		staticMemory:       memory,
 		staticContext:      sb.staticTG.StopCtx(),
 		staticReadTimeout:  timeout,
 		staticStreamBuffer: sb,
 		staticSpan:         opentracing.SpanFromContext(ctx),
 	}
 	stream.prepareOffset()
-	return stream
+	return stream, nil
 }
 
 // newDataSection will create a new data section for the streamBuffer and spin
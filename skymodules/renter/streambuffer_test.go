@@ -7,14 +7,17 @@ import (
 	"io"
 	"io/ioutil"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/types"
 
+	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
 	"gitlab.com/NebulousLabs/threadgroup"
 )
@@ -134,8 +137,11 @@ func TestStreamSmoke(t *testing.T) {
 	dataSectionSize := uint64(16)
 	dataSource := newMockDataSource(data, dataSectionSize)
 	dt := skymodules.NewDistributionTrackerStandard()
-	sbs := newStreamBufferSet(dt, &tg)
-	stream := sbs.callNewStream(ctx, dataSource, 0, 0, types.ZeroCurrency)
+	sbs := newStreamBufferSet(dt, newMemoryManager(1<<30, 0, tg.StopChan()), &tg)
+	stream, err := sbs.callNewStream(ctx, dataSource, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Check that there is one reference in the stream buffer.
 	sbs.mu.Lock()
@@ -145,7 +151,10 @@ func TestStreamSmoke(t *testing.T) {
 		t.Fatal("bad")
 	}
 	// Create a new stream from an id, check that the ref count goes up.
-	streamFromID, exists := sbs.callNewStreamFromID(ctx, dataSource.ID(), 0, 0)
+	streamFromID, exists, err := sbs.callNewStreamFromID(ctx, dataSource.ID(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if !exists {
 		t.Fatal("bad")
 	}
@@ -158,14 +167,17 @@ func TestStreamSmoke(t *testing.T) {
 	// Create a second, different data source with the same id and try to use
 	// that.
 	dataSource2 := newMockDataSource(data, dataSectionSize)
-	repeatStream := sbs.callNewStream(ctx, dataSource2, 0, 0, types.ZeroCurrency)
+	repeatStream, err := sbs.callNewStream(ctx, dataSource2, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
 	sbs.mu.Lock()
 	refs = stream.staticStreamBuffer.externRefCount
 	sbs.mu.Unlock()
 	if refs != 3 {
 		t.Fatal("bad")
 	}
-	err := repeatStream.Close()
+	err = repeatStream.Close()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -329,7 +341,10 @@ func TestStreamSmoke(t *testing.T) {
 	// the same ID, they are actually separate objects which need to be closed
 	// individually.
 	dataSource3 := newMockDataSource(data, dataSectionSize)
-	stream2 := sbs.callNewStream(ctx, dataSource3, 0, 0, types.ZeroCurrency)
+	stream2, err := sbs.callNewStream(ctx, dataSource3, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
 	bytesRead, err = io.ReadFull(stream2, buf)
 	if err != nil {
 		t.Fatal(err)
@@ -457,7 +472,10 @@ func TestStreamSmoke(t *testing.T) {
 
 	// Check that if the tg is stopped, the stream closes immediately.
 	dataSource4 := newMockDataSource(data, dataSectionSize)
-	stream3 := sbs.callNewStream(ctx, dataSource4, 0, 0, types.ZeroCurrency)
+	stream3, err := sbs.callNewStream(ctx, dataSource4, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
 	bytesRead, err = io.ReadFull(stream3, buf)
 	if err != nil {
 		t.Fatal(err)
@@ -501,3 +519,160 @@ func TestStreamSmoke(t *testing.T) {
 		t.Fatal("bad")
 	}
 }
+
+// TestStreamBufferMemory checks that streams reserve the memory for their
+// buffered data from the memory manager and return it once they are closed.
+func TestStreamBufferMemory(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a bg context with a testSpan
+	ctx := opentracing.ContextWithSpan(context.Background(), testSpan())
+
+	// Create a stream buffer set with a memory budget that only fits a single
+	// stream.
+	var tg threadgroup.ThreadGroup
+	data := fastrand.Bytes(15999)
+	dataSectionSize := uint64(16)
+	streamMemory := bytesBufferedPerStream / dataSectionSize * dataSectionSize
+	dt := skymodules.NewDistributionTrackerStandard()
+	mm := newMemoryManager(streamMemory, 0, tg.StopChan())
+	sbs := newStreamBufferSet(dt, mm, &tg)
+
+	// Create a stream. It should hold the memory for its lru.
+	dataSource := newMockDataSource(data, dataSectionSize)
+	stream, err := sbs.callNewStream(ctx, dataSource, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mem := atomic.LoadUint64(&sbs.atomicMemory); mem != streamMemory {
+		t.Fatal("wrong amount of memory", mem, streamMemory)
+	}
+
+	// Creating another stream should time out since there is no memory left.
+	// The failed stream's buffer should be removed and its data source closed.
+	dataSource2 := newMockDataSource(data, dataSectionSize)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = sbs.callNewStream(timeoutCtx, dataSource2, 0, 0, types.ZeroCurrency)
+	if !errors.Contains(err, errStreamMemory) {
+		t.Fatal("expected errStreamMemory", err)
+	}
+	sbs.mu.Lock()
+	_, exists := sbs.streams[dataSource2.ID()]
+	sbs.mu.Unlock()
+	if exists {
+		t.Fatal("stream buffer wasn't removed")
+	}
+	dataSource2.mu.Lock()
+	closed := dataSource2.data == nil
+	dataSource2.mu.Unlock()
+	if !closed {
+		t.Fatal("data source wasn't closed")
+	}
+
+	// The first stream can still be read.
+	buf := make([]byte, 2*dataSectionSize)
+	_, err = io.ReadFull(stream, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[:len(buf)]) {
+		t.Fatal("wrong data")
+	}
+
+	// Close the stream. Once the stream buffer is evicted, the memory should
+	// be returned.
+	err = stream.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		if mem := atomic.LoadUint64(&sbs.atomicMemory); mem != 0 {
+			return fmt.Errorf("streams still hold %v bytes", mem)
+		}
+		if status := mm.callStatus(); status.PriorityAvailable != status.PriorityBase {
+			return fmt.Errorf("memory wasn't returned: %v", status)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tg.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStreamBufferMemorySmallFiles verifies that streams of small files only
+// reserve the memory for the file and not for a full lru, so that the budget
+// for one large stream fits many small ones.
+func TestStreamBufferMemorySmallFiles(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// Create a bg context with a testSpan
+	ctx := opentracing.ContextWithSpan(context.Background(), testSpan())
+
+	// Create a stream buffer set with a memory budget that fits 16 streams of
+	// large files.
+	var tg threadgroup.ThreadGroup
+	dataSectionSize := uint64(16)
+	streamMemory := bytesBufferedPerStream / dataSectionSize * dataSectionSize
+	budget := 16 * streamMemory
+	dt := skymodules.NewDistributionTrackerStandard()
+	mm := newMemoryManager(budget, 0, tg.StopChan())
+	sbs := newStreamBufferSet(dt, mm, &tg)
+
+	// Open more than 16 streams of small files concurrently. None of them
+	// should block on the memory.
+	numStreams := 32
+	fileSize := budget / uint64(numStreams) / 2
+	streams := make([]*stream, numStreams)
+	errs := make([]error, numStreams)
+	var wg sync.WaitGroup
+	for i := range streams {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			dataSource := newMockDataSource(fastrand.Bytes(int(fileSize)), dataSectionSize)
+			streams[i], errs[i] = sbs.callNewStream(timeoutCtx, dataSource, 0, 0, types.ZeroCurrency)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mem := atomic.LoadUint64(&sbs.atomicMemory); mem != uint64(numStreams)*fileSize {
+		t.Fatal("wrong amount of memory", mem, uint64(numStreams)*fileSize)
+	}
+
+	// Close the streams. The memory should be returned.
+	for _, stream := range streams {
+		if err := stream.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := build.Retry(100, 100*time.Millisecond, func() error {
+		if mem := atomic.LoadUint64(&sbs.atomicMemory); mem != 0 {
+			return fmt.Errorf("streams still hold %v bytes", mem)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tg.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -29,8 +29,11 @@ func TestStreamLRU(t *testing.T) {
 	data := fastrand.Bytes(15999) // 1 byte short of 1000 data sections.
 	dataSource := newMockDataSource(data, 16)
 	dt := skymodules.NewDistributionTrackerStandard()
-	sbs := newStreamBufferSet(dt, &tg)
-	stream := sbs.callNewStream(ctx, dataSource, 0, 0, types.ZeroCurrency)
+	sbs := newStreamBufferSet(dt, newMemoryManager(1<<30, 0, tg.StopChan()), &tg)
+	stream, err := sbs.callNewStream(ctx, dataSource, 0, 0, types.ZeroCurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Extract the LRU from the stream to test it directly.
 	lru := stream.lru
@@ -358,12 +358,10 @@ func (r *Renter) managedDownloadLogicalChunkDataFromSiaFile(chunk *unfinishedUpl
 		disableLocalFetch: true,
 		file:              snap,
 
-		latencyTarget: 200e3, // No need to rush latency on repair downloads.
 		length:        downloadLength,
 		needsMemory:   false, // We already requested memory, the download memory fits inside of that.
 		offset:        uint64(chunk.offset),
-		overdrive:     0, // No need to rush the latency on repair downloads.
-		priority:      0, // Repair downloads are completely de-prioritized.
+		priorityClass: downloadPriorityClassBackground, // Repair downloads are completely de-prioritized.
 
 		staticMemoryManager:    chunk.staticMemoryManager, // Same memory manager as upload chunk
 		staticSpendingCategory: categoryRepairDownload,
@@ -0,0 +1,160 @@
+package renter
+
+import (
+	"fmt"
+	"sync"
+
+	"gitlab.com/NebulousLabs/errors"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// errInvalidUploadConcurrency is returned if UploadChunkParallel is
+	// called with a concurrency smaller than 1.
+	errInvalidUploadConcurrency = errors.New("upload concurrency must be at least 1")
+
+	// errNotEnoughUploadHosts is returned if there are fewer hosts to upload
+	// to than pieces to upload.
+	errNotEnoughUploadHosts = errors.New("not enough hosts to upload all pieces")
+
+	// errNoHostsLeft is returned for a piece if all hosts were tried or used
+	// by other pieces without successfully uploading the piece.
+	errNoHostsLeft = errors.New("no hosts left to upload piece to")
+)
+
+type (
+	// uploadPieceFunc uploads a piece to the host with the given key and
+	// returns the piece's Merkle root.
+	uploadPieceFunc func(hpk types.SiaPublicKey, piece []byte) (crypto.Hash, error)
+
+	// parallelUploadHosts is the queue of hosts that pieces of a parallel
+	// upload can still be uploaded to. Every host is handed out at most once
+	// so that no host ends up with more than one piece of the chunk.
+	parallelUploadHosts struct {
+		hosts []types.SiaPublicKey
+		mu    sync.Mutex
+	}
+)
+
+// managedNext returns the next host to upload to or false if there are no
+// hosts left.
+func (h *parallelUploadHosts) managedNext() (types.SiaPublicKey, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.hosts) == 0 {
+		return types.SiaPublicKey{}, false
+	}
+	hpk := h.hosts[0]
+	h.hosts = h.hosts[1:]
+	return hpk, true
+}
+
+// UploadChunkParallel uploads the pieces of a chunk to different hosts
+// concurrently. At most concurrency pieces are uploaded at the same time, each
+// via its own session with a host that the renter has a good for upload
+// contract with. If uploading a piece to a host fails, the piece is uploaded
+// to another host instead. The returned slice contains the host and Merkle
+// root for every piece that was uploaded successfully.
+//
+// The pieces are uploaded as they are, so they need to be erasure coded,
+// encrypted and padded to the sector size by the caller.
+func (r *Renter) UploadChunkParallel(pieces [][]byte, concurrency int) ([]skymodules.UploadedPiece, error) {
+	if err := r.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer r.tg.Done()
+
+	if concurrency < 1 {
+		return nil, errInvalidUploadConcurrency
+	}
+	for i, piece := range pieces {
+		if uint64(len(piece)) != modules.SectorSize {
+			return nil, fmt.Errorf("piece %v has size %v but should be %v", i, len(piece), modules.SectorSize)
+		}
+	}
+
+	// Upload to all hosts with a good for upload contract.
+	var hosts []types.SiaPublicKey
+	for _, contract := range r.staticHostContractor.Contracts() {
+		if contract.Utility.GoodForUpload {
+			hosts = append(hosts, contract.HostPublicKey)
+		}
+	}
+	return uploadPiecesParallel(pieces, hosts, concurrency, r.managedUploadPieceToHost)
+}
+
+// managedUploadPieceToHost uploads a single piece to a host using a new
+// session.
+func (r *Renter) managedUploadPieceToHost(hpk types.SiaPublicKey, piece []byte) (_ crypto.Hash, err error) {
+	s, err := r.staticHostContractor.Session(hpk, r.tg.StopChan())
+	if err != nil {
+		return crypto.Hash{}, errors.AddContext(err, "failed to acquire session")
+	}
+	defer func() {
+		err = errors.Compose(err, s.Close())
+	}()
+
+	// Check for price gouging before uploading.
+	err = checkUploadGouging(r.staticHostContractor.Allowance(), s.HostSettings())
+	if err != nil && !r.staticDeps.Disrupt("DisableUploadGouging") {
+		return crypto.Hash{}, errors.AddContext(err, "price gouging detected")
+	}
+	return s.Upload(piece)
+}
+
+// uploadPiecesParallel uploads the pieces to the hosts using the provided
+// upload function with at most concurrency uploads in flight. Every host
+// receives at most one piece. A piece that fails to upload is retried on the
+// next unused host until there are no hosts left.
+func uploadPiecesParallel(pieces [][]byte, hosts []types.SiaPublicKey, concurrency int, upload uploadPieceFunc) ([]skymodules.UploadedPiece, error) {
+	if len(hosts) < len(pieces) {
+		return nil, errNotEnoughUploadHosts
+	}
+	queue := &parallelUploadHosts{
+		hosts: append([]types.SiaPublicKey{}, hosts...),
+	}
+
+	uploaded := make([]skymodules.UploadedPiece, 0, len(pieces))
+	var uploadErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range pieces {
+		wg.Add(1)
+		go func(pieceIndex uint64) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var pieceErr error
+			for {
+				hpk, ok := queue.managedNext()
+				if !ok {
+					pieceErr = errors.Compose(pieceErr, errNoHostsLeft)
+					break
+				}
+				root, err := upload(hpk, pieces[pieceIndex])
+				if err != nil {
+					pieceErr = errors.Compose(pieceErr, errors.AddContext(err, fmt.Sprintf("failed to upload to host %v", hpk)))
+					continue
+				}
+				mu.Lock()
+				uploaded = append(uploaded, skymodules.UploadedPiece{
+					HostPublicKey: hpk,
+					MerkleRoot:    root,
+					PieceIndex:    pieceIndex,
+				})
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			uploadErr = errors.Compose(uploadErr, errors.AddContext(pieceErr, fmt.Sprintf("failed to upload piece %v", pieceIndex)))
+			mu.Unlock()
+		}(uint64(i))
+	}
+	wg.Wait()
+	return uploaded, uploadErr
+}
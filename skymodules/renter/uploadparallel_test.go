@@ -0,0 +1,88 @@
+package renter
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// TestUploadPiecesParallel is a unit test for uploadPiecesParallel.
+func TestUploadPiecesParallel(t *testing.T) {
+	t.Parallel()
+
+	// Create hosts and pieces.
+	hosts := make([]types.SiaPublicKey, 6)
+	for i := range hosts {
+		hosts[i] = types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{byte(i)}}
+	}
+	pieces := [][]byte{{0}, {1}, {2}, {3}}
+	failingHost := hosts[1].String()
+
+	// Create an upload function that fails for one of the hosts and tracks
+	// the number of concurrent uploads.
+	var inFlight, maxInFlight int64
+	var mu sync.Mutex
+	usedHosts := make(map[string]int)
+	upload := func(hpk types.SiaPublicKey, piece []byte) (crypto.Hash, error) {
+		n := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+		mu.Lock()
+		usedHosts[hpk.String()]++
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		if hpk.String() == failingHost {
+			return crypto.Hash{}, errors.New("upload failed")
+		}
+		return crypto.HashBytes(piece), nil
+	}
+
+	// Upload with a concurrency of 2.
+	uploaded, err := uploadPiecesParallel(pieces, hosts, 2, upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploaded) != len(pieces) {
+		t.Fatalf("expected %v pieces but got %v", len(pieces), len(uploaded))
+	}
+	if maxInFlight > 2 {
+		t.Fatal("concurrency exceeded", maxInFlight)
+	}
+	uploadedIndices := make(map[uint64]struct{})
+	for _, up := range uploaded {
+		if up.HostPublicKey.String() == failingHost {
+			t.Fatal("piece shouldn't be on the failing host")
+		}
+		if up.MerkleRoot != crypto.HashBytes(pieces[up.PieceIndex]) {
+			t.Fatal("wrong root")
+		}
+		uploadedIndices[up.PieceIndex] = struct{}{}
+	}
+	if len(uploadedIndices) != len(pieces) {
+		t.Fatal("not every piece was uploaded", uploadedIndices)
+	}
+	for hpk, n := range usedHosts {
+		if n > 1 {
+			t.Fatalf("host %v was used %v times", hpk, n)
+		}
+	}
+
+	// Not enough hosts.
+	_, err = uploadPiecesParallel(pieces, hosts[:3], 2, upload)
+	if !errors.Contains(err, errNotEnoughUploadHosts) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Running out of hosts due to failures.
+	_, err = uploadPiecesParallel(pieces, hosts[:4], 2, upload)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
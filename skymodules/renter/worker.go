@@ -64,6 +64,7 @@ type (
 	worker struct {
 		// Atomics are used to minimize lock contention on the worker object.
 		atomicAccountBalanceCheckRunning uint64         // used for a sanity check
+		atomicBalanceTargetOverride      unsafe.Pointer // points to a types.Currency, overrides the balance target
 		atomicCache                      unsafe.Pointer // points to a workerCache object
 		atomicCacheUpdating              uint64         // ensures only one cache update happens at a time
 		atomicPriceTable                 unsafe.Pointer // points to a workerPriceTable object
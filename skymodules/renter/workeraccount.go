@@ -10,6 +10,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
@@ -604,7 +605,54 @@ func (w *worker) managedNeedsToRefillAccount() bool {
 		return false
 	}
 
-	return w.staticAccount.managedNeedsToRefill(w.staticBalanceTarget.Div64(2))
+	low, _ := w.staticAccountRefillWaterMarks()
+	return w.staticAccount.managedNeedsToRefill(low)
+}
+
+// staticAccountRefillWaterMarks returns the balance below which the worker's
+// account is refilled and the balance it is refilled to. The high water mark is
+// the worker's balance target override if set, otherwise it is taken from the
+// allowance and defaults to the balance target. It never exceeds the host's max
+// ephemeral account balance. The low water mark is taken from the allowance and
+// defaults to half the high water mark.
+func (w *worker) staticAccountRefillWaterMarks() (low, high types.Currency) {
+	// Funding might be disabled.
+	if w.staticBalanceTarget.IsZero() {
+		return types.ZeroCurrency, types.ZeroCurrency
+	}
+	cache := w.staticCache()
+	allowance := cache.staticRenterAllowance
+	high = allowance.AccountRefillHighWaterMark
+	if override := w.staticBalanceTargetOverride(); !override.IsZero() {
+		high = override
+	}
+	if high.IsZero() {
+		high = w.staticBalanceTarget
+	}
+	if maxBalance := cache.staticHostMaxBalance; !maxBalance.IsZero() && high.Cmp(maxBalance) > 0 {
+		high = maxBalance
+	}
+	low = allowance.AccountRefillLowWaterMark
+	if low.IsZero() || low.Cmp(high) >= 0 {
+		low = high.Div64(2)
+	}
+	return low, high
+}
+
+// staticBalanceTargetOverride returns the worker's balance target override. A
+// zero value means that there is no override.
+func (w *worker) staticBalanceTargetOverride() types.Currency {
+	ptr := atomic.LoadPointer(&w.atomicBalanceTargetOverride)
+	if ptr == nil {
+		return types.ZeroCurrency
+	}
+	return *(*types.Currency)(ptr)
+}
+
+// staticSetBalanceTargetOverride sets the worker's balance target override. A
+// zero target removes the override.
+func (w *worker) staticSetBalanceTargetOverride(target types.Currency) {
+	atomic.StorePointer(&w.atomicBalanceTargetOverride, unsafe.Pointer(&target))
 }
 
 // managedNeedsToSyncAccountBalanceToHost returns true if the renter needs to
@@ -628,13 +676,14 @@ func (w *worker) managedRefillAccount() {
 	if w.staticRenter.staticDeps.Disrupt("DisableFunding") {
 		return // don't refill account
 	}
-	// The account balance dropped to below half the balance target, refill. Use
-	// the max expected balance when refilling to avoid exceeding any host
-	// maximums.
+	// The account balance dropped to below the low water mark, refill up to
+	// the high water mark. Use the max expected balance when refilling to
+	// avoid exceeding any host maximums.
+	_, target := w.staticAccountRefillWaterMarks()
 	balance := w.staticAccount.managedMaxExpectedBalance()
 	amount := types.ZeroCurrency
-	if w.staticBalanceTarget.Cmp(balance) > 0 {
-		amount = w.staticBalanceTarget.Sub(balance)
+	if target.Cmp(balance) > 0 {
+		amount = target.Sub(balance)
 	}
 	pt := w.staticPriceTable().staticPriceTable
 
@@ -695,7 +744,7 @@ func (w *worker) managedRefillAccount() {
 	}()
 
 	// check the current price table for gouging errors
-	err = checkFundAccountGouging(w.staticPriceTable().staticPriceTable, w.staticCache().staticRenterAllowance, w.staticBalanceTarget)
+	err = checkFundAccountGouging(w.staticPriceTable().staticPriceTable, w.staticCache().staticRenterAllowance, target)
 	if err != nil {
 		return
 	}
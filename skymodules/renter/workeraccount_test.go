@@ -7,8 +7,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 
 	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
@@ -99,6 +101,69 @@ func TestWorkerAccount(t *testing.T) {
 	})
 }
 
+// TestWorkerAccountRefillWaterMarks verifies that the account refill water
+// marks are taken from the allowance and fall back to the balance target.
+func TestWorkerAccountRefillWaterMarks(t *testing.T) {
+	t.Parallel()
+
+	w := new(worker)
+	w.staticBalanceTarget = types.SiacoinPrecision
+	var maxBalance types.Currency
+	setAllowance := func(low, high types.Currency) {
+		wc := &workerCache{staticRenterAllowance: skymodules.Allowance{
+			AccountRefillLowWaterMark:  low,
+			AccountRefillHighWaterMark: high,
+		}, staticHostMaxBalance: maxBalance}
+		atomic.StorePointer(&w.atomicCache, unsafe.Pointer(wc))
+	}
+	assertWaterMarks := func(expectedLow, expectedHigh types.Currency) {
+		t.Helper()
+		low, high := w.staticAccountRefillWaterMarks()
+		if !low.Equals(expectedLow) || !high.Equals(expectedHigh) {
+			t.Fatalf("expected %v and %v, got %v and %v", expectedLow, expectedHigh, low, high)
+		}
+	}
+	sc := types.SiacoinPrecision
+
+	// no water marks set, the balance target is used
+	setAllowance(types.ZeroCurrency, types.ZeroCurrency)
+	assertWaterMarks(sc.Div64(2), sc)
+
+	// only the high water mark is set
+	setAllowance(types.ZeroCurrency, sc.Mul64(4))
+	assertWaterMarks(sc.Mul64(2), sc.Mul64(4))
+
+	// both water marks are set
+	setAllowance(sc, sc.Mul64(4))
+	assertWaterMarks(sc, sc.Mul64(4))
+
+	// a low water mark above the high water mark is ignored
+	setAllowance(sc.Mul64(2), sc)
+	assertWaterMarks(sc.Div64(2), sc)
+
+	// the worker's override takes precedence over the allowance
+	w.staticSetBalanceTargetOverride(sc.Mul64(6))
+	setAllowance(sc, sc.Mul64(4))
+	assertWaterMarks(sc, sc.Mul64(6))
+
+	// the high water mark is capped at the host's max balance
+	maxBalance = sc.Mul64(3)
+	setAllowance(sc, sc.Mul64(4))
+	assertWaterMarks(sc, sc.Mul64(3))
+	setAllowance(sc.Mul64(4), types.ZeroCurrency)
+	assertWaterMarks(sc.Mul64(3).Div64(2), sc.Mul64(3))
+
+	// removing the override falls back to the allowance
+	w.staticSetBalanceTargetOverride(types.ZeroCurrency)
+	setAllowance(types.ZeroCurrency, sc.Mul64(2))
+	assertWaterMarks(sc, sc.Mul64(2))
+
+	// disabled funding
+	w.staticBalanceTarget = types.ZeroCurrency
+	setAllowance(sc, sc.Mul64(4))
+	assertWaterMarks(types.ZeroCurrency, types.ZeroCurrency)
+}
+
 // testAccountCheckFundAccountGouging checks that `checkFundAccountGouging` is
 // correctly detecting price gouging from a host.
 func testAccountCheckFundAccountGouging(t *testing.T) {
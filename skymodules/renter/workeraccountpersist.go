@@ -18,6 +18,7 @@ package renter
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -52,12 +53,25 @@ var (
 	metadataVersion = persist.MetadataVersionv156
 	metadataSize    = 2*types.SpecifierLen + 1 // 1 byte for 'clean' flag
 
+	// accountsChecksumSize is the size of the accounts checksum that follows
+	// the metadata after an upgrade
+	accountsChecksumSize = 8 + crypto.HashSize
+
 	// Metadata validation errors
 	errWrongHeader  = errors.New("wrong header")
 	errWrongVersion = errors.New("wrong version")
 
 	// Persistence data validation errors
 	errInvalidChecksum = errors.New("invalid checksum")
+
+	// errAccountsChecksumMismatch is returned if the accounts file doesn't
+	// match the checksum that was stored when copying over the upgraded
+	// accounts from the tmp accounts file.
+	errAccountsChecksumMismatch = errors.New("accounts file doesn't match the checksum of the upgraded accounts")
+
+	// errNoAccountsChecksum is returned if the accounts file doesn't contain
+	// the checksum of the upgraded accounts.
+	errNoAccountsChecksum = errors.New("accounts file has no checksum of the upgraded accounts")
 )
 
 type (
@@ -79,6 +93,16 @@ type (
 		Clean   bool
 	}
 
+	// accountsChecksum is written to the accounts file right after the
+	// metadata when the upgraded accounts are copied over from the tmp
+	// accounts file. It covers the accounts that were copied, which allows for
+	// verifying on load that the copy completed. It's cleared when the file is
+	// opened since the accounts are modified from then on.
+	accountsChecksum struct {
+		Size     int64
+		Checksum crypto.Hash
+	}
+
 	// accountPersistence is the account's persistence object which holds all
 	// data that gets persisted for a single account.
 	accountPersistence struct {
@@ -372,6 +396,14 @@ func (am *accountManager) handleInterruptedUpgrade() error {
 	// - the tmp file is dirty, remove it
 	tmpFileMetadata, err := readAccountsMetadata(tmpFile)
 	if err == nil && tmpFileMetadata.Clean {
+		// if the accounts file matches its checksum, the copy completed and
+		// we were interrupted before removing the tmp file, otherwise the
+		// copy is retried from the tmp file
+		err = verifyAccountsChecksum(am.staticFile)
+		if err == nil {
+			return errors.Compose(tmpFile.Close(), r.staticDeps.RemoveFile(tmpFilePath))
+		}
+		r.staticLog.Println("retrying interrupted accounts upgrade:", err)
 		return am.upgradeFromV150ToV156_CopyAccountsFromFile(tmpFile)
 	}
 
@@ -471,6 +503,20 @@ func (am *accountManager) openFile() (bool, error) {
 		am.staticRenter.staticLog.Println("successfully upgraded accounts file from v150 to v156")
 	}
 
+	// Verify the accounts against the checksum of an upgrade and clear it
+	// since the accounts are about to change. If the tmp file was still around,
+	// a mismatch would have been handled by retrying the upgrade above.
+	err = verifyAccountsChecksum(am.staticFile)
+	if err != nil && !errors.Contains(err, errNoAccountsChecksum) {
+		r.staticLog.Println("ERROR: failed to verify upgraded accounts file", err)
+	}
+	if !errors.Contains(err, errNoAccountsChecksum) {
+		_, err = am.staticFile.WriteAt(make([]byte, accountsChecksumSize), int64(metadataSize))
+		if err != nil {
+			return false, errors.AddContext(err, "unable to clear the accounts checksum")
+		}
+	}
+
 	// Whether this is a new file or an existing file, we need to set the header
 	// on the metadata. When opening an account, the header should represent an
 	// unclean shutdown. This will be flipped to a header that represents a
@@ -579,6 +625,47 @@ func (am *accountManager) readAccountAt(offset int64) (*account, error) {
 	return acc, nil
 }
 
+// VerifyIntegrity reads every account slot of the accounts file and validates
+// its checksum. It returns the offsets of the accounts that are corrupt. Unlike
+// load, which skips corrupt accounts silently apart from a log line, this can
+// be called at any time to detect corruption on disk before an unclean
+// shutdown resets the balances and masks it. A corrupt account that is loaded
+// is rewritten the next time it is persisted.
+func (am *accountManager) VerifyIntegrity() ([]int64, error) {
+	// Map the offsets to the loaded accounts, so we can lock an account while
+	// reading its slot. That way we don't read a slot that is being written.
+	am.mu.Lock()
+	accounts := make(map[int64]*account, len(am.accounts))
+	for _, acc := range am.accounts {
+		accounts[acc.staticOffset] = acc
+	}
+	am.mu.Unlock()
+
+	var corrupt []int64
+	accountBytes := make([]byte, accountSize)
+	for offset := int64(accountsOffset); ; offset += accountSize {
+		acc, loaded := accounts[offset]
+		if loaded {
+			acc.mu.Lock()
+		}
+		_, err := am.staticFile.ReadAt(accountBytes, offset)
+		if loaded {
+			acc.mu.Unlock()
+		}
+		if errors.Contains(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, errors.AddContext(err, fmt.Sprintf("failed to read account at offset %v", offset))
+		}
+
+		var accountData accountPersistence
+		if err := accountData.loadBytes(accountBytes); err != nil {
+			corrupt = append(corrupt, offset)
+		}
+	}
+	return corrupt, nil
+}
+
 // upgradeFromV150ToV156 is compat code that upgrades the accounts file from
 // v150 to v156. The new accounts take up more space on disk, so we have to read
 // all of them, assign them new offets and rewrite them to the accounts file.
@@ -636,31 +723,67 @@ func (am *accountManager) upgradeFromV150ToV156() error {
 // file into the accounts file. This is a separate method as this function is
 // called during the happy flow, but it is also potentially the steps required
 // when trying to recover from a failed initial update attempt.
-func (am *accountManager) upgradeFromV150ToV156_CopyAccountsFromFile(tmpFile modules.File) (err error) {
+func (am *accountManager) upgradeFromV150ToV156_CopyAccountsFromFile(tmpFile modules.File) error {
 	// convenience variables
 	r := am.staticRenter
 	tmpFilePath := filepath.Join(r.persistDir, accountsTmpFilename)
 
+	// copy the accounts, if that fails the tmp file is kept around and the
+	// copy is retried from there on the next startup
+	err := am.copyAccountsFromFile(tmpFile)
+	if err != nil {
+		return errors.Compose(err, tmpFile.Close())
+	}
+
+	// delete the tmp file
+	return errors.AddContext(errors.Compose(tmpFile.Close(), r.staticDeps.RemoveFile(tmpFilePath)), "failed to delete accounts file")
+}
+
+// copyAccountsFromFile copies the contents of the tmp file into the accounts
+// file and verifies the copy using the checksum it stores in the accounts
+// file.
+func (am *accountManager) copyAccountsFromFile(tmpFile modules.File) error {
 	// copy the tmp file to the accounts file
-	_, err = io.Copy(am.staticFile, tmpFile)
+	var src io.Reader = tmpFile
+	interrupt := am.staticRenter.staticDeps.Disrupt("InterruptAccountsUpgradeCopy")
+	if interrupt {
+		// only copy the metadata and the first account to simulate a crash in
+		// the middle of the copy
+		src = io.LimitReader(tmpFile, accountsOffset+accountSize)
+	}
+	_, err := io.Copy(am.staticFile, src)
 	if err != nil {
 		return errors.AddContext(err, "failed to copy the temporary accounts file to the actual accounts file location")
 	}
+	if interrupt {
+		return errors.New("accounts upgrade interrupted while copying")
+	}
 
-	// sync the accounts file
+	// store the checksum of the copied accounts in the accounts file and sync
+	// it, on the next startup it tells us whether the copy completed
+	err = writeAccountsChecksum(am.staticFile, tmpFile)
+	if err != nil {
+		return errors.AddContext(err, "failed to write accounts checksum")
+	}
 	err = am.staticFile.Sync()
 	if err != nil {
 		return errors.AddContext(err, "failed to sync accounts file")
 	}
 
+	// verify the copy before deleting the tmp file, if the accounts file
+	// doesn't match the tmp file is still around on the next startup and the
+	// copy is retried from there
+	err = verifyAccountsChecksum(am.staticFile)
+	if err != nil {
+		return errors.AddContext(err, "failed to verify the accounts file")
+	}
+
 	// seek to the beginning of the file
 	_, err = am.staticFile.Seek(0, io.SeekStart)
 	if err != nil {
 		return errors.AddContext(err, "failed to seek to the beginning of the accounts file")
 	}
-
-	// delete the tmp file
-	return errors.AddContext(errors.Compose(tmpFile.Close(), r.staticDeps.RemoveFile(tmpFilePath)), "failed to delete accounts file")
+	return nil
 }
 
 // updateMetadata writes the given metadata to the accounts file.
@@ -669,6 +792,79 @@ func (am *accountManager) updateMetadata(meta accountsMetadata) error {
 	return err
 }
 
+// writeAccountsChecksum computes the checksum of the accounts in the tmp
+// accounts file and writes it to the accounts file after the metadata.
+func writeAccountsChecksum(accountsFile, tmpFile modules.File) error {
+	tmpStat, err := tmpFile.Stat()
+	if err != nil {
+		return errors.AddContext(err, "failed to stat tmp file")
+	}
+	size := tmpStat.Size()
+	checksum, err := fileChecksum(tmpFile, size)
+	if err != nil {
+		return errors.AddContext(err, "failed to compute checksum of tmp file")
+	}
+	_, err = accountsFile.WriteAt(encoding.Marshal(accountsChecksum{
+		Size:     size,
+		Checksum: checksum,
+	}), int64(metadataSize))
+	return err
+}
+
+// verifyAccountsChecksum verifies the accounts in the given accounts file
+// against the checksum that was stored after copying the upgraded accounts.
+// It returns errNoAccountsChecksum if there is no checksum.
+func verifyAccountsChecksum(file modules.File) error {
+	// read the checksum
+	buffer := make([]byte, accountsChecksumSize)
+	_, err := file.ReadAt(buffer, int64(metadataSize))
+	if errors.Contains(err, io.EOF) {
+		return errNoAccountsChecksum
+	} else if err != nil {
+		return errors.AddContext(err, "failed to read accounts checksum")
+	}
+	var stored accountsChecksum
+	err = encoding.Unmarshal(buffer, &stored)
+	if err != nil {
+		return errors.AddContext(err, "failed to decode accounts checksum")
+	}
+	if stored.Size == 0 {
+		return errNoAccountsChecksum
+	}
+
+	// a file that is shorter than the copied accounts is half-written
+	stat, err := file.Stat()
+	if err != nil {
+		return errors.AddContext(err, "failed to stat accounts file")
+	}
+	if stat.Size() < stored.Size {
+		return errAccountsChecksumMismatch
+	}
+	checksum, err := fileChecksum(file, stored.Size)
+	if err != nil {
+		return errors.AddContext(err, "failed to compute checksum of accounts file")
+	}
+	if checksum != stored.Checksum {
+		return errAccountsChecksumMismatch
+	}
+	return nil
+}
+
+// fileChecksum returns the checksum of the accounts within the first size
+// bytes of the given file.
+func fileChecksum(file modules.File, size int64) (crypto.Hash, error) {
+	h := crypto.NewHash()
+	if size > accountsOffset {
+		_, err := io.Copy(h, io.NewSectionReader(file, accountsOffset, size-accountsOffset))
+		if err != nil {
+			return crypto.Hash{}, err
+		}
+	}
+	var checksum crypto.Hash
+	copy(checksum[:], h.Sum(nil))
+	return checksum, nil
+}
+
 // compatV150ReadAccounts is a helper function that reads the accounts from the
 // accounts file assuming they are persisted using the v150 persistence object
 // and parameters. Extracted to keep the compat code clean.
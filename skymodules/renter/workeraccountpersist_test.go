@@ -2,6 +2,7 @@ package renter
 
 import (
 	"bytes"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -258,6 +259,75 @@ func TestAccountCorrupted(t *testing.T) {
 	am.mu.Unlock()
 }
 
+// TestAccountVerifyIntegrity verifies that corrupt accounts are detected by
+// VerifyIntegrity and that persisting a corrupt account repairs it.
+func TestAccountVerifyIntegrity(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+
+	// create a renter
+	rt, err := newRenterTester(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := rt.Close()
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+	r := rt.renter
+	am := r.staticAccountManager
+
+	// create a number accounts
+	accounts, err := openRandomTestAccountsOnRenter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// persist the accounts and verify none of them is corrupt
+	for _, account := range accounts {
+		if err := account.managedPersist(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	corrupt, err := am.VerifyIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 0 {
+		t.Fatal("unexpected corrupt accounts", corrupt)
+	}
+
+	// corrupt one of the accounts on disk
+	corrupted := accounts[0]
+	_, err = am.staticFile.WriteAt(fastrand.Bytes(8), corrupted.staticOffset+crypto.HashSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt, err = am.VerifyIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 1 || corrupt[0] != corrupted.staticOffset {
+		t.Fatal("corrupt account wasn't detected", corrupt)
+	}
+
+	// persisting the account should repair it
+	if err := corrupted.managedPersist(); err != nil {
+		t.Fatal(err)
+	}
+	corrupt, err = am.VerifyIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 0 {
+		t.Fatal("corrupt account wasn't repaired", corrupt)
+	}
+}
+
 // TestAccountCompatV150 is a unit test that verifies the compatibility code
 // added to ensure the accounts file is properly upgraded from v1.5.0 to v1.5.6
 func TestAccountCompatV150(t *testing.T) {
@@ -288,6 +358,12 @@ func TestAccountCompatV150(t *testing.T) {
 	t.Run("RecoveryFromDirtyTmpFile", func(t *testing.T) {
 		testAccountCompatV150_TmpFileExistsWithClean(t, rt, false)
 	})
+	t.Run("RecoveryFromInterruptedCopy", func(t *testing.T) {
+		testAccountCompatV150_InterruptedCopy(t)
+	})
+	t.Run("RecoveryFromCorruptCopy", func(t *testing.T) {
+		testAccountCompatV150_CorruptCopy(t)
+	})
 }
 
 // testAccountCompatV150Basic verifies the accounts compat code successfully
@@ -425,6 +501,222 @@ func testAccountCompatV150_TmpFileExistsWithClean(t *testing.T, rt *renterTester
 	}
 }
 
+// testAccountCompatV150_InterruptedCopy verifies the disaster recovery flow in
+// the accounts compat code where an earlier attempt crashed while copying the
+// clean tmp accounts file over the accounts file, leaving behind a half-written
+// accounts file that already contains the upgraded metadata.
+func testAccountCompatV150_InterruptedCopy(t *testing.T) {
+	// create a renter tester without renter
+	testdir := build.TempDir("renter", t.Name())
+	rt, err := newRenterTesterNoRenter(testdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := rt.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// create the renter dir
+	renterDir := filepath.Join(testdir, skymodules.RenterDir)
+	err = os.MkdirAll(renterDir, persist.DefaultDiskPermissionsTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// copy the compat file to the accounts file
+	accountsPath := filepath.Join(renterDir, accountsFilename)
+	err = build.CopyFile("../../compatibility/accounts_v1.5.0.dat", accountsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a renter that crashes in the middle of copying the upgraded
+	// accounts
+	deps := dependencies.NewDependencyInterruptAccountsUpgradeCopy()
+	deps.Fail()
+	_, err = newRenterWithDependency(rt.gateway, rt.cs, rt.wallet, rt.tpool, rt.mux, renterDir, deps)
+	if err == nil {
+		t.Fatal("expected the upgrade to be interrupted")
+	}
+
+	// the tmp file should still be around and the half-written accounts file
+	// should have no checksum
+	tmpPath := filepath.Join(renterDir, accountsTmpFilename)
+	exists, err := fileExists(tmpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatal("tmp file should not have been removed")
+	}
+	accountsFile, err := os.Open(accountsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifyAccountsChecksum(accountsFile)
+	if !errors.Contains(err, errNoAccountsChecksum) {
+		t.Fatal("unexpected error", err)
+	}
+	err = accountsFile.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a renter
+	r, err := newRenterWithDependency(rt.gateway, rt.cs, rt.wallet, rt.tpool, rt.mux, renterDir, &skymodules.SkynetDependencies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add it to the renter tester
+	err = rt.addRenter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify all accounts were recovered from the tmp file
+	am := r.staticAccountManager
+	am.mu.Lock()
+	numAccounts := len(am.accounts)
+	am.mu.Unlock()
+	if numAccounts != 377 {
+		t.Fatal("unexpected amount of accounts", numAccounts)
+	}
+
+	// verify the tmp file was removed
+	exists, err = fileExists(tmpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("tmp file should have been removed")
+	}
+
+	// verify the checksum was cleared
+	err = verifyAccountsChecksum(am.staticFile)
+	if !errors.Contains(err, errNoAccountsChecksum) {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+// testAccountCompatV150_CorruptCopy verifies the disaster recovery flow in the
+// accounts compat code where the accounts file doesn't match the checksum that
+// was stored after copying the clean tmp accounts file over it.
+func testAccountCompatV150_CorruptCopy(t *testing.T) {
+	// create a renter tester without renter
+	testdir := build.TempDir("renter", t.Name())
+	rt, err := newRenterTesterNoRenter(testdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := rt.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// create the renter dir
+	renterDir := filepath.Join(testdir, skymodules.RenterDir)
+	err = os.MkdirAll(renterDir, persist.DefaultDiskPermissionsTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read the tmp file and mark it clean
+	tmpBytes, err := ioutil.ReadFile("../../compatibility/accounts_v1.5.6.tmp.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(tmpBytes, encoding.Marshal(accountsMetadata{
+		Header:  metadataHeader,
+		Version: metadataVersion,
+		Clean:   true,
+	}))
+	tmpPath := filepath.Join(renterDir, accountsTmpFilename)
+	err = ioutil.WriteFile(tmpPath, tmpBytes, skymodules.DefaultFilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// write a full copy including the checksum to the accounts file
+	accountsPath := filepath.Join(renterDir, accountsFilename)
+	err = ioutil.WriteFile(accountsPath, tmpBytes, skymodules.DefaultFilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountsFile, err := os.OpenFile(accountsPath, os.O_RDWR, skymodules.DefaultFilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpFile, err := os.Open(tmpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writeAccountsChecksum(accountsFile, tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifyAccountsChecksum(accountsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt the first account, the file should fail verification
+	_, err = accountsFile.WriteAt(fastrand.Bytes(accountSize), accountsOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = verifyAccountsChecksum(accountsFile)
+	if !errors.Contains(err, errAccountsChecksumMismatch) {
+		t.Fatal("unexpected error", err)
+	}
+	err = errors.Compose(accountsFile.Close(), tmpFile.Close())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create a renter
+	r, err := newRenterWithDependency(rt.gateway, rt.cs, rt.wallet, rt.tpool, rt.mux, renterDir, &skymodules.SkynetDependencies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// add it to the renter tester
+	err = rt.addRenter(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the copy was retried from the tmp file
+	am := r.staticAccountManager
+	am.mu.Lock()
+	numAccounts := len(am.accounts)
+	am.mu.Unlock()
+	if numAccounts != 377 {
+		t.Fatal("unexpected amount of accounts", numAccounts)
+	}
+	corrupt, err := am.VerifyIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 0 {
+		t.Fatal("unexpected corrupt accounts", corrupt)
+	}
+
+	// verify the tmp file was removed
+	exists, err := fileExists(tmpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Fatal("tmp file should have been removed")
+	}
+}
+
 // TestAccountPersistenceToAndFromBytes verifies the functionality of the
 // `bytes` and `loadBytes` method on the accountPersistence object
 func TestAccountPersistenceToAndFromBytes(t *testing.T) {
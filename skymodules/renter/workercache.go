@@ -34,6 +34,10 @@ type (
 		staticMaliciousHost   bool
 		staticSynced          bool
 
+		// staticHostMaxBalance is the max ephemeral account balance of the
+		// host.
+		staticHostMaxBalance types.Currency
+
 		staticLastUpdate time.Time
 	}
 )
@@ -89,6 +93,7 @@ func (w *worker) managedUpdateCache() {
 		staticHostVersion:     host.Version,
 		staticRenterAllowance: w.staticRenter.staticHostContractor.Allowance(),
 		staticSynced:          w.staticRenter.staticConsensusSet.Synced(),
+		staticHostMaxBalance:  host.MaxEphemeralAccountBalance,
 
 		staticLastUpdate: time.Now(),
 	}
@@ -62,6 +62,13 @@ func sectorOffsetAndLength(chunkFetchOffset, chunkFetchLength uint64, rs skymodu
 // worker gains more modification actions on the host, this check can be split
 // into different checks that vary based on the operation being performed.
 func checkDownloadGouging(allowance skymodules.Allowance, pt *modules.RPCPriceTable) error {
+	return checkDownloadGougingWithFraction(allowance, pt, downloadGougingFractionDenom)
+}
+
+// checkDownloadGougingWithFraction is checkDownloadGouging with a custom
+// denominator for the fraction of the expected download that the allowance
+// needs to be able to pay for.
+func checkDownloadGougingWithFraction(allowance skymodules.Allowance, pt *modules.RPCPriceTable, fractionDenom uint64) error {
 	// Check whether the base RPC price is too high.
 	rpcCost := modules.MDMReadCost(pt, skymodules.StreamDownloadSize)
 	if !allowance.MaxRPCPrice.IsZero() && allowance.MaxRPCPrice.Cmp(rpcCost) < 0 {
@@ -91,7 +98,7 @@ func checkDownloadGouging(allowance skymodules.Allowance, pt *modules.RPCPriceTa
 	singleDownloadCost := rpcCost.Add(pt.DownloadBandwidthCost.Mul64(skymodules.StreamDownloadSize))
 	fullCostPerByte := singleDownloadCost.Div64(skymodules.StreamDownloadSize)
 	allowanceDownloadCost := fullCostPerByte.Mul64(allowance.ExpectedDownload)
-	reducedCost := allowanceDownloadCost.Div64(downloadGougingFractionDenom)
+	reducedCost := allowanceDownloadCost.Div64(fractionDenom)
 	if reducedCost.Cmp(allowance.Funds) > 0 {
 		errStr := fmt.Sprintf("combined download pricing of host yields %v, which is more than the renter is willing to pay for the download: %v - price gouging protection enabled", reducedCost, allowance.Funds)
 		return errors.New(errStr)
@@ -104,6 +111,9 @@ func checkDownloadGouging(allowance skymodules.Allowance, pt *modules.RPCPriceTa
 // it to be done and try to recover the logical data of the chunk if possible.
 func (w *worker) threadedPerformDownloadChunkJob(udc *unfinishedDownloadChunk) {
 	if err := w.staticRenter.tg.Add(); err != nil {
+		udc.mu.Lock()
+		udc.releasePriorityHost(w.staticHostPubKeyStr)
+		udc.mu.Unlock()
 		return
 	}
 	defer w.staticRenter.tg.Done()
@@ -123,7 +133,7 @@ func (w *worker) threadedPerformDownloadChunkJob(udc *unfinishedDownloadChunk) {
 
 	// Before performing the download, check for price gouging.
 	allowance := w.staticRenter.staticHostContractor.Allowance()
-	err := checkDownloadGouging(allowance, &w.staticPriceTable().staticPriceTable)
+	err := checkDownloadGougingWithFraction(allowance, &w.staticPriceTable().staticPriceTable, udc.staticClassParams.gougingFractionDenom)
 	if err != nil {
 		w.staticRenter.staticLog.Debugf("worker %v downloader is not being used because price gouging was detected: %v", w.staticHostPubKeyStr, err)
 		udc.managedUnregisterWorker(w)
@@ -134,7 +144,11 @@ func (w *worker) threadedPerformDownloadChunkJob(udc *unfinishedDownloadChunk) {
 	// unregistered with the chunk.
 	fetchOffset, fetchLength := sectorOffsetAndLength(udc.staticFetchOffset, udc.staticFetchLength, udc.erasureCode)
 	root := udc.staticChunkMap[w.staticHostPubKey.String()].root
-	pieceData, err := w.ReadSectorLowPrio(w.staticRenter.tg.StopCtx(), udc.staticSpendingCategory, root, fetchOffset, fetchLength)
+	readSector := w.ReadSector
+	if udc.staticClassParams.lowPrio {
+		readSector = w.ReadSectorLowPrio
+	}
+	pieceData, err := readSector(w.staticRenter.tg.StopCtx(), udc.staticSpendingCategory, root, fetchOffset, fetchLength)
 	if err != nil {
 		w.staticRenter.staticLog.Debugf("worker %v failed to download sector: %v", w.staticHostPubKeyStr, err)
 		udc.managedUnregisterWorker(w)
@@ -201,6 +215,7 @@ func (udc *unfinishedDownloadChunk) managedUnregisterWorker(w *worker) {
 	udc.mu.Lock()
 	udc.piecesRegistered--
 	udc.pieceUsage[udc.staticChunkMap[w.staticHostPubKey.String()].index] = false
+	udc.releasePriorityHost(w.staticHostPubKeyStr)
 	udc.mu.Unlock()
 }
 
@@ -221,6 +236,7 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	pieceData, workerHasPiece := udc.staticChunkMap[w.staticHostPubKey.String()]
 	pieceCompleted := udc.completedPieces[pieceData.index]
 	if chunkComplete || chunkFailed || onCooldown || !workerHasPiece || pieceCompleted {
+		udc.releasePriorityHost(w.staticHostPubKeyStr)
 		udc.mu.Unlock()
 		udc.managedRemoveWorker()
 
@@ -257,7 +273,14 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	// metrics, so that we can avoid holding the worker lock and the udc lock
 	// simultaneously (deadlock risk). The 'owned' variables of the worker are
 	// variables that are only accessed by the master worker thread.
-	meetsExtraCriteria := true
+	//
+	// Workers that chronically fail their read jobs are put on standby as
+	// well. The read stats are thread safe and don't require the worker lock.
+	// Once the chunk needed its standby workers, the extra criteria are
+	// relaxed.
+	meetsExtraCriteria := udc.criteriaRelaxed ||
+		(udc.meetsStickyCriteria(w.staticHostPubKeyStr) &&
+			!w.staticJobReadQueue.staticStats.callIsChronicallyFailing())
 
 	// TODO: There's going to need to be some method for relaxing criteria after
 	// the first wave of workers are sent off. If the first waves of workers
@@ -271,11 +294,20 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	// number of overdrive workers (typically zero). For our purposes, completed
 	// pieces count as active workers, though the workers have actually
 	// finished.
+	//
+	// Workers of pieces that aren't prioritized leave room for the priority
+	// pieces that are still waiting for their workers.
 	pieceTaken := udc.pieceUsage[pieceData.index]
 	piecesInProgress := udc.piecesRegistered + udc.piecesCompleted
+	if !udc.isPriorityPiece(pieceData.index) {
+		piecesInProgress += udc.reservedPriorityPieces(w.staticHostPubKeyStr)
+	}
 	desiredPiecesInProgress := udc.erasureCode.MinPieces() + udc.staticOverdrive
 	workersDesired := piecesInProgress < desiredPiecesInProgress && !pieceTaken
 
+	// The worker processed the chunk, so its piece is no longer reserved.
+	wasPending := udc.releasePriorityHost(w.staticHostPubKeyStr)
+
 	if workersDesired && meetsExtraCriteria {
 		// Worker can be useful. Register the worker and return the chunk for
 		// downloading.
@@ -287,5 +319,18 @@ func (w *worker) managedProcessDownloadChunk(udc *unfinishedDownloadChunk) *unfi
 	// standby for this chunk. The worker is still available to help with the
 	// download, so the worker is not removed from the chunk in this codepath.
 	udc.workersStandby = append(udc.workersStandby, w)
+
+	// If a priority worker goes on standby, its reservation is released
+	// without a worker being removed. Clean up the chunk to launch standby
+	// workers for the released slot.
+	//
+	// The same applies if the worker is desired but fails the extra criteria
+	// and it's the last worker to process the chunk. No other worker would
+	// register or be removed, so the chunk would wait on its standby workers
+	// forever.
+	workersUnprocessed := udc.workersRemaining - udc.piecesRegistered - len(udc.workersStandby)
+	if wasPending || (workersDesired && workersUnprocessed <= 0) {
+		go udc.managedCleanUp()
+	}
 	return nil
 }
@@ -2,11 +2,14 @@ package renter
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
 
+	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
@@ -277,6 +280,36 @@ func TestProcessDownloadChunk(t *testing.T) {
 		t.Fatalf("expected 1 standby worker but got %v", len(udc.workersStandby))
 	}
 
+	// the worker isn't sticky and it's the last worker to process the chunk.
+	// It should be launched from standby right away instead of leaving the
+	// chunk without any workers.
+	udc = chunk()
+	udc.staticClassParams, err = downloadPriorityClassBackground.params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	udc.staticSpendingCategory = categoryDownload
+	udc.staticStickyWorkers = map[string]struct{}{"other": {}}
+	c = wt.managedProcessDownloadChunk(udc)
+	if c != nil {
+		t.Fatal("c should be nil")
+	}
+	err = build.Retry(100, 100*time.Millisecond, func() error {
+		udc.mu.Lock()
+		defer udc.mu.Unlock()
+		if !udc.criteriaRelaxed {
+			return errors.New("standby workers weren't launched")
+		}
+		// Wait for the relaunched worker to finish.
+		if udc.workersRemaining != 0 {
+			return fmt.Errorf("expected 0 remaining workers but got %v", udc.workersRemaining)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	// helper to add jobs to the queue.
 	addBlankJobs := func(n int) {
 		for i := 0; i < n; i++ {
@@ -49,6 +49,12 @@ const (
 	// requests can be batched together without increasing the required
 	// upload or download bandwidth.
 	hasSectorBatchSize = 13
+
+	// jobHasSectorShutdownDrainTimeout is the amount of time a has sector
+	// job keeps trying to deliver its response after the renter started
+	// shutting down. This gives callers that are still waiting on the
+	// response channel a chance to receive a deterministic result.
+	jobHasSectorShutdownDrainTimeout = time.Second
 )
 
 // errEstimateAboveMax is returned if a HasSector job wasn't added due to the
@@ -253,22 +259,11 @@ func (w *worker) newJobHasSectorWithPostExecutionHook(ctx context.Context, respo
 // callDiscard will discard a job, sending the provided error.
 func (j *jobHasSector) callDiscard(err error) {
 	w := j.staticQueue.staticWorker()
-	errLaunch := w.staticRenter.tg.Launch(func() {
-		response := &jobHasSectorResponse{
-			staticErr: errors.Extend(err, ErrJobDiscarded),
+	j.callSendResponse(&jobHasSectorResponse{
+		staticErr: errors.Extend(err, ErrJobDiscarded),
 
-			staticWorker: w,
-		}
-		j.managedCallPostExecutionHook(response)
-		select {
-		case j.staticResponseChan <- response:
-		case <-j.staticCtx.Done():
-		case <-w.staticRenter.tg.StopChan():
-		}
+		staticWorker: w,
 	})
-	if errLaunch != nil {
-		w.staticRenter.staticLog.Print("callDiscard: launch failed", err)
-	}
 
 	j.staticSpan.LogKV("callDiscard", err)
 	j.staticSpan.SetTag("success", false)
@@ -342,14 +337,8 @@ func (j jobHasSectorBatch) callExecute() {
 			response.staticAvailables = availables[i]
 		}
 		// Send the response.
-		err2 := w.staticRenter.tg.Launch(func() {
-			hsj.managedCallPostExecutionHook(response)
-			select {
-			case hsj.staticResponseChan <- response:
-			case <-hsj.staticCtx.Done():
-			case <-w.staticRenter.tg.StopChan():
-			}
-		})
+		hsj.callSendResponse(response)
+
 		// Report success or failure to the queue.
 		if err != nil {
 			hsj.staticQueue.callReportFailure(err)
@@ -362,9 +351,49 @@ func (j jobHasSectorBatch) callExecute() {
 		jq := hsj.staticQueue.(*jobHasSectorQueue)
 		jq.callUpdateJobTimeMetrics(jobTime)
 		jq.callUpdateAvailabilityMetrics(hsj.staticNumPieces, availables[i])
-		if err2 != nil {
-			w.staticRenter.staticLog.Println("callExecute: launch failed", err)
-		}
+	}
+}
+
+// callSendResponse sends the response to the caller in a separate goroutine.
+// If the renter is shutting down, the goroutine can't be launched with the
+// thread group anymore. In that case the response is only sent if the caller
+// is ready to receive it right away.
+func (j *jobHasSector) callSendResponse(response *jobHasSectorResponse) {
+	w := j.staticQueue.staticWorker()
+	err := w.staticRenter.tg.Launch(func() {
+		j.managedSendResponse(response)
+	})
+	if err == nil {
+		return
+	}
+	j.managedCallPostExecutionHook(response)
+	select {
+	case j.staticResponseChan <- response:
+	default:
+		w.staticRenter.staticLog.Debugln("callSendResponse: launch failed", err)
+	}
+}
+
+// managedSendResponse calls the post execution hook and sends the response
+// over the job's response channel. Once the renter starts shutting down, the
+// response is dropped if the caller doesn't receive it within
+// jobHasSectorShutdownDrainTimeout.
+func (j *jobHasSector) managedSendResponse(response *jobHasSectorResponse) {
+	w := j.staticQueue.staticWorker()
+	j.managedCallPostExecutionHook(response)
+	select {
+	case j.staticResponseChan <- response:
+		return
+	case <-j.staticCtx.Done():
+		return
+	case <-w.staticRenter.tg.StopChan():
+	}
+
+	// The renter is shutting down, drain for a short while.
+	select {
+	case j.staticResponseChan <- response:
+	case <-j.staticCtx.Done():
+	case <-time.After(jobHasSectorShutdownDrainTimeout):
 	}
 }
 
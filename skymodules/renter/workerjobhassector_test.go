@@ -58,6 +58,40 @@ func TestHasSectorJobBatchCallNext(t *testing.T) {
 	}
 }
 
+// TestHasSectorJobDiscardOnShutdown makes sure that a has sector job that is
+// discarded after the renter shut down still delivers its response to the
+// caller's buffered response channel.
+func TestHasSectorJobDiscardOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	// Create a worker with a stopped thread group.
+	w := &worker{staticRenter: &Renter{}}
+	if err := w.staticRenter.tg.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	queue := &jobHasSectorQueue{
+		availabilityMetrics: newAvailabilityMetrics(availabilityMetricsDefaultHalfLife),
+		jobGenericQueue:     newJobGenericQueue(w),
+	}
+	responseChan := make(chan *jobHasSectorResponse, 1)
+	jhs := &jobHasSector{
+		jobGeneric:         newJobGeneric(context.Background(), queue, nil),
+		staticResponseChan: responseChan,
+		staticSpan:         testSpan(),
+	}
+
+	// Discard the job. The response should be delivered right away.
+	jhs.callDiscard(errors.New("shutdown"))
+	select {
+	case resp := <-responseChan:
+		if !errors.Contains(resp.staticErr, ErrJobDiscarded) {
+			t.Fatal("unexpected error", resp.staticErr)
+		}
+	default:
+		t.Fatal("response wasn't delivered")
+	}
+}
+
 // TestHasSectorJobQueueAvailabilityRate is a unit that verifies the HS job
 // queue correctly returns the availability rate
 func TestHasSectorJobQueueAvailabilityRate(t *testing.T) {
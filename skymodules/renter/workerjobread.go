@@ -6,6 +6,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"gitlab.com/SkynetLabs/skyd/build"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
 	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
@@ -22,6 +23,39 @@ const (
 	// predictor tends to be more accurate over time, but is less responsive to
 	// things like network load.
 	jobReadPerformanceDecay = 0.9
+
+	// jobReadSuccessRateDecay defines how much decay gets applied to the
+	// historic success rate of jobRead each time a job finishes. It is higher
+	// than the performance decay since a single failure says little about the
+	// reliability of a host.
+	jobReadSuccessRateDecay = 0.95
+
+	// jobReadMinSuccessRate is the success rate below which a worker is
+	// considered to be chronically failing read jobs. These workers are put on
+	// standby for downloads.
+	jobReadMinSuccessRate = 0.5
+
+	// jobReadMinSuccessRateJobs is the min weighted number of read jobs a
+	// worker needs to have finished before its success rate is taken into
+	// account.
+	jobReadMinSuccessRateJobs = 5
+
+	// jobReadDistributionHalfLife is the half life of the distribution of a
+	// worker's read job durations.
+	jobReadDistributionHalfLife = 15 * time.Minute
+)
+
+var (
+	// jobReadChronicFailureRetryInterval is the amount of time after the last
+	// finished read job after which a chronically failing worker is no longer
+	// considered to be failing. Since failing workers aren't used for
+	// downloads, they don't finish any read jobs that could improve their
+	// success rate. This gives them a chance to recover.
+	jobReadChronicFailureRetryInterval = build.Select(build.Var{
+		Dev:      time.Minute,
+		Standard: 5 * time.Minute,
+		Testing:  time.Second,
+	}).(time.Duration)
 )
 
 type (
@@ -56,6 +90,20 @@ type (
 		weightedJobTime1m  float64
 		weightedJobTime4m  float64
 
+		// weightedJobs and weightedSuccesses are the decayed number of
+		// finished read jobs and the decayed number of successful ones. Their
+		// ratio is the worker's read success rate.
+		weightedJobs      float64
+		weightedSuccesses float64
+
+		// lastJobFinished is the time at which the success rate was last
+		// updated.
+		lastJobFinished time.Time
+
+		// distribution contains the durations of the worker's read jobs of
+		// all lengths. It is created with the first data point.
+		distribution *skymodules.Distribution
+
 		mu sync.Mutex
 	}
 
@@ -161,6 +209,8 @@ func (j *jobRead) managedFinishExecute(readData []byte, readErr error, readJobTi
 	}
 
 	// Report success or failure to the queue.
+	jq := j.staticQueue.(*jobReadQueue)
+	jq.staticStats.callUpdateSuccessRate(readErr == nil)
 	if readErr != nil {
 		j.staticQueue.callReportFailure(readErr)
 		return
@@ -173,7 +223,6 @@ func (j *jobRead) managedFinishExecute(readData []byte, readErr error, readJobTi
 	// time the read took. Stats should only be added if the job did not
 	// result in an error. Because there was no failure, the consecutive
 	// failures stat can be reset.
-	jq := j.staticQueue.(*jobReadQueue)
 	jq.staticStats.callUpdateJobTimeMetrics(j.staticLength, readJobTime)
 }
 
@@ -267,8 +316,12 @@ func (jrs *jobReadStats) expectedJobTime(length uint64) time.Duration {
 // callExpectedJobCost returns an estimate for the price of performing a read
 // job with the given length.
 func (jq *jobReadQueue) callExpectedJobCost(length uint64) types.Currency {
-	pt := &jq.staticWorker().staticPriceTable().staticPriceTable
+	return expectedReadJobCost(&jq.staticWorker().staticPriceTable().staticPriceTable, length)
+}
 
+// expectedReadJobCost returns an estimate for the price of performing a read
+// job with the given length using the given price table.
+func expectedReadJobCost(pt *modules.RPCPriceTable, length uint64) types.Currency {
 	// Calculate init cost. The program we use has a 48 byte program data and 1
 	// instruction. 48 = 8 bytes length + 8 bytes offset + 32 bytes merkle root
 	cost := modules.MDMInitCost(pt, 48, 1)
@@ -287,11 +340,79 @@ func (jq *jobReadQueue) callExpectedJobCost(length uint64) types.Currency {
 	return cost
 }
 
+// callIsChronicallyFailing returns true if the worker finished enough read jobs
+// for its success rate to be meaningful and the success rate is below the
+// minimum. Workers that haven't finished a read job within the retry interval
+// are given another chance.
+func (jrs *jobReadStats) callIsChronicallyFailing() bool {
+	jrs.mu.Lock()
+	defer jrs.mu.Unlock()
+	if time.Since(jrs.lastJobFinished) >= jobReadChronicFailureRetryInterval {
+		return false
+	}
+	return jrs.weightedJobs >= jobReadMinSuccessRateJobs && jrs.successRate() < jobReadMinSuccessRate
+}
+
+// callSuccessRate returns the fraction of recent read jobs that succeeded.
+func (jrs *jobReadStats) callSuccessRate() float64 {
+	jrs.mu.Lock()
+	defer jrs.mu.Unlock()
+	return jrs.successRate()
+}
+
+// successRate returns the fraction of recent read jobs that succeeded. Workers
+// without any finished jobs have a success rate of 1.
+func (jrs *jobReadStats) successRate() float64 {
+	if jrs.weightedJobs == 0 {
+		return 1
+	}
+	return jrs.weightedSuccesses / jrs.weightedJobs
+}
+
+// callUpdateSuccessRate updates the success rate with the result of a finished
+// read job.
+func (jrs *jobReadStats) callUpdateSuccessRate(success bool) {
+	jrs.mu.Lock()
+	defer jrs.mu.Unlock()
+	jrs.lastJobFinished = time.Now()
+	jrs.weightedJobs = jrs.weightedJobs*jobReadSuccessRateDecay + 1
+	jrs.weightedSuccesses *= jobReadSuccessRateDecay
+	if success {
+		jrs.weightedSuccesses++
+	}
+}
+
+// callDistribution returns a copy of the distribution of the worker's read job
+// durations, or nil if no read jobs have completed yet.
+func (jrs *jobReadStats) callDistribution() *skymodules.Distribution {
+	jrs.mu.Lock()
+	defer jrs.mu.Unlock()
+	if jrs.distribution == nil {
+		return nil
+	}
+	return jrs.distribution.Clone()
+}
+
+// callStandardDeviation returns the standard deviation of the worker's read
+// job durations.
+func (jrs *jobReadStats) callStandardDeviation() time.Duration {
+	jrs.mu.Lock()
+	defer jrs.mu.Unlock()
+	if jrs.distribution == nil {
+		return 0
+	}
+	return jrs.distribution.StandardDeviation()
+}
+
 // callUpdateJobTimeMetrics takes a length and the duration it took to fulfil
 // that job and uses it to update the job performance metrics on the queue.
 func (jrs *jobReadStats) callUpdateJobTimeMetrics(length uint64, jobTime time.Duration) {
 	jrs.mu.Lock()
 	defer jrs.mu.Unlock()
+	if jrs.distribution == nil {
+		jrs.distribution = skymodules.NewDistribution(jobReadDistributionHalfLife)
+	}
+	jrs.distribution.AddDataPoint(jobTime)
 	if length <= 1<<16 {
 		jrs.weightedJobTime64k = expMovingAvgHotStart(jrs.weightedJobTime64k, float64(jobTime), jobReadPerformanceDecay)
 	} else if length <= 1<<20 {
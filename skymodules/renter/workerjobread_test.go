@@ -49,6 +49,70 @@ func TestJobExpectedJobTime(t *testing.T) {
 	}
 }
 
+// TestJobReadSuccessRate is a small unit test that verifies the success rate
+// tracking of the jobReadStats.
+func TestJobReadSuccessRate(t *testing.T) {
+	t.Parallel()
+
+	jrs := &jobReadStats{}
+
+	// A worker without any jobs has a perfect success rate but isn't failing.
+	if rate := jrs.callSuccessRate(); rate != 1 {
+		t.Fatal("unexpected success rate", rate)
+	}
+
+	// A few failures shouldn't mark the worker as failing yet.
+	for i := 0; i < jobReadMinSuccessRateJobs-1; i++ {
+		jrs.callUpdateSuccessRate(false)
+	}
+	if jrs.callSuccessRate() != 0 {
+		t.Fatal("unexpected success rate", jrs.callSuccessRate())
+	}
+	if jrs.callIsChronicallyFailing() {
+		t.Fatal("worker shouldn't be failing before enough jobs finished")
+	}
+
+	// Once enough jobs finished, the worker is failing.
+	for i := 0; i < 10; i++ {
+		jrs.callUpdateSuccessRate(false)
+	}
+	if !jrs.callIsChronicallyFailing() {
+		t.Fatal("worker should be failing")
+	}
+
+	// A failing worker that didn't finish a job within the retry interval is
+	// given another chance.
+	jrs.mu.Lock()
+	lastJobFinished := jrs.lastJobFinished
+	jrs.lastJobFinished = time.Now().Add(-jobReadChronicFailureRetryInterval)
+	jrs.mu.Unlock()
+	if jrs.callIsChronicallyFailing() {
+		t.Fatal("worker should be retried")
+	}
+	jrs.mu.Lock()
+	jrs.lastJobFinished = lastJobFinished
+	jrs.mu.Unlock()
+
+	// Successful jobs should recover the success rate.
+	for i := 0; i < 100; i++ {
+		jrs.callUpdateSuccessRate(true)
+	}
+	if rate := jrs.callSuccessRate(); rate < 0.99 || rate > 1 {
+		t.Fatal("unexpected success rate", rate)
+	}
+	if jrs.callIsChronicallyFailing() {
+		t.Fatal("worker shouldn't be failing anymore")
+	}
+
+	// Alternating results should result in a rate of about 50%.
+	for i := 0; i < 1000; i++ {
+		jrs.callUpdateSuccessRate(i%2 == 0)
+	}
+	if rate := jrs.callSuccessRate(); rate < 0.45 || rate > 0.55 {
+		t.Fatal("unexpected success rate", rate)
+	}
+}
+
 // TestJobReadMetadata verifies the job metadata is set on the job read response
 func TestJobReadMetadata(t *testing.T) {
 	if testing.Short() {
@@ -3,6 +3,18 @@ package renter
 import (
 	"sync"
 	"time"
+
+	"gitlab.com/SkynetLabs/skyd/build"
+	"gitlab.com/SkynetLabs/skyd/skymodules"
+)
+
+var (
+	// defaultMaintenanceCooldownRecoveryFactor is the default fraction of the
+	// remaining maintenance cooldown that is dropped every time one of the
+	// worker's maintenance tasks succeeds while the worker is on cooldown. By
+	// default the full cooldown is waited out. It can be overwritten with the
+	// SKYD_COOLDOWN_RECOVERY_FACTOR environment variable.
+	defaultMaintenanceCooldownRecoveryFactor = 0.0
 )
 
 type (
@@ -28,6 +40,12 @@ type (
 		priceTableUpdateSucceeded     bool
 		revisionsMismatchFixSucceeded bool
 
+		// staticCooldownRecoveryFactor is the fraction of the remaining
+		// cooldown that is dropped when a maintenance task succeeds but the
+		// worker can't go off of its cooldown yet. A value of 0 keeps the
+		// cooldown, a value of 1 clears it.
+		staticCooldownRecoveryFactor float64
+
 		mu sync.Mutex
 	}
 )
@@ -69,16 +87,63 @@ func (wms *workerMaintenanceState) managedMaintenanceCooldownStatus() (bool, tim
 	return onCooldown, cdDuration, wms.recentErr
 }
 
-// managedResetMaintenanceCooldown resets the worker's cooldown after a
-// successful interaction with the host that involved the RHP3 protocol.
+// reduceMaintenanceCooldown drops the recovery factor's fraction of the
+// remaining cooldown. It is called after a successful interaction with the
+// host, which indicates that the host recovered, so the worker rejoins the
+// downloads faster than if it waited out the full cooldown.
+func (wms *workerMaintenanceState) reduceMaintenanceCooldown() {
+	remaining := time.Until(wms.cooldownUntil)
+	if remaining <= 0 || wms.staticCooldownRecoveryFactor == 0 {
+		return
+	}
+	keep := time.Duration(float64(remaining) * (1 - wms.staticCooldownRecoveryFactor))
+	wms.cooldownUntil = time.Now().Add(keep)
+}
+
+// tryResetMaintenanceCooldown resets the worker's cooldown after a successful
+// interaction with the host that involved the RHP3 protocol. If not all
+// maintenance tasks succeeded yet, the remaining cooldown is reduced instead.
 func (wms *workerMaintenanceState) tryResetMaintenanceCooldown() time.Time {
 	if wms.maintenanceSucceeded() {
 		wms.consecutiveFailures = 0
 		wms.cooldownUntil = time.Time{}
+		return wms.cooldownUntil
 	}
+	wms.reduceMaintenanceCooldown()
 	return wms.cooldownUntil
 }
 
+// managedMaintenanceCooldownInfo is a helper function that returns detailed
+// information about the maintenance cooldown, including its expiry and the
+// error that caused it.
+func (wms *workerMaintenanceState) managedMaintenanceCooldownInfo() skymodules.WorkerMaintenanceCooldownStatus {
+	wms.mu.Lock()
+	defer wms.mu.Unlock()
+
+	var recentErrStr string
+	if wms.recentErr != nil {
+		recentErrStr = wms.recentErr.Error()
+	}
+	return skymodules.WorkerMaintenanceCooldownStatus{
+		ConsecutiveFailures: wms.consecutiveFailures,
+		OnCooldown:          time.Now().Before(wms.cooldownUntil),
+		OnCooldownUntil:     wms.cooldownUntil,
+		RecentErr:           recentErrStr,
+		RecentErrTime:       wms.recentErrTime,
+	}
+}
+
+// managedClearMaintenanceCooldown takes the worker off of its maintenance
+// cooldown and resets the consecutive failures. The success flags of the
+// individual maintenance tasks are left untouched, if any of them fails again
+// the worker will go back on cooldown.
+func (wms *workerMaintenanceState) managedClearMaintenanceCooldown() {
+	wms.mu.Lock()
+	defer wms.mu.Unlock()
+	wms.consecutiveFailures = 0
+	wms.cooldownUntil = time.Time{}
+}
+
 // managedMaintenanceRecentError is a helper function that returns the recent
 // maintenance error
 func (w *worker) managedMaintenanceRecentError() error {
@@ -171,5 +236,17 @@ func (w *worker) newMaintenanceState() {
 	if w.staticMaintenanceState != nil {
 		w.staticRenter.staticLog.Critical("maintenancestate already exists")
 	}
-	w.staticMaintenanceState = new(workerMaintenanceState)
+	w.staticMaintenanceState = &workerMaintenanceState{
+		staticCooldownRecoveryFactor: maintenanceCooldownRecoveryFactor(),
+	}
+}
+
+// maintenanceCooldownRecoveryFactor returns the configured maintenance
+// cooldown recovery factor. Values outside of [0, 1] are ignored.
+func maintenanceCooldownRecoveryFactor() float64 {
+	factor, ok := build.CooldownRecoveryFactor()
+	if !ok || factor < 0 || factor > 1 {
+		return defaultMaintenanceCooldownRecoveryFactor
+	}
+	return factor
 }
@@ -3,8 +3,10 @@ package renter
 import (
 	"context"
 	"math"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/opentracing/opentracing-go"
 	"gitlab.com/NebulousLabs/errors"
@@ -148,8 +150,12 @@ func TestWorkerMaintenanceRefillLowContractFunds(t *testing.T) {
 	}
 	funds := contract.RenterFunds
 
-	// set the target to the balance.
+	// set the target to the balance. The worker's cache still contains the
+	// host's previous max balance, so it needs to be updated as well.
 	w.staticBalanceTarget = funds
+	cache := *w.staticCache()
+	cache.staticHostMaxBalance = is.MaxEphemeralAccountBalance
+	atomic.StorePointer(&w.atomicCache, unsafe.Pointer(&cache))
 
 	// trigger a refill.
 	w.managedRefillAccount()
@@ -163,3 +169,98 @@ func TestWorkerMaintenanceRefillLowContractFunds(t *testing.T) {
 		t.Fatalf("expected balance %v but got %v", accountBalance, expectedBalance)
 	}
 }
+
+// TestWorkerMaintenanceClearCooldown verifies that clearing the maintenance
+// cooldown takes the worker off of cooldown and that the cooldown info
+// reflects the state correctly.
+func TestWorkerMaintenanceClearCooldown(t *testing.T) {
+	t.Parallel()
+
+	wms := new(workerMaintenanceState)
+	info := wms.managedMaintenanceCooldownInfo()
+	if info.OnCooldown || info.ConsecutiveFailures != 0 || info.RecentErr != "" {
+		t.Fatal("unexpected initial cooldown info", info)
+	}
+
+	// Put the state on cooldown.
+	err := errors.New("maintenance failure")
+	wms.mu.Lock()
+	wms.incrementMaintenanceCooldown(err)
+	wms.incrementMaintenanceCooldown(err)
+	wms.mu.Unlock()
+
+	info = wms.managedMaintenanceCooldownInfo()
+	if !info.OnCooldown {
+		t.Fatal("expected worker to be on cooldown")
+	}
+	if info.ConsecutiveFailures != 2 {
+		t.Fatal("unexpected consecutive failures", info.ConsecutiveFailures)
+	}
+	if info.RecentErr != err.Error() {
+		t.Fatal("unexpected recent error", info.RecentErr)
+	}
+	if !info.OnCooldownUntil.After(time.Now()) {
+		t.Fatal("expected cooldown expiry in the future", info.OnCooldownUntil)
+	}
+
+	// Clear the cooldown.
+	wms.managedClearMaintenanceCooldown()
+	info = wms.managedMaintenanceCooldownInfo()
+	if info.OnCooldown {
+		t.Fatal("expected worker to be off cooldown")
+	}
+	if info.ConsecutiveFailures != 0 {
+		t.Fatal("unexpected consecutive failures", info.ConsecutiveFailures)
+	}
+	if info.RecentErr != err.Error() {
+		t.Fatal("recent error should be preserved", info.RecentErr)
+	}
+}
+
+// TestWorkerMaintenanceCooldownRecovery verifies that a successful maintenance
+// task reduces the remaining maintenance cooldown by the recovery factor.
+func TestWorkerMaintenanceCooldownRecovery(t *testing.T) {
+	t.Parallel()
+
+	// Put the state on cooldown after a failed refill.
+	wms := &workerMaintenanceState{
+		staticCooldownRecoveryFactor: 0.5,
+	}
+	cooldown := time.Hour
+	wms.mu.Lock()
+	wms.incrementMaintenanceCooldown(errors.New("refill failure"))
+	wms.cooldownUntil = time.Now().Add(cooldown)
+	wms.mu.Unlock()
+
+	// A successful price table update should halve the remaining cooldown.
+	wms.mu.Lock()
+	wms.priceTableUpdateSucceeded = true
+	until := wms.tryResetMaintenanceCooldown()
+	wms.mu.Unlock()
+	remaining := time.Until(until)
+	if remaining > cooldown/2 || remaining < cooldown/2-time.Minute {
+		t.Fatal("cooldown wasn't reduced correctly", remaining)
+	}
+
+	// The worker should still be on cooldown and the failures should be kept.
+	info := wms.managedMaintenanceCooldownInfo()
+	if !info.OnCooldown || info.ConsecutiveFailures != 1 {
+		t.Fatal("unexpected cooldown info", info)
+	}
+
+	// Without a recovery factor, the cooldown shouldn't change.
+	wms.mu.Lock()
+	wms.staticCooldownRecoveryFactor = 0
+	if wms.tryResetMaintenanceCooldown() != until {
+		t.Fatal("cooldown shouldn't have changed")
+	}
+
+	// Once all tasks succeeded, the cooldown should be reset.
+	wms.accountRefillSucceeded = true
+	wms.accountSyncSucceeded = true
+	wms.revisionsMismatchFixSucceeded = true
+	if !wms.tryResetMaintenanceCooldown().IsZero() || wms.consecutiveFailures != 0 {
+		t.Fatal("cooldown wasn't reset")
+	}
+	wms.mu.Unlock()
+}
@@ -8,6 +8,7 @@ import (
 
 	"gitlab.com/SkynetLabs/skyd/build"
 	"gitlab.com/SkynetLabs/skyd/skymodules"
+	"go.sia.tech/siad/modules"
 	"go.sia.tech/siad/types"
 )
 
@@ -48,7 +49,7 @@ func (wp *workerPool) callStatus() skymodules.WorkerPoolStatus {
 
 	// Fetch the list of workers from the worker pool.
 
-	var totalDownloadCoolDown, totalMaintenanceCoolDown, totalUploadCoolDown int
+	var totalDownloadCoolDown, totalMaintenanceCoolDown, totalStalePriceTables, totalUploadCoolDown int
 	var statuss []skymodules.WorkerStatus // Plural of status is statuss, deal with it.
 	workers := wp.callWorkers()
 
@@ -61,6 +62,9 @@ func (wp *workerPool) callStatus() skymodules.WorkerPoolStatus {
 		if status.MaintenanceOnCooldown {
 			totalMaintenanceCoolDown++
 		}
+		if status.PriceTableStatus.Stale {
+			totalStalePriceTables++
+		}
 		if status.UploadOnCoolDown {
 			totalUploadCoolDown++
 		}
@@ -70,6 +74,7 @@ func (wp *workerPool) callStatus() skymodules.WorkerPoolStatus {
 		NumWorkers:               len(workers),
 		TotalDownloadCoolDown:    totalDownloadCoolDown,
 		TotalMaintenanceCoolDown: totalMaintenanceCoolDown,
+		TotalStalePriceTables:    totalStalePriceTables,
 		TotalUploadCoolDown:      totalUploadCoolDown,
 		Workers:                  statuss,
 	}
@@ -93,6 +98,9 @@ func (wp *workerPool) callUpdate() {
 		contractMap[contract.HostPublicKey.String()] = contract
 	}
 
+	// Check the workers' price tables once the pool has been updated.
+	defer wp.managedUpdateStalePriceTablesAlert()
+
 	// Lock the worker pool for the duration of updating its fields.
 	wp.mu.Lock()
 	defer wp.mu.Unlock()
@@ -158,6 +166,25 @@ func (wp *workerPool) callUpdate() {
 	}
 }
 
+// managedUpdateStalePriceTablesAlert registers an alert if a significant
+// fraction of the workers have a stale price table, which indicates that the
+// price table update loop is failing. The alert is unregistered otherwise.
+func (wp *workerPool) managedUpdateStalePriceTablesAlert() {
+	workers := wp.callWorkers()
+	var numStale int
+	for _, w := range workers {
+		if w.staticPriceTable().staticStale() {
+			numStale++
+		}
+	}
+	if len(workers) > 0 && float64(numStale)/float64(len(workers)) >= stalePriceTablesAlertThreshold {
+		cause := AlertCauseStalePriceTables(numStale, len(workers))
+		wp.staticRenter.staticAlerter.RegisterAlert(AlertIDStalePriceTables, AlertMSGStalePriceTables, cause, modules.SeverityWarning)
+	} else {
+		wp.staticRenter.staticAlerter.UnregisterAlert(AlertIDStalePriceTables)
+	}
+}
+
 // Worker will return the worker associated with the provided public key.
 // If no worker is found, an error will be returned.
 func (wp *workerPool) Worker(hostPubKey types.SiaPublicKey) (skymodules.Worker, error) {
@@ -186,6 +213,56 @@ func (r *Renter) WorkerPoolStatus() (skymodules.WorkerPoolStatus, error) {
 	return r.staticWorkerPool.callStatus(), nil
 }
 
+// ClearWorkerCooldown takes the worker for the host with the given public key
+// off of its maintenance cooldown, allowing it to be used again immediately.
+func (r *Renter) ClearWorkerCooldown(hostPubKey types.SiaPublicKey) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	w, err := r.staticWorkerPool.callWorker(hostPubKey)
+	if err != nil {
+		return err
+	}
+	w.staticMaintenanceState.managedClearMaintenanceCooldown()
+	w.staticWake()
+	return nil
+}
+
+// SetWorkerBalanceTarget sets the balance the worker for the host with the
+// given public key refills its ephemeral account to. It overrides the
+// allowance's account refill high water mark. A zero target removes the
+// override.
+func (r *Renter) SetWorkerBalanceTarget(hostPubKey types.SiaPublicKey, target types.Currency) error {
+	if err := r.tg.Add(); err != nil {
+		return err
+	}
+	defer r.tg.Done()
+	w, err := r.staticWorkerPool.callWorker(hostPubKey)
+	if err != nil {
+		return err
+	}
+	w.staticSetBalanceTargetOverride(target)
+	w.staticWake()
+	return nil
+}
+
+// WorkerMaintenanceCooldownStatus returns the maintenance cooldown status of
+// the worker for the host with the given public key.
+func (r *Renter) WorkerMaintenanceCooldownStatus(hostPubKey types.SiaPublicKey) (skymodules.WorkerMaintenanceCooldownStatus, error) {
+	if err := r.tg.Add(); err != nil {
+		return skymodules.WorkerMaintenanceCooldownStatus{}, err
+	}
+	defer r.tg.Done()
+	w, err := r.staticWorkerPool.callWorker(hostPubKey)
+	if err != nil {
+		return skymodules.WorkerMaintenanceCooldownStatus{}, err
+	}
+	status := w.staticMaintenanceState.managedMaintenanceCooldownInfo()
+	status.HostPubKey = hostPubKey
+	return status, nil
+}
+
 // callWorkers will safely grab the list of workers in the worker pool. This
 // function must be used instead of accessing the worker map directly in any
 // situation where the workers are being used as opposed to just counted,
@@ -49,6 +49,10 @@ var (
 		Testing:  1 * time.Minute,
 	}).(time.Duration)
 
+	// stalePriceTablesAlertThreshold is the fraction of workers with a stale
+	// price table at which the renter registers an alert.
+	stalePriceTablesAlertThreshold = 0.25
+
 	// minInitialEstimate is the minimum job time estimate that's set on the HS
 	// and RJ queue in case we fail to update the price table successfully
 	minInitialEstimate = time.Second
@@ -79,6 +83,10 @@ type (
 		// The time at which the price table expires.
 		staticExpiryTime time.Time
 
+		// The time at which the price table was last successfully fetched
+		// from the host.
+		staticFetchTime time.Time
+
 		// The time at which the worker scheduled a price table update manually.
 		// We limit the amount of times this can occur because the host might
 		// take advantage of this mechanism and have the renter constantly
@@ -172,6 +180,21 @@ func (wpt *workerPriceTable) staticValidFor(duration time.Duration) bool {
 	return minExpiry.Before(wpt.staticExpiryTime)
 }
 
+// staticAge returns the time that has passed since the price table was last
+// fetched from the host. If the price table was never fetched, the age is 0.
+func (wpt *workerPriceTable) staticAge() time.Duration {
+	if wpt.staticFetchTime.IsZero() {
+		return 0
+	}
+	return time.Since(wpt.staticFetchTime)
+}
+
+// staticStale returns true if the price table expired because the most recent
+// attempt to update it failed.
+func (wpt *workerPriceTable) staticStale() bool {
+	return !wpt.staticValid() && wpt.staticRecentErr != nil
+}
+
 // staticNeedsToUpdate returns whether or not the price table needs to be
 // updated.
 func (wpt *workerPriceTable) staticNeedsToUpdate() bool {
@@ -255,6 +278,7 @@ func (w *worker) staticUpdatePriceTable() {
 		pt := &workerPriceTable{
 			staticPriceTable:       currentPT.staticPriceTable,
 			staticExpiryTime:       currentPT.staticExpiryTime,
+			staticFetchTime:        currentPT.staticFetchTime,
 			staticLastForcedUpdate: currentPT.staticLastForcedUpdate,
 			staticUpdateTime:       cd,
 			staticRecentErr:        err,
@@ -371,6 +395,7 @@ func (w *worker) staticUpdatePriceTable() {
 	wpt := &workerPriceTable{
 		staticPriceTable:       pt,
 		staticExpiryTime:       expiryTime,
+		staticFetchTime:        now,
 		staticUpdateTime:       newUpdateTime,
 		staticLastForcedUpdate: currentPT.staticLastForcedUpdate,
 		staticRecentErr:        currentPT.staticRecentErr,
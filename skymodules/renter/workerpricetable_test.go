@@ -2,6 +2,7 @@ package renter
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -299,6 +300,96 @@ func TestHostBlockHeightWithinTolerance(t *testing.T) {
 	}
 }
 
+// TestWorkerPriceTableStale is a unit test for the staticAge and staticStale
+// helpers of the workerPriceTable.
+func TestWorkerPriceTableStale(t *testing.T) {
+	t.Parallel()
+
+	// A price table that was never fetched has no age and isn't stale.
+	var wpt workerPriceTable
+	if wpt.staticAge() != 0 || wpt.staticStale() {
+		t.Fatal("empty price table should neither have an age nor be stale")
+	}
+
+	// A valid price table isn't stale even if the last update failed.
+	wpt = workerPriceTable{
+		staticExpiryTime: time.Now().Add(time.Minute),
+		staticFetchTime:  time.Now().Add(-time.Minute),
+		staticRecentErr:  errors.New("failure"),
+	}
+	if wpt.staticAge() < time.Minute {
+		t.Fatal("unexpected age", wpt.staticAge())
+	}
+	if wpt.staticStale() {
+		t.Fatal("valid price table shouldn't be stale")
+	}
+
+	// An expired price table is stale if the last update failed.
+	wpt.staticExpiryTime = time.Now().Add(-time.Second)
+	if !wpt.staticStale() {
+		t.Fatal("expired price table should be stale")
+	}
+	wpt.staticRecentErr = nil
+	if wpt.staticStale() {
+		t.Fatal("expired price table without error shouldn't be stale")
+	}
+}
+
+// TestStalePriceTablesAlert tests that the worker pool registers the stale
+// price tables alert once enough workers have a stale price table and
+// unregisters it again once the price tables were updated.
+func TestStalePriceTablesAlert(t *testing.T) {
+	t.Parallel()
+
+	// Create a worker pool with 4 workers that have a valid price table.
+	r := &Renter{staticAlerter: modules.NewAlerter("renter")}
+	wp := &workerPool{
+		workers:      make(map[string]*worker),
+		staticRenter: r,
+	}
+	valid := &workerPriceTable{staticExpiryTime: time.Now().Add(time.Minute)}
+	stale := &workerPriceTable{
+		staticExpiryTime: time.Now().Add(-time.Second),
+		staticRecentErr:  errors.New("failure"),
+	}
+	var workers []*worker
+	for i := 0; i < 4; i++ {
+		w := &worker{}
+		w.staticSetPriceTable(valid)
+		wp.workers[fmt.Sprint(i)] = w
+		workers = append(workers, w)
+	}
+	hasAlert := func() bool {
+		_, _, warnAlerts := r.staticAlerter.Alerts()
+		for _, alert := range warnAlerts {
+			if alert.Msg == AlertMSGStalePriceTables {
+				return true
+			}
+		}
+		return false
+	}
+
+	// No stale price tables, no alert.
+	wp.managedUpdateStalePriceTablesAlert()
+	if hasAlert() {
+		t.Fatal("alert shouldn't be registered")
+	}
+
+	// A stale price table reaches the threshold of 25%.
+	workers[0].staticSetPriceTable(stale)
+	wp.managedUpdateStalePriceTablesAlert()
+	if !hasAlert() {
+		t.Fatal("alert should be registered")
+	}
+
+	// Once the price table was updated, the alert is unregistered.
+	workers[0].staticSetPriceTable(valid)
+	wp.managedUpdateStalePriceTablesAlert()
+	if hasAlert() {
+		t.Fatal("alert should be unregistered")
+	}
+}
+
 // newDefaultPriceTable is a helper function that returns a price table with
 // default prices for all fields
 func newDefaultPriceTable() modules.RPCPriceTable {
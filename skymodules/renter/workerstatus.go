@@ -39,6 +39,7 @@ func (w *worker) callStatus() skymodules.WorkerStatus {
 	// Update the worker cache before returning a status.
 	w.staticTryUpdateCache()
 	cache := w.staticCache()
+	_, target := w.staticAccountRefillWaterMarks()
 	return skymodules.WorkerStatus{
 		// Contract Information
 		ContractID:      cache.staticContractID,
@@ -69,7 +70,7 @@ func (w *worker) callStatus() skymodules.WorkerStatus {
 		MaintenanceCoolDownTime:  maintenanceCoolDownTime,
 
 		// Account Information
-		AccountBalanceTarget: w.staticBalanceTarget,
+		AccountBalanceTarget: target,
 		AccountStatus:        w.staticAccount.managedStatus(),
 
 		// Price Table Information
@@ -99,10 +100,13 @@ func (w *worker) staticPriceTableStatus() skymodules.WorkerPriceTableStatus {
 	}
 
 	return skymodules.WorkerPriceTableStatus{
-		ExpiryTime: pt.staticExpiryTime,
-		UpdateTime: pt.staticUpdateTime,
+		ExpiryTime:    pt.staticExpiryTime,
+		LastFetchTime: pt.staticFetchTime,
+		UpdateTime:    pt.staticUpdateTime,
 
 		Active: time.Now().Before(pt.staticExpiryTime),
+		Age:    pt.staticAge(),
+		Stale:  pt.staticStale(),
 
 		RecentErr:     recentErrStr,
 		RecentErrTime: pt.staticRecentErrTime,
@@ -134,6 +138,7 @@ func (w *worker) callReadJobStatus() skymodules.WorkerReadJobsStatus {
 		JobQueueSize:        status.size,
 		RecentErr:           recentErrString,
 		RecentErrTime:       status.recentErrTime,
+		SuccessRate:         jrq.staticStats.callSuccessRate(),
 	}
 }
 
@@ -0,0 +1,54 @@
+package skymodules
+
+import (
+	"gitlab.com/NebulousLabs/errors"
+	"go.sia.tech/siad/types"
+)
+
+// PaymentRevisionWithCollateral is a helper to create the revision that a host
+// expects for a given payment and amount of risked collateral. It is the
+// formula that the host verifies revisions against, so both the renter and
+// the host should use it to compute the proof outputs of a new revision.
+//
+// The payment is moved from the renter to the host in the valid proof outputs
+// and from the renter to the void in the missed proof outputs. The collateral
+// is moved from the host to the void in the missed proof outputs. The revision
+// number is incremented by one.
+func PaymentRevisionWithCollateral(current types.FileContractRevision, payment, collateral types.Currency) (types.FileContractRevision, error) {
+	rev, err := current.PaymentRevision(payment)
+	if err != nil {
+		return types.FileContractRevision{}, err
+	}
+
+	// Check that there is enough collateral to cover the cost.
+	if rev.MissedHostOutput().Value.Cmp(collateral) < 0 {
+		return types.FileContractRevision{}, types.ErrRevisionCollateralTooLow
+	}
+
+	// Move collateral from host to void.
+	rev.SetMissedHostPayout(rev.MissedHostOutput().Value.Sub(collateral))
+	voidOutput, err := rev.MissedVoidOutput()
+	if err != nil {
+		return types.FileContractRevision{}, errors.AddContext(err, "failed to get void output")
+	}
+	err = rev.SetMissedVoidPayout(voidOutput.Value.Add(collateral))
+	if err != nil {
+		return types.FileContractRevision{}, errors.AddContext(err, "failed to set void output")
+	}
+	return rev, nil
+}
+
+// ProofValues returns the values of the valid and missed proof outputs of a
+// revision. These are the values sent to the host alongside the revision
+// number in the RPC requests that revise a contract.
+func ProofValues(rev types.FileContractRevision) (valid, missed []types.Currency) {
+	valid = make([]types.Currency, len(rev.NewValidProofOutputs))
+	for i, o := range rev.NewValidProofOutputs {
+		valid[i] = o.Value
+	}
+	missed = make([]types.Currency, len(rev.NewMissedProofOutputs))
+	for i, o := range rev.NewMissedProofOutputs {
+		missed[i] = o.Value
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package skymodules
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/types"
+)
+
+// TestPaymentRevisionWithCollateral is a unit test for
+// PaymentRevisionWithCollateral and ProofValues.
+func TestPaymentRevisionWithCollateral(t *testing.T) {
+	t.Parallel()
+
+	current := types.FileContractRevision{
+		NewRevisionNumber: 1,
+		NewValidProofOutputs: []types.SiacoinOutput{
+			{Value: types.NewCurrency64(100)}, // renter
+			{Value: types.NewCurrency64(50)},  // host
+		},
+		NewMissedProofOutputs: []types.SiacoinOutput{
+			{Value: types.NewCurrency64(100)}, // renter
+			{Value: types.NewCurrency64(50)},  // host
+			{Value: types.ZeroCurrency},       // void
+		},
+	}
+
+	rev, err := PaymentRevisionWithCollateral(current, types.NewCurrency64(10), types.NewCurrency64(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.NewRevisionNumber != 2 {
+		t.Fatal("wrong revision number", rev.NewRevisionNumber)
+	}
+	valid, missed := ProofValues(rev)
+	expectedValid := []uint64{90, 60}
+	expectedMissed := []uint64{90, 30, 30}
+	if len(valid) != len(expectedValid) || len(missed) != len(expectedMissed) {
+		t.Fatal("wrong number of proof values", len(valid), len(missed))
+	}
+	for i, v := range expectedValid {
+		if !valid[i].Equals64(v) {
+			t.Fatalf("valid %v: expected %v but got %v", i, v, valid[i])
+		}
+	}
+	for i, v := range expectedMissed {
+		if !missed[i].Equals64(v) {
+			t.Fatalf("missed %v: expected %v but got %v", i, v, missed[i])
+		}
+	}
+
+	// Risking more collateral than the host has should fail.
+	_, err = PaymentRevisionWithCollateral(current, types.NewCurrency64(10), types.NewCurrency64(51))
+	if err != types.ErrRevisionCollateralTooLow {
+		t.Fatal("unexpected error", err)
+	}
+}
@@ -79,8 +79,10 @@ func NewSkyfileReader(reader io.Reader, sup SkyfileUploadParameters) SkyfileUplo
 	return &skyfileReader{
 		reader: reader,
 		metadata: SkyfileMetadata{
-			Filename: sup.Filename,
-			Mode:     sup.Mode,
+			Filename:           sup.Filename,
+			Mode:               sup.Mode,
+			Immutable:          sup.Immutable,
+			DefaultContentType: sup.ContentType,
 		},
 		metadataAvail: make(chan struct{}),
 	}
@@ -235,6 +237,8 @@ func newSkyfileMultipartReader(reader *multipart.Reader, sup SkyfileUploadParame
 			DisableDefaultPath: sup.DisableDefaultPath,
 			TryFiles:           sup.TryFiles,
 			ErrorPages:         sup.ErrorPages,
+			Immutable:          sup.Immutable,
+			DefaultContentType: sup.ContentType,
 			Subfiles:           make(SkyfileSubfiles),
 		},
 		metadataAvail: make(chan struct{}),
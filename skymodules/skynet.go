@@ -52,6 +52,11 @@ var (
 	// if none is specified and defaultpath and disabledefaultpath are also
 	// unspecified.
 	DefaultTryFilesValue = []string{"index.html"}
+
+	// DefaultSkynetPricePerMS is the default price per millisecond the renter
+	// is able to spend on faster workers when downloading a Skyfile. By default
+	// this is a sane default of 100 nS.
+	DefaultSkynetPricePerMS = types.SiacoinPrecision.MulFloat(1e-7) // 100 nS
 )
 
 var (
@@ -186,6 +191,15 @@ type (
 
 		// ErrorPages overrides the content we serve for some error codes.
 		ErrorPages map[int]string
+
+		// Immutable declares that the content of the skyfile never changes,
+		// which allows portals to cache it aggressively.
+		Immutable bool
+
+		// ContentType declares the canonical content type of the skyfile. It
+		// is served for single-file skyfiles instead of the sniffed content
+		// type of the file.
+		ContentType string
 	}
 
 	// SkyfileMultipartUploadParameters defines the parameters specific to
@@ -246,6 +260,8 @@ type (
 		DisableDefaultPath bool            `json:"disabledefaultpath,omitempty"`
 		TryFiles           []string        `json:"tryfiles,omitempty"`
 		ErrorPages         map[int]string  `json:"errorpages,omitempty"`
+		Immutable          bool            `json:"immutable,omitempty"`
+		DefaultContentType string          `json:"contenttype,omitempty"`
 	}
 
 	// SkynetPortal contains information identifying a Skynet portal.
@@ -366,6 +382,13 @@ func (sm SkyfileMetadata) ForPath(path string) (SkyfileMetadata, bool, uint64, u
 		Subfiles:   make(SkyfileSubfiles),
 		TryFiles:   sm.TryFiles,
 		ErrorPages: sm.ErrorPages,
+		Immutable:  sm.Immutable,
+	}
+
+	// The declared content type only applies to the file of a single-file
+	// skyfile, the subfiles of a multi-subfile skyfile have their own.
+	if len(sm.Subfiles) <= 1 {
+		metadata.DefaultContentType = sm.DefaultContentType
 	}
 
 	// Try to find an exact match
@@ -403,9 +426,13 @@ func (sm SkyfileMetadata) ForPath(path string) (SkyfileMetadata, bool, uint64, u
 }
 
 // ContentType returns the Content Type of the data. We only return a
-// content-type if it has exactly one subfile. As that is the only case where we
-// can be sure of it.
+// content-type if it has at most one subfile. As that is the only case where we
+// can be sure of it. The content type declared in the metadata takes precedence
+// over the one of the subfile.
 func (sm SkyfileMetadata) ContentType() string {
+	if sm.DefaultContentType != "" && len(sm.Subfiles) <= 1 {
+		return sm.DefaultContentType
+	}
 	if len(sm.Subfiles) == 1 {
 		for _, sf := range sm.Subfiles {
 			return sf.ContentType
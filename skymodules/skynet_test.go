@@ -281,6 +281,34 @@ func TestSkyfileMetadata_ForPath(t *testing.T) {
 		t.Fatal(`Expected to find a file by its directory, even when it's missing its leading "/".`)
 	}
 
+	// The immutable flag carries over to the subset.
+	fullMeta.Immutable = true
+	subMeta, _, _, _ = fullMeta.ForPath(filePath1)
+	if !subMeta.Immutable {
+		t.Fatal("Expected the immutable flag to carry over")
+	}
+
+	// The declared content type only carries over for single-file skyfiles.
+	fullMeta.DefaultContentType = "text/plain"
+	subMeta, _, _, _ = fullMeta.ForPath(filePath1)
+	if subMeta.DefaultContentType != "" {
+		t.Fatal("Expected the content type not to carry over")
+	}
+	singleMeta := SkyfileMetadata{
+		Subfiles: SkyfileSubfiles{
+			filePath1: SkyfileSubfileMetadata{Filename: filePath1, Len: 1, ContentType: "text/html"},
+		},
+		DefaultContentType: "text/plain",
+	}
+	subMeta, _, _, _ = singleMeta.ForPath(filePath1)
+	if subMeta.ContentType() != "text/plain" {
+		t.Fatal("Expected the content type to carry over", subMeta.ContentType())
+	}
+	singleMeta.DefaultContentType = ""
+	if singleMeta.ContentType() != "text/html" {
+		t.Fatal("Expected the subfile's content type", singleMeta.ContentType())
+	}
+
 	// Try to find a file in an empty metadata struct.
 	subMeta, _, offset, _ = emptyMeta.ForPath("foo")
 	if len(subMeta.Subfiles) != 0 {
@@ -11,6 +11,7 @@ import (
 	"net/textproto"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/aead/chacha20/chacha"
@@ -21,16 +22,40 @@ import (
 	"go.sia.tech/siad/modules"
 )
 
+const (
+	// DefaultContentTypeSniffLen is the number of bytes used to sniff the
+	// content type of a file. It matches the number of bytes considered by
+	// http.DetectContentType.
+	DefaultContentTypeSniffLen = 512
+)
+
 var (
 	// ErrInvalidDefaultPath is returned when the specified default path is not
 	// valid, e.g. the file it points to does not exist.
 	ErrInvalidDefaultPath = errors.New("invalid default path provided")
 
+	// ErrInvalidSubfileOffsets is returned if the ranges of a skyfile's
+	// subfiles overlap or exceed the skyfile.
+	ErrInvalidSubfileOffsets = errors.New("invalid subfile offsets")
+
 	// ErrMalformedBaseSector is returned if a malformed base sector is
 	// detected.
 	ErrMalformedBaseSector = errors.New("base sector is malformed")
+
+	// ErrBaseSectorPrefixTooShort is returned if the leading bytes of a base
+	// sector don't contain the full layout, fanout and metadata.
+	ErrBaseSectorPrefixTooShort = errors.New("base sector prefix is too short to contain the layout, fanout and metadata")
+
+	// ErrLargeFanoutAndMetadata is returned if the fanout and metadata of a
+	// skyfile don't fit into its base sector.
+	ErrLargeFanoutAndMetadata = errors.New("this version of siad does not support skyfiles with large fanouts and metadata")
 )
 
+// ContentTypeDetector is a function that detects the content type of a file
+// from its leading bytes. It returns an empty string if the content type can't
+// be determined.
+type ContentTypeDetector func(data []byte) string
+
 // AddMultipartFile is a helper function to add a file to multipart form-data.
 // Note that the given data will be treated as binary data and the multipart
 // ContentType header will be set accordingly.
@@ -91,20 +116,27 @@ func BuildBaseSector(layoutBytes, fanoutBytes, metadataBytes, fileBytes []byte)
 	return baseSector, uint64(offset)
 }
 
+// CanParseSkyfile returns whether ParseSkyfileMetadata will succeed for the
+// given base sector, which must not be encrypted yet. If it won't, a
+// human-readable reason is returned as well. This allows for validating a base
+// sector at upload time.
+func CanParseSkyfile(baseSector []byte) (bool, string) {
+	if uint64(len(baseSector)) > modules.SectorSize {
+		return false, fmt.Sprintf("base sector of %v bytes exceeds the sector size of %v bytes", len(baseSector), modules.SectorSize)
+	}
+	if len(baseSector) < SkyfileLayoutSize {
+		return false, fmt.Sprintf("base sector of %v bytes is too short to contain the layout", len(baseSector))
+	}
+	_, _, _, _, _, err := ParseSkyfileMetadata(baseSector)
+	if err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
 // DecodeFanout will take the fanout bytes from a baseSector and decode them.
 func DecodeFanout(sl SkyfileLayout, fanoutBytes []byte) (piecesPerChunk, chunkRootsSize, numChunks uint64, err error) {
-	// Special case: if the data of the file is using 1-of-N erasure coding,
-	// each piece will be identical, so the fanout will only have encoded a
-	// single piece for each chunk.
-	if sl.FanoutDataPieces == 1 && sl.CipherType == crypto.TypePlain {
-		piecesPerChunk = 1
-		chunkRootsSize = crypto.HashSize
-	} else {
-		// This is the case where the file data is not 1-of-N. Every piece is
-		// different, so every piece must get enumerated.
-		piecesPerChunk = uint64(sl.FanoutDataPieces) + uint64(sl.FanoutParityPieces)
-		chunkRootsSize = crypto.HashSize * piecesPerChunk
-	}
+	piecesPerChunk, chunkRootsSize = fanoutChunkRootsSize(sl)
 	// Sanity check - the fanout bytes should be an even number of chunks.
 	if uint64(len(fanoutBytes))%chunkRootsSize != 0 {
 		err = errors.New("the fanout bytes do not contain an even number of chunks")
@@ -114,6 +146,44 @@ func DecodeFanout(sl SkyfileLayout, fanoutBytes []byte) (piecesPerChunk, chunkRo
 	return
 }
 
+// fanoutChunkRootsSize returns the number of pieces per chunk that are
+// encoded in the fanout of the skyfile with the given layout, and the size of
+// their roots within the fanout.
+func fanoutChunkRootsSize(sl SkyfileLayout) (piecesPerChunk, chunkRootsSize uint64) {
+	// Special case: if the data of the file is using 1-of-N erasure coding,
+	// each piece will be identical, so the fanout will only have encoded a
+	// single piece for each chunk.
+	if sl.FanoutDataPieces == 1 && sl.CipherType == crypto.TypePlain {
+		return 1, crypto.HashSize
+	}
+	// This is the case where the file data is not 1-of-N. Every piece is
+	// different, so every piece must get enumerated.
+	piecesPerChunk = uint64(sl.FanoutDataPieces) + uint64(sl.FanoutParityPieces)
+	return piecesPerChunk, crypto.HashSize * piecesPerChunk
+}
+
+// fanoutNumChunks returns the number of chunks that are described by the
+// fanout of the skyfile with the given layout.
+func fanoutNumChunks(sl SkyfileLayout) uint64 {
+	_, chunkRootsSize := fanoutChunkRootsSize(sl)
+	if chunkRootsSize == 0 {
+		return 0
+	}
+	return sl.FanoutSize / chunkRootsSize
+}
+
+// checkFanoutAndMetadataSize checks that neither the fanout nor the metadata
+// of the skyfile with the given layout exceed a sector.
+func checkFanoutAndMetadataSize(sl SkyfileLayout) error {
+	if sl.FanoutSize > modules.SectorSize {
+		return errors.AddContext(ErrLargeFanoutAndMetadata, fmt.Sprintf("fanout of %v bytes for %v chunks exceeds the max fanout size of %v bytes", sl.FanoutSize, fanoutNumChunks(sl), modules.SectorSize))
+	}
+	if sl.MetadataSize > modules.SectorSize {
+		return errors.AddContext(ErrLargeFanoutAndMetadata, fmt.Sprintf("metadata of %v bytes exceeds the max metadata size of %v bytes", sl.MetadataSize, modules.SectorSize))
+	}
+	return nil
+}
+
 // DecryptBaseSector attempts to decrypt the baseSector. If it has the necessary
 // Skykey, it will decrypt the baseSector in-place.It returns the file-specific
 // skykey to be used for decrypting the rest of the associated skyfile.
@@ -254,8 +324,16 @@ func ParseSkyfileMetadata(baseSector []byte) (sl SkyfileLayout, fanoutBytes []by
 
 	// Currently there is no support for skyfiles with fanout + metadata that
 	// exceeds the base sector.
-	if offset+sl.FanoutSize+sl.MetadataSize > uint64(len(baseSector)) || sl.FanoutSize > modules.SectorSize || sl.MetadataSize > modules.SectorSize {
-		return SkyfileLayout{}, nil, SkyfileMetadata{}, nil, nil, errors.New("this version of siad does not support skyfiles with large fanouts and metadata")
+	if err := checkFanoutAndMetadataSize(sl); err != nil {
+		return SkyfileLayout{}, nil, SkyfileMetadata{}, nil, nil, err
+	}
+	var available uint64
+	if uint64(len(baseSector)) > offset {
+		available = uint64(len(baseSector)) - offset
+	}
+	if offset > uint64(len(baseSector)) || sl.FanoutSize+sl.MetadataSize > available {
+		err = errors.AddContext(ErrLargeFanoutAndMetadata, fmt.Sprintf("fanout of %v bytes for %v chunks and metadata of %v bytes exceed the %v bytes available in the base sector", sl.FanoutSize, fanoutNumChunks(sl), sl.MetadataSize, available))
+		return SkyfileLayout{}, nil, SkyfileMetadata{}, nil, nil, err
 	}
 
 	// Parse the fanout.
@@ -292,6 +370,64 @@ func ParseSkyfileMetadata(baseSector []byte) (sl SkyfileLayout, fanoutBytes []by
 	return sl, fanoutBytes, sm, rawSM, baseSectorPayload, nil
 }
 
+// ParseSkyfileLayoutAndMetadataOnly parses the layout and metadata of a
+// skyfile from the leading bytes of its base sector. Unlike
+// ParseSkyfileMetadata it doesn't require the full base sector, only the
+// layout, fanout and metadata region, which is SkyfileLayoutSize +
+// sl.FanoutSize + sl.MetadataSize bytes long. This allows for fetching a
+// minimal prefix of the base sector for metadata-only requests.
+//
+// NOTE: the base sector prefix is expected to be decrypted already.
+func ParseSkyfileLayoutAndMetadataOnly(firstBytes []byte) (SkyfileLayout, SkyfileMetadata, error) {
+	// Parse the layout.
+	if len(firstBytes) < SkyfileLayoutSize {
+		return SkyfileLayout{}, SkyfileMetadata{}, errors.AddContext(ErrBaseSectorPrefixTooShort, "unable to parse layout")
+	}
+	var sl SkyfileLayout
+	sl.Decode(firstBytes)
+	offset := uint64(SkyfileLayoutSize)
+
+	// Check the version.
+	if sl.Version != 1 {
+		return SkyfileLayout{}, SkyfileMetadata{}, fmt.Errorf("unsupported skyfile version %v", sl.Version)
+	}
+	if err := checkFanoutAndMetadataSize(sl); err != nil {
+		return SkyfileLayout{}, SkyfileMetadata{}, err
+	}
+
+	// Skip the fanout and check that the metadata is included.
+	offset += sl.FanoutSize
+	if offset+sl.MetadataSize > uint64(len(firstBytes)) {
+		return SkyfileLayout{}, SkyfileMetadata{}, errors.AddContext(ErrBaseSectorPrefixTooShort, fmt.Sprintf("need %v bytes but got %v", offset+sl.MetadataSize, len(firstBytes)))
+	}
+
+	// Parse the metadata.
+	var sm SkyfileMetadata
+	err := json.Unmarshal(firstBytes[offset:offset+sl.MetadataSize], &sm)
+	if err != nil {
+		err = errors.Compose(ErrMalformedBaseSector, err)
+		return SkyfileLayout{}, SkyfileMetadata{}, errors.AddContext(err, "unable to parse SkyfileMetadata from skyfile base sector")
+	}
+
+	// Make sure the returned metadata is valid.
+	if err := ValidateSkyfileMetadata(sm); err != nil {
+		return SkyfileLayout{}, SkyfileMetadata{}, err
+	}
+	return sl, sm, nil
+}
+
+// SkyfileContentLength returns the total number of bytes that will be served
+// when downloading the whole skyfile described by the given metadata. Legacy
+// skyfiles don't have their length set on the metadata, in which case the
+// length is the sum of the length of all subfiles.
+func SkyfileContentLength(sm SkyfileMetadata) uint64 {
+	legacyFile := len(sm.Subfiles) > 0 && sm.Length == 0
+	if legacyFile {
+		return sm.size()
+	}
+	return sm.Length
+}
+
 // SkyfileMetadataBytes will return the marshalled/encoded bytes for the
 // skyfile metadata.
 func SkyfileMetadataBytes(sm SkyfileMetadata) ([]byte, error) {
@@ -303,6 +439,25 @@ func SkyfileMetadataBytes(sm SkyfileMetadata) ([]byte, error) {
 	return metadataBytes, nil
 }
 
+// SubfileContentLength returns the number of bytes that will be served when
+// downloading the given path from the skyfile described by the given metadata.
+// The path can lead to both a file or a directory. The boolean indicates
+// whether the path exists within the skyfile.
+func SubfileContentLength(sm SkyfileMetadata, path string) (uint64, bool) {
+	// The root path of a skyfile without subfiles is the file itself.
+	if len(sm.Subfiles) == 0 {
+		if strings.Trim(path, "/") != "" {
+			return 0, false
+		}
+		return sm.Length, true
+	}
+	metadataForPath, _, _, size := sm.ForPath(path)
+	if len(metadataForPath.Subfiles) == 0 {
+		return 0, false
+	}
+	return size, true
+}
+
 // ValidateSkyfileMetadata validates the given SkyfileMetadata
 func ValidateSkyfileMetadata(metadata SkyfileMetadata) error {
 	// check filename
@@ -332,6 +487,11 @@ func ValidateSkyfileMetadata(metadata SkyfileMetadata) error {
 		if !legacyFile && metadata.Length != totalLength {
 			return fmt.Errorf("invalid length set on metadata - length: %v, totalLength: %v, subfiles: %v", metadata.Length, totalLength, len(metadata.Subfiles))
 		}
+		if !legacyFile {
+			if err := validateSubfileOffsets(metadata.Subfiles, metadata.Length); err != nil {
+				return err
+			}
+		}
 	}
 
 	if metadata.DisableDefaultPath && metadata.DefaultPath != "" {
@@ -356,6 +516,47 @@ func ValidateSkyfileMetadata(metadata SkyfileMetadata) error {
 	if err != nil {
 		return errors.AddContext(err, "metadata contains invalid errorpages configuration")
 	}
+
+	// check the declared content type
+	if metadata.DefaultContentType != "" {
+		_, _, err = mime.ParseMediaType(metadata.DefaultContentType)
+		if err != nil {
+			return errors.AddContext(err, fmt.Sprintf("invalid content type provided '%v'", metadata.DefaultContentType))
+		}
+	}
+
+	// immutable needs no validation, all other fields only reference content
+	// within the skyfile itself so none of them conflict with it
+	return nil
+}
+
+// validateSubfileOffsets checks that the ranges [Offset, Offset+Len) of the
+// given subfiles are disjoint and within [0, length).
+func validateSubfileOffsets(subfiles SkyfileSubfiles, length uint64) error {
+	sorted := make([]SkyfileSubfileMetadata, 0, len(subfiles))
+	for _, md := range subfiles {
+		sorted = append(sorted, md)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		if sorted[i].Len != sorted[j].Len {
+			return sorted[i].Len < sorted[j].Len
+		}
+		return sorted[i].Filename < sorted[j].Filename
+	})
+	var prev SkyfileSubfileMetadata
+	for i, md := range sorted {
+		end := md.Offset + md.Len
+		if end < md.Offset || end > length {
+			return errors.AddContext(ErrInvalidSubfileOffsets, fmt.Sprintf("subfile '%v' [%v, %v) exceeds the length of the skyfile %v", md.Filename, md.Offset, md.Offset+md.Len, length))
+		}
+		if i > 0 && md.Offset < prev.Offset+prev.Len {
+			return errors.AddContext(ErrInvalidSubfileOffsets, fmt.Sprintf("subfiles '%v' [%v, %v) and '%v' [%v, %v) overlap", prev.Filename, prev.Offset, prev.Offset+prev.Len, md.Filename, md.Offset, end))
+		}
+		prev = md
+	}
 	return nil
 }
 
@@ -377,21 +578,39 @@ func createFormFileHeaders(fieldname, filename, filemode, contentType string) (t
 // type cannot be determined by the file's extension, this function will read up
 // to 512 bytes from the provided reader.
 func fileContentType(filename string, file io.Reader) (string, error) {
+	return DetectFileContentType(filename, file, DefaultContentTypeSniffLen, nil)
+}
+
+// DetectFileContentType extracts the content type from a given file. If the
+// content type cannot be determined by the file's extension, this function
+// will read up to sniffLen bytes from the provided reader. These bytes are
+// passed to the optional detector first. If the detector is nil or can't
+// determine the content type, the first 512 bytes are passed to
+// http.DetectContentType.
+func DetectFileContentType(filename string, file io.Reader, sniffLen int, detector ContentTypeDetector) (string, error) {
 	contentType := mime.TypeByExtension(filepath.Ext(filename))
 	if contentType != "" {
 		return contentType, nil
 	}
-	// Only the first 512 bytes are used to sniff the content type. Ignore EOF
-	// so we properly fall back to the fallback defined in the http library for
-	// empty file uploads.
-	buffer := make([]byte, 512)
-	_, err := file.Read(buffer)
-	if err != nil && !errors.Contains(err, io.EOF) {
+	if sniffLen < DefaultContentTypeSniffLen {
+		sniffLen = DefaultContentTypeSniffLen
+	}
+	// Ignore EOF so we properly fall back to the fallback defined in the http
+	// library for empty file uploads.
+	buffer := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && !errors.Contains(err, io.EOF) && !errors.Contains(err, io.ErrUnexpectedEOF) {
 		return "", err
 	}
-	// Always returns a valid content-type by returning
+	if detector != nil {
+		if contentType := detector(buffer[:n]); contentType != "" {
+			return contentType, nil
+		}
+	}
+	// Only the first 512 bytes are used by the http library to sniff the
+	// content type. It always returns a valid content-type by returning
 	// "application/octet-stream" if no others seemed to match.
-	return http.DetectContentType(buffer), nil
+	return http.DetectContentType(buffer[:DefaultContentTypeSniffLen]), nil
 }
 
 // validateDefaultPath ensures the given default path makes sense in relation to
@@ -1,12 +1,15 @@
 package skymodules
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"strings"
 	"testing"
 
 	"gitlab.com/NebulousLabs/errors"
 	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/crypto"
 	"go.sia.tech/siad/modules"
 )
 
@@ -187,6 +190,20 @@ func testValidateSkyfileMetadata(t *testing.T) {
 		t.Fatal("unexpected outcome")
 	}
 
+	// verify the declared content type
+	withContentType := metadata
+	withContentType.DefaultContentType = "text/plain; charset=utf-8"
+	err = ValidateSkyfileMetadata(withContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid = metadata
+	invalid.DefaultContentType = "text/"
+	err = ValidateSkyfileMetadata(invalid)
+	if err == nil || !strings.Contains(err.Error(), "invalid content type provided") {
+		t.Fatal("unexpected outcome", err)
+	}
+
 	// verify that tryfiles + defaultpath is an invalid combination
 	invalid = metadata
 	metadata.Subfiles["index.html"] = SkyfileSubfileMetadata{
@@ -279,6 +296,47 @@ func testValidateSkyfileMetadata(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "invalid length set on metadata - length: 1, totalLength: 10, subfiles: 1") {
 		t.Fatal("unexpected outcome")
 	}
+
+	// verify overlapping subfiles
+	invalid = metadata
+	invalid.Subfiles = SkyfileSubfiles{
+		"a": SkyfileSubfileMetadata{
+			Filename: "a",
+			Len:      2,
+		},
+		"b": SkyfileSubfileMetadata{
+			Filename: "b",
+			Len:      2,
+			Offset:   1,
+		},
+	}
+	invalid.Length = 4
+	err = ValidateSkyfileMetadata(invalid)
+	if !errors.Contains(err, ErrInvalidSubfileOffsets) || !strings.Contains(err.Error(), "subfiles 'a' [0, 2) and 'b' [1, 3) overlap") {
+		t.Fatal("unexpected outcome", err)
+	}
+
+	// verify subfiles that exceed the length
+	invalid.Subfiles["b"] = SkyfileSubfileMetadata{
+		Filename: "b",
+		Len:      2,
+		Offset:   3,
+	}
+	err = ValidateSkyfileMetadata(invalid)
+	if !errors.Contains(err, ErrInvalidSubfileOffsets) || !strings.Contains(err.Error(), "subfile 'b' [3, 5) exceeds") {
+		t.Fatal("unexpected outcome", err)
+	}
+
+	// verify adjacent subfiles
+	invalid.Subfiles["b"] = SkyfileSubfileMetadata{
+		Filename: "b",
+		Len:      2,
+		Offset:   2,
+	}
+	err = ValidateSkyfileMetadata(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
 }
 
 // testEnsurePrefix ensures EnsurePrefix is properly adding prefixes.
@@ -378,6 +436,122 @@ func TestParseSkyfileMetadata(t *testing.T) {
 	}
 }
 
+// TestParseSkyfileLayoutAndMetadataOnly checks that the layout and metadata
+// can be parsed from a prefix of the base sector.
+func TestParseSkyfileLayoutAndMetadataOnly(t *testing.T) {
+	t.Parallel()
+
+	// Build a base sector.
+	sm := SkyfileMetadata{Filename: "file", Length: 100}
+	smBytes, err := SkyfileMetadataBytes(sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fanoutBytes := fastrand.Bytes(crypto.HashSize)
+	sl := newTestSkyfileLayout()
+	sl.Filesize = sm.Length
+	sl.FanoutSize = uint64(len(fanoutBytes))
+	sl.MetadataSize = uint64(len(smBytes))
+	baseSector, _ := BuildBaseSector(sl.Encode(), fanoutBytes, smBytes, nil)
+	prefixLen := SkyfileLayoutSize + len(fanoutBytes) + len(smBytes)
+
+	// Parsing the minimal prefix should work.
+	sl2, sm2, err := ParseSkyfileLayoutAndMetadataOnly(baseSector[:prefixLen])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sl2 != sl {
+		t.Fatal("layout mismatch", sl2, sl)
+	}
+	if sm2.Filename != sm.Filename || sm2.Length != sm.Length {
+		t.Fatal("metadata mismatch", sm2, sm)
+	}
+
+	// Parsing the full base sector should work too.
+	_, _, err = ParseSkyfileLayoutAndMetadataOnly(baseSector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A prefix that misses the end of the metadata or the layout should fail.
+	_, _, err = ParseSkyfileLayoutAndMetadataOnly(baseSector[:prefixLen-1])
+	if !errors.Contains(err, ErrBaseSectorPrefixTooShort) {
+		t.Fatal("unexpected error", err)
+	}
+	_, _, err = ParseSkyfileLayoutAndMetadataOnly(baseSector[:SkyfileLayoutSize-1])
+	if !errors.Contains(err, ErrBaseSectorPrefixTooShort) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// Overflowing sizes shouldn't cause a panic.
+	sl.FanoutSize = math.MaxUint64 - 14e3 - 1
+	copy(baseSector, sl.Encode())
+	_, _, err = ParseSkyfileLayoutAndMetadataOnly(baseSector)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestCanParseSkyfile is a unit test for CanParseSkyfile.
+func TestCanParseSkyfile(t *testing.T) {
+	t.Parallel()
+
+	// Build a valid base sector.
+	sm := SkyfileMetadata{Filename: "file", Length: 100}
+	smBytes, err := SkyfileMetadataBytes(sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fanoutBytes := fastrand.Bytes(crypto.HashSize)
+	sl := newTestSkyfileLayout()
+	sl.Filesize = sm.Length
+	sl.FanoutSize = uint64(len(fanoutBytes))
+	sl.MetadataSize = uint64(len(smBytes))
+	baseSector, _ := BuildBaseSector(sl.Encode(), fanoutBytes, smBytes, nil)
+	if ok, reason := CanParseSkyfile(baseSector); !ok {
+		t.Fatal("expected base sector to be parseable", reason)
+	}
+
+	// A fanout that exceeds a sector can't be parsed. The reason should
+	// contain the fanout size and the number of chunks.
+	sl.FanoutSize = modules.SectorSize + crypto.HashSize
+	copy(baseSector, sl.Encode())
+	ok, reason := CanParseSkyfile(baseSector)
+	if ok {
+		t.Fatal("expected large fanout to fail")
+	}
+	numChunks := sl.FanoutSize / crypto.HashSize
+	if !strings.Contains(reason, fmt.Sprintf("fanout of %v bytes for %v chunks", sl.FanoutSize, numChunks)) {
+		t.Fatal("unexpected reason", reason)
+	}
+	_, _, _, _, _, err = ParseSkyfileMetadata(baseSector)
+	if !errors.Contains(err, ErrLargeFanoutAndMetadata) {
+		t.Fatal("unexpected error", err)
+	}
+
+	// A fanout that doesn't fit into the base sector together with the
+	// metadata can't be parsed either.
+	sl.FanoutSize = modules.SectorSize - SkyfileLayoutSize
+	copy(baseSector, sl.Encode())
+	if ok, reason := CanParseSkyfile(baseSector); ok || !strings.Contains(reason, "bytes available in the base sector") {
+		t.Fatal("unexpected result", ok, reason)
+	}
+
+	// A base sector that is too short for the layout can't be parsed. The
+	// error shouldn't underflow the available bytes.
+	sl.FanoutSize = 0
+	sl.MetadataSize = 0
+	_, _, _, _, _, err = ParseSkyfileMetadata(sl.Encode()[:SkyfileLayoutSize/2])
+	if !errors.Contains(err, ErrLargeFanoutAndMetadata) || !strings.Contains(err.Error(), "exceed the 0 bytes available") {
+		t.Fatal("unexpected error", err)
+	}
+
+	// A base sector that is larger than a sector can't be parsed.
+	if ok, _ := CanParseSkyfile(make([]byte, modules.SectorSize+1)); ok {
+		t.Fatal("expected large base sector to fail")
+	}
+}
+
 // TestValidateErrorPages ensures that ValidateErrorPages functions correctly.
 func TestValidateErrorPages(t *testing.T) {
 	t.Parallel()
@@ -511,3 +685,153 @@ func TestValidateTryFiles(t *testing.T) {
 		}
 	}
 }
+
+// TestSkyfileContentLength ensures that SkyfileContentLength and
+// SubfileContentLength return the correct sizes, including for legacy files.
+func TestSkyfileContentLength(t *testing.T) {
+	t.Parallel()
+
+	subfiles := SkyfileSubfiles{
+		"index.html": SkyfileSubfileMetadata{
+			Filename: "index.html",
+			Offset:   0,
+			Len:      10,
+		},
+		"dir/a.txt": SkyfileSubfileMetadata{
+			Filename: "dir/a.txt",
+			Offset:   10,
+			Len:      20,
+		},
+		"dir/b.txt": SkyfileSubfileMetadata{
+			Filename: "dir/b.txt",
+			Offset:   30,
+			Len:      30,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		sm      SkyfileMetadata
+		path    string
+		total   uint64
+		sublen  uint64
+		subfind bool
+	}{
+		{
+			name:    "single file",
+			sm:      SkyfileMetadata{Filename: "file", Length: 100},
+			path:    "/",
+			total:   100,
+			sublen:  100,
+			subfind: true,
+		},
+		{
+			name:    "single file unknown path",
+			sm:      SkyfileMetadata{Filename: "file", Length: 100},
+			path:    "/file",
+			total:   100,
+			sublen:  0,
+			subfind: false,
+		},
+		{
+			name:    "multi file subfile",
+			sm:      SkyfileMetadata{Filename: "dir", Length: 60, Subfiles: subfiles},
+			path:    "/index.html",
+			total:   60,
+			sublen:  10,
+			subfind: true,
+		},
+		{
+			name:    "multi file directory",
+			sm:      SkyfileMetadata{Filename: "dir", Length: 60, Subfiles: subfiles},
+			path:    "/dir",
+			total:   60,
+			sublen:  50,
+			subfind: true,
+		},
+		{
+			name:    "multi file unknown path",
+			sm:      SkyfileMetadata{Filename: "dir", Length: 60, Subfiles: subfiles},
+			path:    "/unknown",
+			total:   60,
+			sublen:  0,
+			subfind: false,
+		},
+		{
+			name:    "legacy file",
+			sm:      SkyfileMetadata{Filename: "dir", Subfiles: subfiles},
+			path:    "/dir/b.txt",
+			total:   60,
+			sublen:  30,
+			subfind: true,
+		},
+	}
+
+	for _, test := range tests {
+		if total := SkyfileContentLength(test.sm); total != test.total {
+			t.Fatalf("%v: unexpected total length %v != %v", test.name, total, test.total)
+		}
+		sublen, found := SubfileContentLength(test.sm, test.path)
+		if found != test.subfind {
+			t.Fatalf("%v: unexpected found %v != %v", test.name, found, test.subfind)
+		}
+		if sublen != test.sublen {
+			t.Fatalf("%v: unexpected subfile length %v != %v", test.name, sublen, test.sublen)
+		}
+	}
+}
+
+// TestDetectFileContentType tests the DetectFileContentType function.
+func TestDetectFileContentType(t *testing.T) {
+	t.Parallel()
+
+	data := append(make([]byte, 1000), []byte("MAGIC")...)
+
+	// The extension should take precedence over the detector.
+	detector := func(b []byte) string {
+		if bytes.HasSuffix(b, []byte("MAGIC")) {
+			return "application/x-magic"
+		}
+		return ""
+	}
+	ct, err := DetectFileContentType("file.html", bytes.NewReader(data), 2048, detector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "text/html; charset=utf-8" {
+		t.Fatal("unexpected content type", ct)
+	}
+
+	// The detector should see bytes beyond the first 512.
+	ct, err = DetectFileContentType("file", bytes.NewReader(data), 2048, detector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "application/x-magic" {
+		t.Fatal("unexpected content type", ct)
+	}
+
+	// A sniff budget that is too small to contain the magic bytes should fall
+	// back to the http library.
+	ct, err = DetectFileContentType("file", bytes.NewReader(data), 600, detector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != "application/octet-stream" {
+		t.Fatal("unexpected content type", ct)
+	}
+
+	// Without a detector the result should match fileContentType.
+	pdf := []byte("%PDF-1.4")
+	ct, err = DetectFileContentType("file", bytes.NewReader(pdf), 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct2, err := fileContentType("file", bytes.NewReader(pdf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct != ct2 || ct != "application/pdf" {
+		t.Fatal("unexpected content types", ct, ct2)
+	}
+}